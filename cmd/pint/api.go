@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+//go:embed ui/index.html
+var uiIndex []byte
+
+type apiResponse struct {
+	Data   any    `json:"data,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type apiLines struct {
+	First int `json:"first"`
+	Last  int `json:"last"`
+}
+
+type apiDiagnostic struct {
+	Message     string `json:"message"`
+	FirstColumn int    `json:"firstColumn"`
+	LastColumn  int    `json:"lastColumn"`
+	IsContext   bool   `json:"isContext,omitempty"`
+}
+
+type apiProblem struct {
+	FirstSeen     time.Time       `json:"firstSeen"`
+	Fingerprint   string          `json:"fingerprint"`
+	Path          string          `json:"path"`
+	SymlinkTarget string          `json:"symlinkTarget"`
+	Owner         string          `json:"owner,omitempty"`
+	Name          string          `json:"name,omitempty"`
+	Kind          string          `json:"kind"`
+	Reporter      string          `json:"reporter"`
+	Summary       string          `json:"summary"`
+	Details       string          `json:"details,omitempty"`
+	Severity      string          `json:"severity"`
+	Console       string          `json:"console"`
+	Diagnostics   []apiDiagnostic `json:"diagnostics"`
+	Lines         apiLines        `json:"lines"`
+	Duplicates    int             `json:"duplicates,omitempty"`
+}
+
+type apiRule struct {
+	Path          string   `json:"path"`
+	SymlinkTarget string   `json:"symlinkTarget"`
+	Owner         string   `json:"owner,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Kind          string   `json:"kind"`
+	Error         string   `json:"error,omitempty"`
+	Lines         apiLines `json:"lines"`
+	Problems      int      `json:"problems"`
+}
+
+type apiServer struct {
+	DisabledChecks map[string][]string `json:"disabledChecks"`
+	Name           string              `json:"name"`
+	URI            string              `json:"uri,omitempty"`
+	Upstreams      []string            `json:"upstreams"`
+	Tags           []string            `json:"tags"`
+	Include        []string            `json:"include"`
+	Exclude        []string            `json:"exclude"`
+}
+
+// registerAPI adds HTTP JSON API and web UI handlers serving results
+// from the last run of all checks.
+func registerAPI(mux *http.ServeMux, c *problemCollector) {
+	mux.HandleFunc("/api/v1/problems", c.handleProblems)
+	mux.HandleFunc("/api/v1/rules", c.handleRules)
+	mux.HandleFunc("/api/v1/servers", c.handleServers)
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(uiIndex)
+	})
+}
+
+func (c *problemCollector) handleProblems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	severities := make([]checks.Severity, 0, len(query["severity"]))
+	for _, s := range query["severity"] {
+		sev, err := checks.ParseSeverity(s)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid severity value: %w", err))
+			return
+		}
+		severities = append(severities, sev)
+	}
+
+	c.lock.Lock()
+	var reports []reporter.Report
+	if c.summary != nil {
+		reports = c.summary.Reports()
+	}
+	c.lock.Unlock()
+
+	problems := []apiProblem{}
+	for _, report := range reports {
+		if report.Problem.Severity < c.minSeverity {
+			continue
+		}
+		if report.IsDuplicate && !c.showDuplicates {
+			continue
+		}
+		if !matchesFilter(query["path"], report.Path.Name, report.Path.SymlinkTarget) ||
+			!matchesFilter(query["owner"], report.Owner) ||
+			!matchesFilter(query["reporter"], report.Problem.Reporter) {
+			continue
+		}
+		if len(severities) > 0 && !slices.Contains(severities, report.Problem.Severity) {
+			continue
+		}
+		p := newAPIProblem(r.Context(), report, c.showDuplicates)
+		p.FirstSeen = c.history.firstSeen(p.Fingerprint)
+		problems = append(problems, p)
+	}
+
+	writeAPIData(w, problems)
+}
+
+func (c *problemCollector) handleRules(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	c.lock.Lock()
+	entries := c.entries
+	var reports []reporter.Report
+	if c.summary != nil {
+		reports = c.summary.Reports()
+	}
+	c.lock.Unlock()
+
+	rules := []apiRule{}
+	for _, entry := range entries {
+		if !matchesFilter(query["path"], entry.Path.Name, entry.Path.SymlinkTarget) ||
+			!matchesFilter(query["owner"], entry.Owner) {
+			continue
+		}
+		rules = append(rules, newAPIRule(entry, reports))
+	}
+
+	writeAPIData(w, rules)
+}
+
+func (c *problemCollector) handleServers(w http.ResponseWriter, _ *http.Request) {
+	c.lock.Lock()
+	servers := c.servers
+	c.lock.Unlock()
+
+	out := make([]apiServer, 0, len(servers))
+	for _, server := range servers {
+		out = append(out, apiServer{
+			Name:           server.Name(),
+			URI:            server.URI(),
+			Tags:           server.Tags(),
+			Include:        server.Include(),
+			Exclude:        server.Exclude(),
+			Upstreams:      server.Upstreams(),
+			DisabledChecks: server.GetDisabledChecks(),
+		})
+	}
+
+	writeAPIData(w, out)
+}
+
+func newAPIProblem(ctx context.Context, report reporter.Report, showDuplicates bool) apiProblem {
+	// Render the same output as pint lint would print for this problem.
+	var console strings.Builder
+	_ = reporter.NewConsoleReporter(&console, checks.Information, true, true).Submit(ctx, reporter.NewSummary([]reporter.Report{report}))
+
+	diagnostics := make([]apiDiagnostic, 0, len(report.Problem.Diagnostics))
+	for _, diag := range report.Problem.Diagnostics {
+		diagnostics = append(diagnostics, apiDiagnostic{
+			Message:     diag.Message,
+			FirstColumn: diag.FirstColumn,
+			LastColumn:  diag.LastColumn,
+			IsContext:   diag.Kind == diags.Context,
+		})
+	}
+
+	p := apiProblem{
+		FirstSeen:     time.Time{},
+		Fingerprint:   problemFingerprint(report),
+		Path:          report.Path.Name,
+		SymlinkTarget: report.Path.SymlinkTarget,
+		Owner:         report.Owner,
+		Name:          report.Rule.Name(),
+		Kind:          string(report.Rule.Type()),
+		Reporter:      report.Problem.Reporter,
+		Summary:       report.Problem.Summary,
+		Details:       report.Problem.Details,
+		Severity:      apiSeverity(report.Problem.Severity),
+		Console:       strings.TrimRight(console.String(), "\n"),
+		Diagnostics:   diagnostics,
+		Lines:         apiLines{First: report.Problem.Lines.First, Last: report.Problem.Lines.Last},
+		Duplicates:    0,
+	}
+	if !showDuplicates {
+		p.Duplicates = len(report.Duplicates)
+	}
+	return p
+}
+
+// apiSeverity returns the severity name accepted by the severity query parameter.
+func apiSeverity(s checks.Severity) string {
+	if s == checks.Information {
+		return "info"
+	}
+	return strings.ToLower(s.String())
+}
+
+func newAPIRule(entry *discovery.Entry, reports []reporter.Report) apiRule {
+	rule := apiRule{
+		Path:          entry.Path.Name,
+		SymlinkTarget: entry.Path.SymlinkTarget,
+		Owner:         entry.Owner,
+		Name:          entry.Rule.Name(),
+		Kind:          string(entry.Rule.Type()),
+		Lines:         apiLines{First: entry.Rule.Lines.First, Last: entry.Rule.Lines.Last},
+		Problems:      0,
+	}
+	switch {
+	case entry.PathError != nil:
+		rule.Error = entry.PathError.Error()
+	case entry.Rule.Error.Err != nil:
+		rule.Error = entry.Rule.Error.Err.Error()
+	}
+	for _, report := range reports {
+		if report.Path.Name == entry.Path.Name && report.Rule.Lines == entry.Rule.Lines {
+			rule.Problems++
+		}
+	}
+	return rule
+}
+
+// matchesFilter returns true if there are no filter values or if any of the values
+// is equal to any of given fields.
+func matchesFilter(values []string, fields ...string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, field := range fields {
+		if slices.Contains(values, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeAPIData(w http.ResponseWriter, data any) {
+	writeAPIResponse(w, http.StatusOK, apiResponse{Status: "success", Data: data, Error: ""})
+}
+
+func writeAPIError(w http.ResponseWriter, code int, err error) {
+	writeAPIResponse(w, code, apiResponse{Status: "error", Data: nil, Error: err.Error()})
+}
+
+func writeAPIResponse(w http.ResponseWriter, code int, resp apiResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(resp); err != nil {
+		slog.LogAttrs(context.Background(), slog.LevelError, "Failed to write API response", slog.Any("err", err))
+	}
+}
@@ -115,6 +115,15 @@ func actionCI(ctx context.Context, c *cli.Command) error {
 		return err
 	}
 
+	rendered, err := discovery.NewRenderFinder(meta.cfg.Parser.Renderers(), discovery.RunRenderCommand, meta.cfg.Parser.Options(), allowedOwners).Find(ctx)
+	if err != nil {
+		return err
+	}
+	if err = discovery.MarkRenderedChanges(ctx, git.RunGit, baseBranch, rendered); err != nil {
+		return err
+	}
+	entries = append(entries, rendered...)
+
 	ctx = context.WithValue(ctx, config.CommandKey, config.CICommand)
 
 	gen := config.NewPrometheusGenerator(meta.cfg, metricsRegistry)
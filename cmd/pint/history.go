@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+const webhookTimeout = time.Minute
+
+// historyProblem is a single problem tracked across multiple check iterations.
+type historyProblem struct {
+	FirstSeen   time.Time `json:"firstSeen"`
+	Fingerprint string    `json:"fingerprint"`
+	Path        string    `json:"path"`
+	Owner       string    `json:"owner,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Kind        string    `json:"kind"`
+	Reporter    string    `json:"reporter"`
+	Summary     string    `json:"summary"`
+	Severity    string    `json:"severity"`
+}
+
+func newHistoryProblem(report reporter.Report) historyProblem {
+	return historyProblem{
+		FirstSeen:   time.Time{},
+		Fingerprint: problemFingerprint(report),
+		Path:        report.Path.Name,
+		Owner:       report.Owner,
+		Name:        report.Rule.Name(),
+		Kind:        string(report.Rule.Type()),
+		Reporter:    report.Problem.Reporter,
+		Summary:     report.Problem.Summary,
+		Severity:    strings.ToLower(report.Problem.Severity.String()),
+	}
+}
+
+// problemFingerprint returns a unique identifier of a problem.
+// Line numbers are not part of the fingerprint, so it doesn't change
+// when unrelated rules are added or removed in the same file.
+// Summary and severity are also not part of it, since these can change
+// between pint versions or config reloads while it's still the same problem.
+// Instead we use the position of each diagnostic within the rule.
+func problemFingerprint(report reporter.Report) string {
+	h := xxhash.New()
+	for _, v := range []string{
+		report.Path.Name,
+		string(report.Rule.Type()),
+		report.Rule.Name(),
+		report.Problem.Reporter,
+		strconv.Itoa(int(report.Problem.Anchor)),
+	} {
+		_, _ = h.WriteString(v)
+		_, _ = h.WriteString("\n")
+	}
+	for _, diag := range report.Problem.Diagnostics {
+		_, _ = h.WriteString(strconv.Itoa(diag.FirstColumn))
+		_, _ = h.WriteString(":")
+		_, _ = h.WriteString(strconv.Itoa(diag.LastColumn))
+		_, _ = h.WriteString("\n")
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+type historyFile struct {
+	Problems []historyProblem `json:"problems"`
+}
+
+// problemHistory tracks when each problem was first reported.
+// Currently reported problems are tracked separately from recorded ones,
+// which are only updated after all changes were successfully sent to
+// the webhook, so we never lose any notification.
+type problemHistory struct {
+	problems map[string]historyProblem
+	recorded map[string]historyProblem
+	path     string
+	isReady  bool
+	lock     sync.Mutex
+}
+
+// newProblemHistory creates a new history, if path is set then history
+// will be loaded from that file if it exists and saved to it after every update.
+func newProblemHistory(path string) (*problemHistory, error) {
+	h := problemHistory{ // nolint: exhaustruct
+		problems: map[string]historyProblem{},
+		recorded: map[string]historyProblem{},
+		path:     path,
+	}
+	if path == "" {
+		return &h, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var hf historyFile
+	if err = json.Unmarshal(data, &hf); err != nil {
+		return nil, fmt.Errorf("failed to parse history file %q: %w", path, err)
+	}
+	for _, p := range hf.Problems {
+		h.recorded[p.Fingerprint] = p
+	}
+	h.problems = maps.Clone(h.recorded)
+	h.isReady = true
+	slog.LogAttrs(context.Background(), slog.LevelInfo, "Loaded problem history", slog.String("path", path), slog.Int("problems", len(h.recorded)))
+	return &h, nil
+}
+
+// observe replaces the list of currently reported problems and returns them
+// with FirstSeen set, keyed by the fingerprint.
+func (h *problemHistory) observe(problems []historyProblem, now time.Time) map[string]historyProblem {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	current := make(map[string]historyProblem, len(problems))
+	for _, p := range problems {
+		if _, ok := current[p.Fingerprint]; ok {
+			continue
+		}
+		if old, ok := h.problems[p.Fingerprint]; ok {
+			p.FirstSeen = old.FirstSeen
+		} else if old, ok := h.recorded[p.Fingerprint]; ok {
+			p.FirstSeen = old.FirstSeen
+		} else {
+			p.FirstSeen = now
+		}
+		current[p.Fingerprint] = p
+	}
+	h.problems = current
+	return maps.Clone(current)
+}
+
+// changes returns all problems that were not recorded before and all recorded
+// problems that are no longer reported.
+// Nothing is returned until the first record if there was no history file to load,
+// since we don't know which problems are new.
+func (h *problemHistory) changes(current map[string]historyProblem) (added, resolved []historyProblem) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if !h.isReady {
+		return nil, nil
+	}
+	for fp, p := range current {
+		if _, ok := h.recorded[fp]; !ok {
+			added = append(added, p)
+		}
+	}
+	for fp, p := range h.recorded {
+		if _, ok := current[fp]; !ok {
+			resolved = append(resolved, p)
+		}
+	}
+	sortHistoryProblems(added)
+	sortHistoryProblems(resolved)
+	return added, resolved
+}
+
+// record replaces recorded problems with the current ones.
+func (h *problemHistory) record(current map[string]historyProblem) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.recorded = current
+	h.isReady = true
+}
+
+// firstSeen returns the time when the problem with given fingerprint was first reported.
+func (h *problemHistory) firstSeen(fingerprint string) time.Time {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.problems[fingerprint].FirstSeen
+}
+
+// save writes recorded problems to the history file, it's a no-op if no file path was set.
+func (h *problemHistory) save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	h.lock.Lock()
+	hf := historyFile{Problems: slices.Collect(maps.Values(h.recorded))}
+	h.lock.Unlock()
+	sortHistoryProblems(hf.Problems)
+
+	data, err := json.MarshalIndent(hf, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so we never leave a partially written history file.
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), h.path)
+}
+
+func sortHistoryProblems(problems []historyProblem) {
+	slices.SortFunc(problems, func(a, b historyProblem) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Reporter, b.Reporter); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Fingerprint, b.Fingerprint)
+	})
+}
+
+type webhookPayload struct {
+	New      []historyProblem `json:"new"`
+	Resolved []historyProblem `json:"resolved"`
+}
+
+// webhookNotifier sends a POST request with JSON body every time there are
+// new or resolved problems.
+type webhookNotifier struct {
+	uri    string
+	client http.Client
+}
+
+func newWebhookNotifier(uri string) *webhookNotifier {
+	return &webhookNotifier{
+		uri:    uri,
+		client: http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Notify sends new and resolved problems to the webhook.
+// It's safe to call on a nil notifier, it will do nothing.
+func (wn *webhookNotifier) Notify(ctx context.Context, added, resolved []historyProblem) error {
+	if wn == nil || (len(added) == 0 && len(resolved) == 0) {
+		return nil
+	}
+
+	payload := webhookPayload{New: added, Resolved: resolved}
+	if payload.New == nil {
+		payload.New = []historyProblem{}
+	}
+	if payload.Resolved == nil {
+		payload.Resolved = []historyProblem{}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.uri, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook request failed with %s", resp.Status)
+	}
+
+	slog.LogAttrs(ctx, slog.LevelInfo, "Webhook notification sent", slog.Int("new", len(added)), slog.Int("resolved", len(resolved)))
+	return nil
+}
+
+// historyRecorder sends notifications and records problem history in the background,
+// so a slow webhook never blocks running checks.
+// If a notification fails the history is left unchanged and all changes will be
+// sent again with the next update.
+type historyRecorder struct {
+	history *problemHistory
+	webhook *webhookNotifier
+	queue   chan map[string]historyProblem
+	done    chan struct{}
+}
+
+func newHistoryRecorder(history *problemHistory, webhook *webhookNotifier) *historyRecorder {
+	return &historyRecorder{
+		history: history,
+		webhook: webhook,
+		queue:   make(chan map[string]historyProblem, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// Run processes queued updates until Close is called.
+func (hr *historyRecorder) Run(ctx context.Context) {
+	defer close(hr.done)
+	for current := range hr.queue {
+		hr.process(ctx, current)
+	}
+}
+
+// Update queues currently reported problems, replacing any update that wasn't
+// processed yet, since only the most recent one matters.
+// It must not be called from multiple goroutines.
+func (hr *historyRecorder) Update(current map[string]historyProblem) {
+	select {
+	case <-hr.queue:
+	default:
+	}
+	hr.queue <- current
+}
+
+// Close waits for all queued updates to be processed and stops Run.
+func (hr *historyRecorder) Close() {
+	close(hr.queue)
+	<-hr.done
+}
+
+func (hr *historyRecorder) process(ctx context.Context, current map[string]historyProblem) {
+	added, resolved := hr.history.changes(current)
+	if err := hr.webhook.Notify(ctx, added, resolved); err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to send webhook notification", slog.Any("err", err))
+		return
+	}
+	hr.history.record(current)
+	if err := hr.history.save(); err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to save problem history", slog.Any("err", err))
+	}
+}
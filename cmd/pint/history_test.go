@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+func TestProblemHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	foo := historyProblem{Fingerprint: "foo", Path: "rules.yml", Name: "foo"} // nolint: exhaustruct
+	bar := historyProblem{Fingerprint: "bar", Path: "rules.yml", Name: "bar"} // nolint: exhaustruct
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	h, err := newProblemHistory(path)
+	require.NoError(t, err)
+
+	// First update without any history file doesn't report anything as new.
+	current := h.observe([]historyProblem{foo}, t1)
+	added, resolved := h.changes(current)
+	require.Empty(t, added)
+	require.Empty(t, resolved)
+	require.Equal(t, t1, h.firstSeen("foo"))
+	h.record(current)
+	require.NoError(t, h.save())
+
+	current = h.observe([]historyProblem{foo, bar}, t2)
+	added, resolved = h.changes(current)
+	require.Len(t, added, 1)
+	require.Equal(t, "bar", added[0].Fingerprint)
+	require.Equal(t, t2, added[0].FirstSeen)
+	require.Empty(t, resolved)
+	require.Equal(t, t1, h.firstSeen("foo"))
+	require.Equal(t, t2, h.firstSeen("bar"))
+
+	// Changes that were not recorded are reported again.
+	added, resolved = h.changes(h.observe([]historyProblem{foo, bar}, t2.Add(time.Hour)))
+	require.Len(t, added, 1)
+	require.Equal(t, "bar", added[0].Fingerprint)
+	require.Equal(t, t2, added[0].FirstSeen)
+	require.Empty(t, resolved)
+
+	// Loaded history only has foo, so bar is new and foo is kept.
+	h, err = newProblemHistory(path)
+	require.NoError(t, err)
+	require.Equal(t, t1, h.firstSeen("foo"))
+
+	added, resolved = h.changes(h.observe([]historyProblem{bar}, t2))
+	require.Len(t, added, 1)
+	require.Equal(t, "bar", added[0].Fingerprint)
+	require.Len(t, resolved, 1)
+	require.Equal(t, "foo", resolved[0].Fingerprint)
+	require.Equal(t, t1, resolved[0].FirstSeen)
+	require.True(t, h.firstSeen("foo").IsZero())
+}
+
+func TestHistoryRecorder(t *testing.T) {
+	var lock sync.Mutex
+	var payloads []webhookPayload
+	status := http.StatusInternalServerError
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		lock.Lock()
+		payloads = append(payloads, payload)
+		w.WriteHeader(status)
+		lock.Unlock()
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "history.json")
+	foo := historyProblem{Fingerprint: "foo", Path: "rules.yml", Name: "foo"} // nolint: exhaustruct
+	bar := historyProblem{Fingerprint: "bar", Path: "rules.yml", Name: "bar"} // nolint: exhaustruct
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	h, err := newProblemHistory(path)
+	require.NoError(t, err)
+	h.record(h.observe([]historyProblem{foo}, t1))
+	require.NoError(t, h.save())
+
+	// Webhook fails so history is not recorded.
+	hr := newHistoryRecorder(h, newWebhookNotifier(srv.URL))
+	go hr.Run(context.Background())
+	hr.Update(h.observe([]historyProblem{foo, bar}, t1))
+	hr.Close()
+
+	h, err = newProblemHistory(path)
+	require.NoError(t, err)
+	require.True(t, h.firstSeen("bar").IsZero())
+
+	// Webhook works so the same change is sent again and recorded.
+	lock.Lock()
+	status = http.StatusOK
+	lock.Unlock()
+	hr = newHistoryRecorder(h, newWebhookNotifier(srv.URL))
+	go hr.Run(context.Background())
+	hr.Update(h.observe([]historyProblem{foo, bar}, t1))
+	hr.Close()
+
+	h, err = newProblemHistory(path)
+	require.NoError(t, err)
+	require.Equal(t, t1, h.firstSeen("bar"))
+
+	require.Len(t, payloads, 2)
+	for _, payload := range payloads {
+		require.Len(t, payload.New, 1)
+		require.Equal(t, "bar", payload.New[0].Fingerprint)
+		require.Empty(t, payload.Resolved)
+	}
+}
+
+func TestProblemFingerprint(t *testing.T) {
+	newReport := func(summary string, severity checks.Severity, line, firstColumn int) reporter.Report {
+		return reporter.Report{ // nolint: exhaustruct
+			Path: discovery.Path{Name: "rules.yml", SymlinkTarget: "rules.yml"},
+			Rule: parser.Rule{}, // nolint: exhaustruct
+			Problem: checks.Problem{ // nolint: exhaustruct
+				Reporter: "promql/series",
+				Summary:  summary,
+				Severity: severity,
+				Lines:    diags.LineRange{First: line, Last: line},
+				Diagnostics: []diags.Diagnostic{
+					{FirstColumn: firstColumn, LastColumn: 10}, // nolint: exhaustruct
+				},
+			},
+		}
+	}
+
+	fp := problemFingerprint(newReport("foo", checks.Bug, 1, 1))
+	require.Equal(t, fp, problemFingerprint(newReport("bar", checks.Warning, 5, 1)))
+	require.NotEqual(t, fp, problemFingerprint(newReport("foo", checks.Bug, 1, 2)))
+}
@@ -92,6 +92,17 @@ func actionLint(ctx context.Context, c *cli.Command) error {
 		return err
 	}
 
+	rendered, err := discovery.NewRenderFinder(
+		meta.cfg.Parser.Renderers(),
+		discovery.RunRenderCommand,
+		meta.cfg.Parser.Options(),
+		allowedOwners,
+	).Find(ctx)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, rendered...)
+
 	ctx = context.WithValue(ctx, config.CommandKey, config.LintCommand)
 
 	gen := config.NewPrometheusGenerator(meta.cfg, metricsRegistry)
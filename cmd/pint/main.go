@@ -107,28 +107,39 @@ func actionSetup(c *cli.Command) (meta actionMeta, err error) {
 		return meta, fmt.Errorf("--%s flag must be > 0", workersFlag)
 	}
 
+	meta.cfg, err = loadConfig(c)
+	if err != nil {
+		return meta, err
+	}
+	meta.isOffline = c.Bool(offlineFlag)
+
+	return meta, nil
+}
+
+// loadConfig reads and validates pint config file, applying all command line
+// flags that modify it.
+func loadConfig(c *cli.Command) (cfg config.Config, err error) {
 	var fromFile bool
-	meta.cfg, fromFile, err = config.Load(c.String(configFlag), c.IsSet(configFlag))
+	cfg, fromFile, err = config.Load(c.String(configFlag), c.IsSet(configFlag))
 	if err != nil {
-		return meta, fmt.Errorf("failed to load config file %q: %w", c.String(configFlag), err)
+		return cfg, fmt.Errorf("failed to load config file %q: %w", c.String(configFlag), err)
 	}
 	if fromFile {
 		slog.LogAttrs(context.Background(), slog.LevelDebug, "Adding pint config to the parser exclude list", slog.String("path", c.String(configFlag)))
-		meta.cfg.Parser.Exclude = append(meta.cfg.Parser.Exclude, c.String(configFlag))
+		cfg.Parser.Exclude = append(cfg.Parser.Exclude, c.String(configFlag))
 	}
 
-	meta.cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
+	cfg.SetDisabledChecks(c.StringSlice(disabledFlag))
 	enabled := c.StringSlice(enabledFlag)
 	if len(enabled) > 0 {
-		meta.cfg.Checks.Enabled = enabled
+		cfg.Checks.Enabled = enabled
 	}
 
 	if c.Bool(offlineFlag) {
-		meta.isOffline = true
-		meta.cfg.DisableOnlineChecks()
+		cfg.DisableOnlineChecks()
 	}
 
-	return meta, nil
+	return cfg, nil
 }
 
 func main() {
@@ -402,6 +402,9 @@ func sanitizePayload(payload []byte) []byte {
 	commitIDRe := regexp.MustCompile(`"commit_id":"([0-9a-zA-Z]{40})"`)
 	payload = commitIDRe.ReplaceAll(payload, []byte(`"commit_id":"<COMMIT ID>"`))
 
+	firstSeenRe := regexp.MustCompile(`"firstSeen":"([^"]+)"`)
+	payload = firstSeenRe.ReplaceAll(payload, []byte(`"firstSeen":"<TIMESTAMP>"`))
+
 	var node yaml.Node
 	if err := yaml.Unmarshal(payload, &node); err != nil {
 		return payload
@@ -56,4 +56,28 @@ var (
 		},
 		[]string{"kind"},
 	)
+	configReloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pint_config_reloads_total",
+			Help: "Total number of config file reloads since startup.",
+		},
+		[]string{"result"},
+	)
+	configLastReloadSuccessful = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pint_config_last_reload_successful",
+			Help: "Whether the last config file reload was successful.",
+		},
+	)
+	configLastReloadSuccessTime = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pint_config_last_reload_success_timestamp_seconds",
+			Help: "Timestamp of the last successful config file reload since unix epoch in seconds.",
+		},
+	)
+)
+
+const (
+	reloadSuccess = "success"
+	reloadFailure = "failure"
 )
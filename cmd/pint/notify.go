@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// notifyDelay is how long we wait after the last file event before
+// re-checking modified files, editors often emit multiple events per save.
+const notifyDelay = time.Millisecond * 500
+
+type fileNotifier struct {
+	finder  pathFinderFunc
+	watcher *fsnotify.Watcher
+	changes chan []string
+	watched map[string]struct{}
+	roots   []string
+	paths   []string
+	lock    sync.Mutex
+}
+
+func newFileNotifier(f pathFinderFunc) (*fileNotifier, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return &fileNotifier{ // nolint: exhaustruct
+		finder:  f,
+		watcher: watcher,
+		changes: make(chan []string),
+		watched: map[string]struct{}{},
+	}, nil
+}
+
+// Changes returns a channel that will receive the list of modified files.
+// It's safe to call on a nil notifier, the returned channel will never receive anything.
+func (fn *fileNotifier) Changes() <-chan []string {
+	if fn == nil {
+		return nil
+	}
+	return fn.changes
+}
+
+// Refresh will add all directories with files returned by the path finder to the watch list.
+func (fn *fileNotifier) Refresh(ctx context.Context) {
+	if fn == nil {
+		return
+	}
+
+	paths, err := fn.finder(ctx)
+	if err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to get the list of paths to watch", slog.Any("err", err))
+		return
+	}
+
+	var roots []string
+	for _, pattern := range paths {
+		// Watch the parent directory so we can see newly created files.
+		parents, _ := filepath.Glob(filepath.Dir(pattern))
+		for _, dir := range parents {
+			fn.addDir(ctx, dir)
+		}
+
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			if isDirectory(path) {
+				roots = append(roots, filepath.Clean(path))
+				fn.addTree(ctx, path)
+			}
+		}
+	}
+
+	fn.lock.Lock()
+	fn.paths = paths
+	fn.roots = roots
+	fn.lock.Unlock()
+}
+
+// Run will process all file events until the context is cancelled.
+func (fn *fileNotifier) Run(ctx context.Context) {
+	timer := time.NewTimer(notifyDelay)
+	timer.Stop()
+
+	pending := map[string]struct{}{}
+	// Paths ready to be sent, only set once there were no new events for notifyDelay.
+	var ready []string
+	for {
+		var out chan []string
+		if len(ready) > 0 {
+			out = fn.changes
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-fn.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			path := filepath.Clean(event.Name)
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				fn.forget(path)
+			}
+			if event.Has(fsnotify.Create) && isDirectory(path) && fn.isWatched(path) {
+				fn.addTree(ctx, path)
+				for _, file := range listFiles(path) {
+					pending[file] = struct{}{}
+				}
+				ready = nil
+				timer.Reset(notifyDelay)
+				continue
+			}
+			if !fn.isWatched(path) {
+				continue
+			}
+			slog.LogAttrs(ctx, slog.LevelDebug, "File modified", slog.String("path", path), slog.String("op", event.Op.String()))
+			pending[path] = struct{}{}
+			ready = nil
+			timer.Reset(notifyDelay)
+		case err, ok := <-fn.watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.LogAttrs(ctx, slog.LevelError, "File watcher returned an error", slog.Any("err", err))
+		case <-timer.C:
+			ready = sortedPaths(pending)
+		case out <- ready:
+			pending = map[string]struct{}{}
+			ready = nil
+		}
+	}
+}
+
+func (fn *fileNotifier) Close() error {
+	if fn == nil {
+		return nil
+	}
+	return fn.watcher.Close()
+}
+
+// isWatched returns true if given path is matched by any of the paths
+// returned by the finder or is inside any of matched directories.
+func (fn *fileNotifier) isWatched(path string) bool {
+	fn.lock.Lock()
+	defer fn.lock.Unlock()
+
+	for _, pattern := range fn.paths {
+		if ok, _ := filepath.Match(filepath.Clean(pattern), path); ok {
+			return true
+		}
+	}
+	for _, root := range fn.roots {
+		if strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (fn *fileNotifier) addTree(ctx context.Context, root string) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			fn.addDir(ctx, path)
+		}
+		return nil
+	})
+}
+
+func (fn *fileNotifier) addDir(ctx context.Context, dir string) {
+	dir = filepath.Clean(dir)
+
+	fn.lock.Lock()
+	defer fn.lock.Unlock()
+
+	if _, ok := fn.watched[dir]; ok {
+		return
+	}
+	if err := fn.watcher.Add(dir); err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to watch directory", slog.String("path", dir), slog.Any("err", err))
+		return
+	}
+	fn.watched[dir] = struct{}{}
+	slog.LogAttrs(ctx, slog.LevelDebug, "Watching directory for changes", slog.String("path", dir))
+}
+
+// forget removes a deleted directory from the list of watched directories,
+// the watch itself is removed automatically when the directory is deleted.
+func (fn *fileNotifier) forget(dir string) {
+	fn.lock.Lock()
+	defer fn.lock.Unlock()
+	delete(fn.watched, dir)
+}
+
+// watchConfigFile will request a config reload every time given file is modified.
+// The parent directory is watched rather than the file itself, so we can follow
+// editors and tools that replace the file instead of writing to it.
+func watchConfigFile(ctx context.Context, path string, reload chan<- struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	path = filepath.Clean(path)
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		timer := time.NewTimer(notifyDelay)
+		timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op == fsnotify.Chmod || filepath.Clean(event.Name) != path {
+					continue
+				}
+				slog.LogAttrs(ctx, slog.LevelDebug, "Config file modified", slog.String("path", path), slog.String("op", event.Op.String()))
+				timer.Reset(notifyDelay)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.LogAttrs(ctx, slog.LevelError, "Config file watcher returned an error", slog.Any("err", err))
+			case <-timer.C:
+				slog.LogAttrs(ctx, slog.LevelInfo, "Config file modified, reloading configuration", slog.String("path", path))
+				requestReload(reload)
+			}
+		}
+	}()
+	return nil
+}
+
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.IsDir()
+}
+
+func listFiles(root string) (files []string) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, filepath.Clean(path))
+		}
+		return nil
+	})
+	return files
+}
+
+func sortedPaths(m map[string]struct{}) []string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
@@ -16,6 +16,12 @@ import (
 
 func checkRules(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator, cfg config.Config, entries []*discovery.Entry) (summary reporter.Summary, err error) {
 	slog.LogAttrs(ctx, slog.LevelInfo, "Checking Prometheus rules", slog.Int("entries", len(entries)), slog.Int("workers", workers), slog.Bool("online", !isOffline))
+	return checkSelectedRules(ctx, workers, isOffline, gen, cfg, entries, entries)
+}
+
+// checkSelectedRules runs checks only for selected entries, all entries are
+// still passed to each check, so it can compare rules against each other.
+func checkSelectedRules(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator, cfg config.Config, entries, selected []*discovery.Entry) (summary reporter.Summary, err error) {
 	if isOffline {
 		slog.LogAttrs(ctx, slog.LevelInfo, "Offline mode, skipping Prometheus discovery")
 	} else {
@@ -50,7 +56,7 @@ func checkRules(ctx context.Context, workers int, isOffline bool, gen *config.Pr
 	}
 
 	var onlineChecksCount, offlineChecksCount, checkedEntriesCount atomic.Int64
-	for _, entry := range entries {
+	for _, entry := range selected {
 		switch {
 		case entry.PathError != nil && entry.State == discovery.Removed:
 			continue
@@ -159,6 +165,15 @@ func runCheck(ctx context.Context, check checks.RuleChecker, entry *discovery.En
 		)
 	}
 
+	if entry.Group != nil && len(entry.Group.SourceTenants) > 0 {
+		// Rules in groups with source_tenants are evaluated using data from all source tenants.
+		tenants := make([]string, 0, len(entry.Group.SourceTenants))
+		for _, tenant := range entry.Group.SourceTenants {
+			tenants = append(tenants, tenant.Value)
+		}
+		ctx = promapi.WithTenants(ctx, tenants)
+	}
+
 	start := time.Now()
 	problems := check.Check(ctx, entry, entries)
 	checkDuration.WithLabelValues(check.Reporter()).Observe(time.Since(start).Seconds())
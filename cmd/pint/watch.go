@@ -10,7 +10,7 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
-	"regexp"
+	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
@@ -22,7 +22,6 @@ import (
 	"github.com/cloudflare/pint/internal/diags"
 	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/git"
-	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/promapi"
 	"github.com/cloudflare/pint/internal/reporter"
 
@@ -39,6 +38,10 @@ const (
 	pidfileFlag     = "pidfile"
 	maxProblemsFlag = "max-problems"
 	minSeverityFlag = "min-severity"
+	fsnotifyFlag    = "fsnotify"
+	watchConfigFlag = "watch-config"
+	historyFileFlag = "history-file"
+	webhookURLFlag  = "webhook-url"
 )
 
 var watchCmd = &cli.Command{
@@ -129,6 +132,24 @@ var watchCmd = &cli.Command{
 			Value:   strings.ToLower(checks.Bug.String()),
 			Usage:   "Set minimum severity for problems reported via metrics.",
 		},
+		&cli.BoolFlag{
+			Name:  fsnotifyFlag,
+			Value: false,
+			Usage: "Watch files for changes and re-check modified rules as soon as they are saved.",
+		},
+		&cli.BoolFlag{
+			Name:  watchConfigFlag,
+			Value: false,
+			Usage: "Reload pint config file every time it's modified.",
+		},
+		&cli.StringFlag{
+			Name:  historyFileFlag,
+			Usage: "Save the history of all reported problems to this file so it's preserved across restarts.",
+		},
+		&cli.StringFlag{
+			Name:  webhookURLFlag,
+			Usage: "Send a POST request with JSON body to this URL every time problems are added or resolved.",
+		},
 	},
 }
 
@@ -155,8 +176,19 @@ func actionWatch(c *cli.Command, meta actionMeta, f pathFinderFunc) error {
 		}()
 	}
 
+	history, err := newProblemHistory(c.String(historyFileFlag))
+	if err != nil {
+		return fmt.Errorf("failed to load problem history: %w", err)
+	}
+	var webhook *webhookNotifier
+	if uri := c.String(webhookURLFlag); uri != "" {
+		webhook = newWebhookNotifier(uri)
+	}
+	recorder := newHistoryRecorder(history, webhook)
+	go recorder.Run(context.Background())
+
 	// start HTTP server for metrics
-	collector := newProblemCollector(meta.cfg, f, minSeverity, c.Int(maxProblemsFlag), c.Bool(showDupsFlag))
+	collector := newProblemCollector(meta.cfg, f, minSeverity, c.Int(maxProblemsFlag), c.Bool(showDupsFlag), history, recorder)
 	// register all metrics
 	metricsRegistry.MustRegister(collector)
 	metricsRegistry.MustRegister(checkDuration)
@@ -167,6 +199,9 @@ func actionWatch(c *cli.Command, meta actionMeta, f pathFinderFunc) error {
 	metricsRegistry.MustRegister(lastRunTime)
 	metricsRegistry.MustRegister(lastRunDuration)
 	metricsRegistry.MustRegister(rulesParsedTotal)
+	metricsRegistry.MustRegister(configReloadsTotal)
+	metricsRegistry.MustRegister(configLastReloadSuccessful)
+	metricsRegistry.MustRegister(configLastReloadSuccessTime)
 	promapi.RegisterMetrics(metricsRegistry)
 
 	metricsRegistry.MustRegister(
@@ -179,6 +214,10 @@ func actionWatch(c *cli.Command, meta actionMeta, f pathFinderFunc) error {
 	rulesParsedTotal.WithLabelValues(config.AlertingRuleType).Add(0)
 	rulesParsedTotal.WithLabelValues(config.RecordingRuleType).Add(0)
 	rulesParsedTotal.WithLabelValues(config.InvalidRuleType).Add(0)
+	configReloadsTotal.WithLabelValues(reloadSuccess).Add(0)
+	configReloadsTotal.WithLabelValues(reloadFailure).Add(0)
+	configLastReloadSuccessful.Set(1)
+	configLastReloadSuccessTime.SetToCurrentTime()
 
 	http.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
 		_, _ = io.WriteString(w, "OK\n")
@@ -187,6 +226,7 @@ func actionWatch(c *cli.Command, meta actionMeta, f pathFinderFunc) error {
 		ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
 		Timeout:  time.Second * 20,
 	}))
+	registerAPI(http.DefaultServeMux, collector)
 	listen := c.String(listenFlag)
 	server := http.Server{
 		Addr:         listen,
@@ -205,12 +245,48 @@ func actionWatch(c *cli.Command, meta actionMeta, f pathFinderFunc) error {
 	gen := config.NewPrometheusGenerator(meta.cfg, metricsRegistry)
 	gen.GenerateStatic()
 
-	allowedOwners := meta.cfg.Owners.CompileAllowed()
+	mainCtx, mainCancel := context.WithCancel(context.WithValue(context.Background(), config.CommandKey, config.WatchCommand))
+
+	var notifier *fileNotifier
+	if c.Bool(fsnotifyFlag) {
+		notifier, err = newFileNotifier(f)
+		if err != nil {
+			mainCancel()
+			return fmt.Errorf("failed to start file watcher: %w", err)
+		}
+		defer notifier.Close()
+		go notifier.Run(mainCtx)
+		slog.LogAttrs(mainCtx, slog.LevelInfo, "Watching rule files for changes")
+	}
+
+	reload := make(chan struct{}, 1)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-mainCtx.Done():
+				return
+			case <-hup:
+				slog.LogAttrs(mainCtx, slog.LevelInfo, "Received SIGHUP, reloading configuration")
+				requestReload(reload)
+			}
+		}
+	}()
+	if c.Bool(watchConfigFlag) {
+		if err = watchConfigFile(mainCtx, c.String(configFlag), reload); err != nil {
+			mainCancel()
+			return fmt.Errorf("failed to watch config file: %w", err)
+		}
+		slog.LogAttrs(mainCtx, slog.LevelInfo, "Watching config file for changes", slog.String("path", c.String(configFlag)))
+	}
+	loader := func() (config.Config, error) {
+		return loadConfig(c)
+	}
 
 	// start timer to run every $interval
 	ack := make(chan bool, 1)
-	mainCtx, mainCancel := context.WithCancel(context.WithValue(context.Background(), config.CommandKey, config.WatchCommand))
-	stop := startTimer(mainCtx, meta.workers, meta.isOffline, gen, meta.cfg.Parser.Options(), allowedOwners, interval, ack, collector)
+	stop := startTimer(mainCtx, meta.workers, meta.isOffline, gen, interval, ack, collector, notifier, reload, loader)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -219,10 +295,14 @@ func actionWatch(c *cli.Command, meta actionMeta, f pathFinderFunc) error {
 	slog.LogAttrs(context.Background(), slog.LevelInfo, "Shutting down")
 	mainCancel()
 
+	signal.Stop(hup)
 	stop <- true
 	slog.LogAttrs(context.Background(), slog.LevelInfo, "Waiting for all background tasks to finish")
 	<-ack
 
+	slog.LogAttrs(context.Background(), slog.LevelInfo, "Waiting for problem history to be recorded")
+	recorder.Close()
+
 	gen.Stop()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
@@ -232,7 +312,7 @@ func actionWatch(c *cli.Command, meta actionMeta, f pathFinderFunc) error {
 	return nil
 }
 
-func startTimer(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator, parserOpts parser.Options, allowedOwners []*regexp.Regexp, interval time.Duration, ack chan bool, collector *problemCollector) chan bool {
+func startTimer(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator, interval time.Duration, ack chan bool, collector *problemCollector, notifier *fileNotifier, reload <-chan struct{}, loader configLoaderFunc) chan bool {
 	ticker := time.NewTicker(time.Second)
 	stop := make(chan bool, 1)
 	wasBootstrapped := false
@@ -246,7 +326,24 @@ func startTimer(ctx context.Context, workers int, isOffline bool, gen *config.Pr
 					ticker.Reset(interval)
 					wasBootstrapped = true
 				}
-				if err := collector.scan(ctx, workers, isOffline, gen, parserOpts, allowedOwners); err != nil {
+				if err := collector.scan(ctx, workers, isOffline, gen); err != nil {
+					slog.LogAttrs(ctx, slog.LevelError, "Got an error when running checks", slog.Any("err", err))
+				}
+				notifier.Refresh(ctx)
+				checkIterationsTotal.Inc()
+			case <-reload:
+				if !collector.reload(ctx, gen, loader) || !wasBootstrapped {
+					continue
+				}
+				slog.LogAttrs(ctx, slog.LevelDebug, "Running checks after configuration reload")
+				if err := collector.scan(ctx, workers, isOffline, gen); err != nil {
+					slog.LogAttrs(ctx, slog.LevelError, "Got an error when running checks", slog.Any("err", err))
+				}
+				notifier.Refresh(ctx)
+				checkIterationsTotal.Inc()
+			case paths := <-notifier.Changes():
+				slog.LogAttrs(ctx, slog.LevelInfo, "Re-checking modified files", slog.Any("paths", paths))
+				if err := collector.rescan(ctx, paths, workers, isOffline, gen); err != nil {
 					slog.LogAttrs(ctx, slog.LevelError, "Got an error when running checks", slog.Any("err", err))
 				}
 				checkIterationsTotal.Inc()
@@ -265,10 +362,16 @@ func startTimer(ctx context.Context, workers int, isOffline bool, gen *config.Pr
 
 type problemCollector struct {
 	finder           pathFinderFunc
+	history          *problemHistory
+	recorder         *historyRecorder
 	fileOwners       map[string]string
 	summary          *reporter.Summary
+	entries          []*discovery.Entry
+	reports          []reporter.Report
+	servers          []*promapi.FailoverGroup
 	problem          *prometheus.Desc
 	problems         *prometheus.Desc
+	firstSeen        *prometheus.Desc
 	fileOwnersMetric *prometheus.Desc
 	cfg              config.Config
 	maxProblems      int
@@ -277,9 +380,11 @@ type problemCollector struct {
 	showDuplicates   bool
 }
 
-func newProblemCollector(cfg config.Config, f pathFinderFunc, minSeverity checks.Severity, maxProblems int, showDuplicates bool) *problemCollector {
+func newProblemCollector(cfg config.Config, f pathFinderFunc, minSeverity checks.Severity, maxProblems int, showDuplicates bool, history *problemHistory, recorder *historyRecorder) *problemCollector {
 	return &problemCollector{ // nolint: exhaustruct
 		finder:     f,
+		history:    history,
+		recorder:   recorder,
 		cfg:        cfg,
 		fileOwners: map[string]string{},
 		problem: prometheus.NewDesc(
@@ -294,6 +399,12 @@ func newProblemCollector(cfg config.Config, f pathFinderFunc, minSeverity checks
 			[]string{},
 			prometheus.Labels{},
 		),
+		firstSeen: prometheus.NewDesc(
+			"pint_problem_first_seen_timestamp_seconds",
+			"Time when given problem was first reported by pint since unix epoch in seconds.",
+			[]string{"filename", "kind", "name", "severity", "reporter", "problem", "owner"},
+			prometheus.Labels{},
+		),
 		fileOwnersMetric: prometheus.NewDesc(
 			"pint_rule_file_owner",
 			"This is a boolean metric that describes who is the configured owner for given rule file.",
@@ -306,7 +417,10 @@ func newProblemCollector(cfg config.Config, f pathFinderFunc, minSeverity checks
 	}
 }
 
-func (c *problemCollector) scan(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator, parserOpts parser.Options, allowedOwners []*regexp.Regexp) error {
+func (c *problemCollector) scan(ctx context.Context, workers int, isOffline bool, gen *config.PrometheusGenerator) error {
+	parserOpts := c.cfg.Parser.Options()
+	allowedOwners := c.cfg.Owners.CompileAllowed()
+
 	paths, err := c.finder(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get the list of paths to check: %w", err)
@@ -327,28 +441,195 @@ func (c *problemCollector) scan(ctx context.Context, workers int, isOffline bool
 		return err
 	}
 
+	rendered, err := discovery.NewRenderFinder(c.cfg.Parser.Renderers(), discovery.RunRenderCommand, parserOpts, allowedOwners).Find(ctx)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, rendered...)
+
 	s, err := checkRules(ctx, workers, isOffline, gen, c.cfg, entries)
 	if err != nil {
 		return err
 	}
 
+	c.update(ctx, entries, s.Reports(), gen.Servers())
+
+	return nil
+}
+
+// rescan re-parses only modified files and re-runs checks for rules from these files
+// and for all other rules that depend on them, results for all other rules are preserved.
+func (c *problemCollector) rescan(ctx context.Context, paths []string, workers int, isOffline bool, gen *config.PrometheusGenerator) error {
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	entries, reports := c.entries, c.reports
+	c.lock.Unlock()
+
+	if entries == nil {
+		// No full scan was completed yet.
+		return nil
+	}
+
+	for _, path := range paths {
+		if c.isRenderedPath(path) {
+			// Rendered rules can depend on any file in the chart or overlay.
+			return c.scan(ctx, workers, isOffline, gen)
+		}
+	}
+
+	var removed, kept []*discovery.Entry
+	for _, entry := range entries {
+		if slices.Contains(paths, filepath.Clean(entry.Path.Name)) || slices.Contains(paths, filepath.Clean(entry.Path.SymlinkTarget)) {
+			removed = append(removed, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+
+	// Parse all modified files that still exist, including symlinks pointing to modified files.
+	var existing []string
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err == nil && !slices.Contains(existing, path) {
+			existing = append(existing, path)
+		}
+	}
+	for _, entry := range removed {
+		if _, err := os.Lstat(entry.Path.Name); err == nil && !slices.Contains(existing, entry.Path.Name) {
+			existing = append(existing, entry.Path.Name)
+		}
+	}
+
+	var parsed []*discovery.Entry
+	if len(existing) > 0 {
+		var err error
+		parsed, err = discovery.NewGlobFinder(
+			existing,
+			git.NewPathFilter(
+				config.MustCompileRegexes(c.cfg.Parser.Include...),
+				config.MustCompileRegexes(c.cfg.Parser.Exclude...),
+				config.MustCompileRegexes(c.cfg.Parser.Relaxed...),
+			),
+			c.cfg.Parser.Options(),
+			c.cfg.Owners.CompileAllowed(),
+		).Find()
+		if err != nil {
+			return err
+		}
+	}
 
+	dependents := discovery.DependentEntries(kept, append(slices.Clone(removed), parsed...))
+	selected := append(slices.Clone(parsed), dependents...)
+	entries = append(kept, parsed...)
+
+	slog.LogAttrs(
+		ctx, slog.LevelInfo, "Checking modified Prometheus rules",
+		slog.Int("entries", len(parsed)),
+		slog.Int("dependents", len(dependents)),
+		slog.Int("workers", workers),
+		slog.Bool("online", !isOffline),
+	)
+	s, err := checkSelectedRules(ctx, workers, isOffline, gen, c.cfg, entries, selected)
+	if err != nil {
+		return err
+	}
+
+	// Keep all existing reports for rules that were not checked again.
+	merged := make([]reporter.Report, 0, len(reports))
+	for _, report := range reports {
+		if isReportForEntries(report, removed) || isReportForEntries(report, dependents) {
+			continue
+		}
+		merged = append(merged, report)
+	}
+	merged = append(merged, s.Reports()...)
+
+	c.update(ctx, entries, merged, gen.Servers())
+
+	return nil
+}
+
+// reload will try to load the config file again and, if it's valid, start using it.
+// The old config is kept if the new one cannot be loaded.
+func (c *problemCollector) reload(ctx context.Context, gen *config.PrometheusGenerator, loader configLoaderFunc) bool {
+	cfg, err := loader()
+	if err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "Failed to reload configuration, keeping the old one", slog.Any("err", err))
+		configReloadsTotal.WithLabelValues(reloadFailure).Inc()
+		configLastReloadSuccessful.Set(0)
+		return false
+	}
+
+	gen.Reload(cfg)
+	c.cfg = cfg
+
+	slog.LogAttrs(ctx, slog.LevelInfo, "Configuration reloaded", slog.Int("servers", gen.Count()))
+	configReloadsTotal.WithLabelValues(reloadSuccess).Inc()
+	configLastReloadSuccessful.Set(1)
+	configLastReloadSuccessTime.SetToCurrentTime()
+	return true
+}
+
+func (c *problemCollector) update(ctx context.Context, entries []*discovery.Entry, reports []reporter.Report, servers []*promapi.FailoverGroup) {
+	reports = slices.Clone(reports)
+	for i := range reports {
+		reports[i].IsDuplicate = false
+		reports[i].Duplicates = nil
+	}
+
+	s := reporter.NewSummary(slices.Clone(reports))
 	s.SortReports()
 	s.Dedup()
 
-	c.summary = &s
-
 	fileOwners := map[string]string{}
 	for _, entry := range entries {
 		if entry.Owner != "" {
 			fileOwners[entry.Path.Name] = entry.Owner
 		}
 	}
+
+	var problems []historyProblem
+	for _, report := range s.Reports() {
+		if c.isReported(report) {
+			problems = append(problems, newHistoryProblem(report))
+		}
+	}
+	current := c.history.observe(problems, time.Now())
+
+	c.lock.Lock()
+	c.summary = &s
+	c.entries = entries
+	c.reports = reports
+	c.servers = slices.Clone(servers)
 	c.fileOwners = fileOwners
+	c.lock.Unlock()
 
-	return nil
+	c.recorder.Update(current)
+}
+
+// isReported returns true if given report should be exposed via metrics and the API.
+func (c *problemCollector) isReported(report reporter.Report) bool {
+	if report.Problem.Severity < c.minSeverity {
+		return false
+	}
+	return !report.IsDuplicate || c.showDuplicates
+}
+
+func (c *problemCollector) isRenderedPath(path string) bool {
+	for _, r := range c.cfg.Parser.Renderers() {
+		dir := filepath.Clean(r.Path)
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isReportForEntries(report reporter.Report, entries []*discovery.Entry) bool {
+	for _, entry := range entries {
+		if report.Path.Name == entry.Path.Name && report.Rule.Lines == entry.Rule.Lines {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *problemCollector) Describe(ch chan<- *prometheus.Desc) {
@@ -368,6 +649,8 @@ func (c *problemCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	done := map[string][]prometheus.Metric{}
+	fingerprints := map[string]string{}
+	firstSeen := map[string]prometheus.Metric{}
 	keys := []string{}
 
 	for _, report := range c.summary.Reports() {
@@ -405,6 +688,12 @@ func (c *problemCollector) Collect(ch chan<- prometheus.Metric) {
 		if _, ok := done[key]; !ok {
 			done[key] = metrics
 			keys = append(keys, key)
+
+			fp := problemFingerprint(report)
+			fingerprints[key] = fp
+			if ts := c.history.firstSeen(fp); !ts.IsZero() {
+				firstSeen[fp] = metricFromFirstSeen(report, c.firstSeen, ts)
+			}
 		}
 	}
 
@@ -413,6 +702,10 @@ func (c *problemCollector) Collect(ch chan<- prometheus.Metric) {
 	slices.Sort(keys)
 	var reported int
 	for _, key := range keys {
+		if metric, ok := firstSeen[fingerprints[key]]; ok {
+			ch <- metric
+			delete(firstSeen, fingerprints[key])
+		}
 		for _, metric := range done[key] {
 			ch <- metric
 			reported++
@@ -442,4 +735,33 @@ func metricFromProblem(report reporter.Report, problem *prometheus.Desc, summary
 	)
 }
 
+func metricFromFirstSeen(report reporter.Report, firstSeen *prometheus.Desc, ts time.Time) prometheus.Metric {
+	name := report.Rule.Name()
+	if name == "" {
+		name = "unknown"
+	}
+	return prometheus.MustNewConstMetric(
+		firstSeen,
+		prometheus.GaugeValue,
+		float64(ts.UnixNano())/1e9,
+		report.Path.Name,
+		string(report.Rule.Type()),
+		name,
+		strings.ToLower(report.Problem.Severity.String()),
+		report.Problem.Reporter,
+		report.Problem.Summary,
+		report.Owner,
+	)
+}
+
 type pathFinderFunc func(ctx context.Context) ([]string, error)
+
+type configLoaderFunc func() (config.Config, error)
+
+// requestReload will queue a config reload unless there's already one pending.
+func requestReload(reload chan<- struct{}) {
+	select {
+	case reload <- struct{}{}:
+	default:
+	}
+}
@@ -18,6 +18,9 @@ import (
 const (
 	AlertsAbsentCheckName    = "alerts/absent"
 	AlertsAbsentCheckDetails = "When Prometheus restarts this alert rule might be evaluated before your service is scraped, which can cause false positives from absent() call.\nAdding a `for` option that is at least 2x scrape interval will prevent this from happening."
+
+	// absent_over_time() window should cover at least this many scrapes.
+	absentOverTimeMinScrapes = 3
 )
 
 func NewAlertsAbsentCheck(prom *promapi.FailoverGroup) AlertsAbsentCheck {
@@ -64,14 +67,17 @@ func (c AlertsAbsentCheck) Check(ctx context.Context, entry *discovery.Entry, _
 
 	src := entry.Rule.AlertingRule.Expr.Source()
 	absentSources := make([]*source.Source, 0, len(src))
+	overTimeSources := make([]*source.Source, 0, len(src))
 	for _, s := range src {
-		if s.Operation() != "absent" {
-			continue
+		switch s.Operation() {
+		case "absent":
+			absentSources = append(absentSources, s)
+		case "absent_over_time":
+			overTimeSources = append(overTimeSources, s)
 		}
-		absentSources = append(absentSources, s)
 	}
 
-	if len(absentSources) == 0 {
+	if len(absentSources) == 0 && len(overTimeSources) == 0 {
 		return problems
 	}
 
@@ -85,6 +91,13 @@ func (c AlertsAbsentCheck) Check(ctx context.Context, entry *discovery.Entry, _
 		return problems
 	}
 
+	for _, s := range absentSources {
+		problems = append(problems, c.checkJob(entry, cfg, s)...)
+	}
+	for _, s := range overTimeSources {
+		problems = append(problems, c.checkOverTimeWindow(ctx, entry, cfg, s)...)
+	}
+
 	if entry.Rule.AlertingRule.For != nil && entry.Rule.AlertingRule.For.ParseError != nil {
 		return problems
 	}
@@ -161,3 +174,100 @@ func (c AlertsAbsentCheck) Check(ctx context.Context, entry *discovery.Entry, _
 
 	return problems
 }
+
+// checkJob reports absent() calls on selectors with a job that's not present
+// in scrape_configs, these series can only be missing so absent() will always
+// return a result.
+func (c AlertsAbsentCheck) checkJob(entry *discovery.Entry, cfg *promapi.ConfigResult, s *source.Source) (problems []Problem) {
+	// Without any scrape_configs we can't tell which jobs exist.
+	// Jobs can also be defined in scrape_config_files, but the content
+	// of these files is not exposed via Prometheus API.
+	if len(cfg.Config.ScrapeConfigs) == 0 || len(cfg.Config.ScrapeConfigFiles) > 0 {
+		return problems
+	}
+
+	vs, ok := source.MostOuterOperation[*promParser.VectorSelector](s)
+	if !ok {
+		return problems
+	}
+	job, ok := selectorJob(vs)
+	if !ok {
+		return problems
+	}
+
+	if _, ok = cfg.Config.ScrapeJob(job); ok {
+		return problems
+	}
+	for _, sc := range cfg.Config.ScrapeConfigs {
+		if sc.CanSetJobLabel() {
+			return problems
+		}
+	}
+
+	call, _ := source.MostOuterOperation[*promParser.Call](s)
+	problems = append(problems, Problem{
+		Anchor:   AnchorAfter,
+		Lines:    entry.Rule.AlertingRule.Expr.Value.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  "absent() on a job that isn't scraped",
+		Details: "This alert is checking for a job that's not present in scrape configuration, so `absent()` will always find these metrics missing.\n" +
+			"This usually happens when a job was removed from Prometheus configuration but alerts for it were left behind.",
+		Severity: Warning,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message: fmt.Sprintf("`%s` configuration on %s doesn't have any scrape job named `%s`.",
+					promapi.APIPathConfig, promText(c.prom.Name(), cfg.URI), job),
+				Pos:         entry.Rule.AlertingRule.Expr.Value.Pos,
+				Expr:        entry.Rule.AlertingRule.Expr.Query().Expr,
+				FirstColumn: int(call.PosRange.Start) + 1,
+				LastColumn:  int(call.PosRange.End),
+				Kind:        diags.Issue,
+			},
+		},
+	})
+	return problems
+}
+
+// checkOverTimeWindow reports absent_over_time() calls with a range window
+// that doesn't cover enough scrapes, those will return results between scrapes
+// or after a single failed scrape.
+func (c AlertsAbsentCheck) checkOverTimeWindow(ctx context.Context, entry *discovery.Entry, cfg *promapi.ConfigResult, s *source.Source) (problems []Problem) {
+	ms, ok := source.MostOuterOperation[*promParser.MatrixSelector](s)
+	if !ok || ms.Range <= 0 {
+		return problems
+	}
+
+	vs, ok := ms.VectorSelector.(*promParser.VectorSelector)
+	if !ok {
+		return problems
+	}
+
+	si := resolveScrapeInterval(ctx, c.prom, cfg, vs)
+	if ms.Range >= si.interval*absentOverTimeMinScrapes {
+		return problems
+	}
+
+	call, _ := source.MostOuterOperation[*promParser.Call](s)
+	problems = append(problems, Problem{
+		Anchor:   AnchorAfter,
+		Lines:    entry.Rule.AlertingRule.Expr.Value.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  "absent_over_time() window too small",
+		Details: fmt.Sprintf("Using `absent_over_time()` with a range window shorter than %d scrape intervals can cause false positive alerts, "+
+			"the time window might not contain any samples if a single scrape fails or takes longer than usual.", absentOverTimeMinScrapes),
+		Severity: Warning,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message: fmt.Sprintf("`absent_over_time()` is using a `%s` window but %s is using `%s` %s, use a window of at least `%s`.",
+					output.HumanizeDuration(ms.Range), promText(c.prom.Name(), cfg.URI),
+					output.HumanizeDuration(si.interval), si, output.HumanizeDuration(si.interval*absentOverTimeMinScrapes)),
+				Pos:         entry.Rule.AlertingRule.Expr.Value.Pos,
+				Expr:        entry.Rule.AlertingRule.Expr.Query().Expr,
+				FirstColumn: int(call.PosRange.Start) + 1,
+				LastColumn:  int(call.PosRange.End),
+				Kind:        diags.Issue,
+			},
+		},
+	})
+	return problems
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
+
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/promapi"
 )
@@ -195,6 +197,192 @@ func TestAlertsAbsentCheck(t *testing.T) {
 			},
 			problems: true,
 		},
+		{
+			description: "absent() on a scraped job",
+			content:     "- alert: foo\n  expr: absent(up{job=\"foo\"})\n  for: 5m\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n"},
+				},
+			},
+		},
+		{
+			description: "absent() on a job that isn't scraped",
+			content:     "- alert: foo\n  expr: absent(up{job=\"removed\"})\n  for: 5m\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n"},
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "absent() on a job that isn't scraped, no scrape_configs",
+			content:     "- alert: foo\n  expr: absent(up{job=\"removed\"})\n  for: 5m\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+			},
+		},
+		{
+			description: "absent() on a job that isn't scraped, regexp matcher",
+			content:     "- alert: foo\n  expr: absent(up{job=~\"removed\"})\n  for: 5m\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n"},
+				},
+			},
+		},
+		{
+			description: "absent() on a job that isn't scraped, honor_labels",
+			content:     "- alert: foo\n  expr: absent(up{job=\"removed\"})\n  for: 5m\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n- job_name: federate\n  honor_labels: true\n"},
+				},
+			},
+		},
+		{
+			description: "absent() on a job that isn't scraped, job relabeled",
+			content:     "- alert: foo\n  expr: absent(up{job=\"removed\"})\n  for: 5m\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n- job_name: sd\n  relabel_configs:\n  - target_label: job\n    replacement: removed\n"},
+				},
+			},
+		},
+		{
+			description: "absent() on a job that isn't scraped, scrape_config_files",
+			content:     "- alert: foo\n  expr: absent(up{job=\"removed\"})\n  for: 5m\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_config_files:\n- jobs/*.yml\nscrape_configs:\n- job_name: foo\n"},
+				},
+			},
+		},
+		{
+			description: "absent_over_time() with a window smaller than 3x global scrape_interval",
+			content:     "- alert: foo\n  expr: absent_over_time(up[2m])\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(up) by (job)"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "absent_over_time() with a window equal to 3x global scrape_interval",
+			content:     "- alert: foo\n  expr: absent_over_time(up[3m])\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(up) by (job)"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+			},
+		},
+		{
+			description: "absent_over_time() with a window smaller than 3x job scrape_interval",
+			content:     "- alert: foo\n  expr: absent_over_time(up{job=\"foo\"}[2m])\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: foo\n  scrape_interval: 1m\n"},
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "absent_over_time() with a window bigger than 3x job scrape_interval",
+			content:     "- alert: foo\n  expr: absent_over_time(up{job=\"bar\"}[1m])\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n"},
+				},
+			},
+		},
+		{
+			description: "absent_over_time() on a job that isn't scraped",
+			content:     "- alert: foo\n  expr: absent_over_time(up{job=\"removed\"}[5m])\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 15s\n"},
+				},
+			},
+		},
+		{
+			description: "absent_over_time() with a window smaller than 3x scrape_interval of jobs from series",
+			content:     "- alert: foo\n  expr: absent_over_time(up[2m])\n",
+			checker:     newAlertsAbsentCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(up) by (job)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSample(map[string]string{"job": "foo"}),
+							generateSample(map[string]string{"job": "bar"}),
+						},
+					},
+				},
+			},
+			problems: true,
+		},
 	}
 	runTests(t, testCases)
 }
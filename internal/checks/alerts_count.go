@@ -79,7 +79,10 @@ func (c AlertsCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*dis
 		return problems
 	}
 
-	if len(qr.Series.Ranges) > 0 {
+	isLogQL := entry.Rule.AlertingRule.Expr.IsLogQL()
+
+	// Loki doesn't have any uptime metric we could use to find gaps.
+	if len(qr.Series.Ranges) > 0 && !isLogQL {
 		promUptime, err := c.prom.RangeQuery(ctx, wrapExpr(c.prom.UptimeMetric(), "count"), params).Wait()
 		if err != nil {
 			slog.LogAttrs(ctx, slog.LevelWarn, "Cannot detect Prometheus uptime gaps", slog.Any("err", err), slog.String("name", c.prom.Name()))
@@ -111,12 +114,18 @@ func (c AlertsCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*dis
 	}
 
 	delta := qr.Series.Until.Sub(qr.Series.From).Round(time.Minute)
-	details := fmt.Sprintf(
-		`To get a preview of the alerts that would fire please [click here](%s/graph?g0.expr=%s&g0.tab=0&g0.range_input=%s).`,
-		qr.URI, url.QueryEscape(entry.Rule.AlertingRule.Expr.Value.Value), output.HumanizeDuration(delta),
-	)
+	var details string
+	if !isLogQL {
+		details = fmt.Sprintf(
+			`To get a preview of the alerts that would fire please [click here](%s/graph?g0.expr=%s&g0.tab=0&g0.range_input=%s).`,
+			qr.URI, url.QueryEscape(entry.Rule.AlertingRule.Expr.Value.Value), output.HumanizeDuration(delta),
+		)
+	}
 	if c.comment != "" {
-		details = fmt.Sprintf("%s\n%s", details, maybeComment(c.comment))
+		if details != "" {
+			details += "\n"
+		}
+		details += maybeComment(c.comment)
 	}
 
 	problems = append(problems, Problem{
@@ -7,6 +7,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/promapi"
 )
 
@@ -772,6 +773,34 @@ func TestAlertsCountCheck(t *testing.T) {
 			},
 			problems: true,
 		},
+		{
+			description: "Loki rule",
+			content:     "- alert: Foo\n  expr: sum by(job) (rate({job=\"foo\"} |= \"error\" [5m])) > 0\n",
+			checker:     newAlertsCheck,
+			prometheus: func(uri string) *promapi.FailoverGroup {
+				return simpleProm("loki", uri+"/loki", time.Second*5, true)
+			},
+			contentSchema: parser.LokiSchema,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requestPathCond{path: "/loki" + promapi.APIPathQueryRange},
+						formCond{key: "query", value: `sum by(job) (rate({job="foo"} |= "error" [5m])) > 0`},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							generateSampleStream(
+								map[string]string{"job": "foo"},
+								time.Now().Add(time.Hour*-20),
+								time.Now().Add(time.Hour*-20).Add(time.Minute*6),
+								time.Minute,
+							),
+						},
+					},
+				},
+			},
+			problems: true,
+		},
 	}
 
 	runTests(t, testCases)
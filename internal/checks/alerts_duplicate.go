@@ -0,0 +1,178 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	AlertsDuplicateCheckName = "alerts/duplicate"
+
+	AlertsDuplicateCheckDetails = `Prometheus will add ` + "`global:external_labels`" + ` to all alerts it sends to Alertmanager.
+Alertmanager can only deduplicate alerts with identical labels, so the same alert sent from servers with different ` + "`external_labels`" + ` will be seen as multiple alerts.
+See [alerts/duplicate](https://cloudflare.github.io/pint/checks/alerts/duplicate.html) for more details.`
+)
+
+func NewAlertsDuplicateCheck(prom *promapi.FailoverGroup) AlertsDuplicateCheck {
+	return AlertsDuplicateCheck{
+		prom:     prom,
+		instance: fmt.Sprintf("%s(%s)", AlertsDuplicateCheckName, prom.Name()),
+	}
+}
+
+type AlertsDuplicateCheck struct {
+	prom     *promapi.FailoverGroup
+	instance string
+}
+
+func (c AlertsDuplicateCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        true,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c AlertsDuplicateCheck) String() string {
+	return c.instance
+}
+
+func (c AlertsDuplicateCheck) Reporter() string {
+	return AlertsDuplicateCheckName
+}
+
+func (c AlertsDuplicateCheck) Check(ctx context.Context, entry *discovery.Entry, entries []*discovery.Entry) (problems []Problem) {
+	if entry.Rule.AlertingRule == nil {
+		return problems
+	}
+
+	if entry.Rule.AlertingRule.Expr.SyntaxError() != nil {
+		return problems
+	}
+
+	servers := c.otherServers(ctx, entry, entries)
+	if len(servers) == 0 {
+		return problems
+	}
+
+	cfg, err := c.prom.Config(ctx, 0).Wait()
+	if err != nil {
+		if errors.Is(err, promapi.ErrUnsupported) {
+			c.prom.DisableCheck(promapi.APIPathConfig, c.Reporter())
+			return problems
+		}
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Warning))
+		return problems
+	}
+	externalLabels, ok := cfg.Config.AlertLabels(labels.FromMap(cfg.Config.Global.ExternalLabels))
+	if !ok {
+		return problems
+	}
+
+	others := make([]string, 0, len(servers))
+	for _, server := range servers {
+		otherCfg, err := server.Config(ctx, 0).Wait()
+		if err != nil {
+			if errors.Is(err, promapi.ErrUnsupported) {
+				continue
+			}
+			problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), server.Name(), Warning))
+			continue
+		}
+		otherLabels, ok := otherCfg.Config.AlertLabels(labels.FromMap(otherCfg.Config.Global.ExternalLabels))
+		if !ok || labels.Equal(externalLabels, otherLabels) {
+			continue
+		}
+		others = append(others, fmt.Sprintf("%s with `%s` external labels", promText(server.Name(), otherCfg.URI), otherLabels))
+	}
+	if len(others) == 0 {
+		return problems
+	}
+
+	problems = append(problems, Problem{
+		Anchor:   AnchorAfter,
+		Lines:    entry.Rule.Lines,
+		Reporter: c.Reporter(),
+		Summary:  "alert evaluated on servers with different external labels",
+		Details:  AlertsDuplicateCheckDetails,
+		Severity: Warning,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message: fmt.Sprintf(
+					"This alert is evaluated on %s with `%s` external labels, but it's also evaluated on %s. Alertmanager won't deduplicate these alerts and will receive a separate copy from each server.",
+					promText(c.prom.Name(), cfg.URI), externalLabels, strings.Join(others, ", "),
+				),
+				Pos:         entry.Rule.AlertingRule.Alert.Pos,
+				Expr:        nil,
+				FirstColumn: 1,
+				LastColumn:  len(entry.Rule.AlertingRule.Alert.Value),
+				Kind:        diags.Issue,
+			},
+		},
+	})
+
+	return problems
+}
+
+// otherServers returns all other Prometheus servers that will evaluate the same alert,
+// either from this file or from any other file with an identical alerting rule.
+// Only servers that come after this one are returned, servers listed before this one
+// will already report it.
+func (c AlertsDuplicateCheck) otherServers(ctx context.Context, entry *discovery.Entry, entries []*discovery.Entry) (servers []*promapi.FailoverGroup) {
+	val := ctx.Value(promapi.AllPrometheusServers)
+	if val == nil {
+		return nil
+	}
+
+	paths := []string{entry.Path.Name}
+	for _, other := range entries {
+		if isSameAlert(entry.Rule.AlertingRule, other) {
+			paths = append(paths, other.Path.Name)
+		}
+	}
+
+	var isAfter bool
+	for _, server := range val.([]*promapi.FailoverGroup) {
+		if server.Name() == c.prom.Name() {
+			isAfter = true
+			continue
+		}
+		if !isAfter {
+			continue
+		}
+		for _, path := range paths {
+			if server.IsEnabledForPath(path) {
+				servers = append(servers, server)
+				break
+			}
+		}
+	}
+	return servers
+}
+
+func isSameAlert(rule *parser.AlertingRule, other *discovery.Entry) bool {
+	if other.State == discovery.Removed {
+		return false
+	}
+	if other.PathError != nil || other.Rule.Error.Err != nil {
+		return false
+	}
+	if other.Rule.AlertingRule == nil || other.Rule.AlertingRule.Alert.Value != rule.Alert.Value {
+		return false
+	}
+	return rule.Labels.IsIdentical(other.Rule.AlertingRule.Labels)
+}
@@ -0,0 +1,280 @@
+package checks_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newAlertsDuplicateCheck(prom *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewAlertsDuplicateCheck(prom)
+}
+
+func newExternalLabelsProm(name string, externalLabels map[string]string, include ...string) *promapi.FailoverGroup {
+	paths := make([]*regexp.Regexp, 0, len(include))
+	for _, path := range include {
+		paths = append(paths, regexp.MustCompile("^"+path+"$"))
+	}
+	prom := promapi.NewFailoverGroup(name, name+".yml", nil, false, "up", paths, nil, nil)
+	prom.SetLocalConfig(&promapi.ConfigResult{
+		URI:  name + ".yml",
+		Path: name + ".yml",
+		Config: promapi.PrometheusConfig{
+			Global: promapi.ConfigSectionGlobal{
+				ExternalLabels: externalLabels,
+			},
+		},
+	})
+	return prom
+}
+
+func newAlertRelabelProm(name string, externalLabels map[string]string, rcs []*relabel.Config) *promapi.FailoverGroup {
+	prom := promapi.NewFailoverGroup(name, name+".yml", nil, false, "up", nil, nil, nil)
+	prom.SetLocalConfig(&promapi.ConfigResult{
+		URI:  name + ".yml",
+		Path: name + ".yml",
+		Config: promapi.PrometheusConfig{
+			Global: promapi.ConfigSectionGlobal{
+				ExternalLabels: externalLabels,
+			},
+			Alerting: promapi.ConfigSectionAlerting{
+				AlertRelabelConfigs: rcs,
+			},
+		},
+	})
+	return prom
+}
+
+func dropReplicaLabel() []*relabel.Config {
+	return []*relabel.Config{
+		{
+			Action: relabel.LabelDrop,
+			Regex:  relabel.MustNewRegexp("replica"),
+		},
+	}
+}
+
+func withPath(path string, entries []*discovery.Entry) []*discovery.Entry {
+	for _, entry := range entries {
+		entry.Path.Name = path
+		entry.Path.SymlinkTarget = path
+	}
+	return entries
+}
+
+func TestAlertsDuplicateCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores recording rules",
+			content:     "- record: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"})
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "b"}),
+				}
+			},
+		},
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- alert: foo\n  expr: sum(\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"})
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "b"}),
+				}
+			},
+		},
+		{
+			description: "single server",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"})
+			},
+		},
+		{
+			description: "servers with identical external labels",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"})
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "a"}),
+				}
+			},
+		},
+		{
+			description: "servers with different external labels",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"})
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "b"}),
+					newExternalLabelsProm("same", map[string]string{"cluster": "a"}),
+					newExternalLabelsProm("thanos", nil),
+				}
+			},
+			problems: true,
+		},
+		{
+			description: "HA pair with replica label removed by alert relabeling",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newAlertRelabelProm("prom", map[string]string{"cluster": "a", "replica": "1"}, dropReplicaLabel())
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newAlertRelabelProm("other", map[string]string{"cluster": "a", "replica": "2"}, dropReplicaLabel()),
+				}
+			},
+		},
+		{
+			description: "HA pair with replica label removed only on one server",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newAlertRelabelProm("prom", map[string]string{"cluster": "a", "replica": "1"}, dropReplicaLabel())
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newAlertRelabelProm("other", map[string]string{"cluster": "a", "replica": "2"}, nil),
+				}
+			},
+			problems: true,
+		},
+		{
+			description: "other server dropping all alerts",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"})
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newAlertRelabelProm("other", map[string]string{"cluster": "b"}, []*relabel.Config{
+						{
+							Action:       relabel.Drop,
+							SourceLabels: model.LabelNames{"cluster"},
+							Regex:        relabel.MustNewRegexp("b"),
+						},
+					}),
+				}
+			},
+		},
+		{
+			description: "other server not evaluating this file",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"})
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "b"}, "other.yml"),
+				}
+			},
+		},
+		{
+			description: "same alert in another file",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: page\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"}, "fake.yml")
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "b"}, "other.yml"),
+				}
+			},
+			entries: withPath("other.yml", mustParseContent(
+				"- alert: foo\n  expr: up{job=\"bar\"} == 0\n  labels:\n    severity: page\n",
+			)),
+			problems: true,
+		},
+		{
+			description: "alert with different labels in another file",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: page\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"}, "fake.yml")
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "b"}, "other.yml"),
+				}
+			},
+			entries: withPath("other.yml", mustParseContent(
+				"- alert: foo\n  expr: up == 0\n  labels:\n    severity: ticket\n",
+			)),
+		},
+		{
+			description: "removed alert in another file",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"}, "fake.yml")
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "b"}, "other.yml"),
+				}
+			},
+			entries: withPath("other.yml", parseWithState(
+				"- alert: foo\n  expr: up == 0\n",
+				discovery.Removed,
+			)),
+		},
+		{
+			description: "config error",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus:  newSimpleProm,
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					newExternalLabelsProm("other", map[string]string{"cluster": "b"}),
+				}
+			},
+			problems: true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "other server connection refused",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newAlertsDuplicateCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return newExternalLabelsProm("prom", map[string]string{"cluster": "a"})
+			},
+			otherProms: func(_ string) []*promapi.FailoverGroup {
+				return []*promapi.FailoverGroup{
+					simpleProm("other", "http://127.0.0.1:1111", time.Second*5, false),
+				}
+			},
+			problems: true,
+		},
+	}
+	runTests(t, testCases)
+}
@@ -76,7 +76,7 @@ func (c AlertsExternalLabelsCheck) Check(ctx context.Context, entry *discovery.E
 				},
 				Reporter: c.Reporter(),
 				Summary:  "invalid label",
-				Details:  fmt.Sprintf("[Click here](%s/config) to see `%s` Prometheus runtime configuration.", cfg.URI, c.prom.Name()),
+				Details:  configDetails(c.prom.Name(), cfg),
 				Severity: Bug,
 				Diagnostics: []diags.Diagnostic{
 					{
@@ -103,7 +103,7 @@ func (c AlertsExternalLabelsCheck) Check(ctx context.Context, entry *discovery.E
 					},
 					Reporter: c.Reporter(),
 					Summary:  "invalid label",
-					Details:  fmt.Sprintf("[Click here](%s/config) to see `%s` Prometheus runtime configuration.", cfg.URI, c.prom.Name()),
+					Details:  configDetails(c.prom.Name(), cfg),
 					Severity: Bug,
 					Diagnostics: []diags.Diagnostic{
 						{
@@ -0,0 +1,210 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	AlertsFlappingCheckName = "alerts/flapping"
+)
+
+func NewAlertsFlappingCheck(prom *promapi.FailoverGroup, lookBack, step time.Duration, maxFlaps int, comment string, severity Severity) AlertsFlappingCheck {
+	return AlertsFlappingCheck{
+		prom:     prom,
+		lookBack: lookBack,
+		step:     step,
+		maxFlaps: maxFlaps,
+		comment:  comment,
+		severity: severity,
+		instance: fmt.Sprintf("%s(%s)", AlertsFlappingCheckName, prom.Name()),
+	}
+}
+
+type AlertsFlappingCheck struct {
+	prom     *promapi.FailoverGroup
+	comment  string
+	instance string
+	lookBack time.Duration
+	step     time.Duration
+	maxFlaps int
+	severity Severity
+}
+
+func (c AlertsFlappingCheck) Meta() CheckMeta {
+	return CheckMeta{
+		// ALERTS history is only useful for rules already deployed to Prometheus,
+		// so we skip new and modified rules.
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Moved,
+		},
+		Online:        true,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c AlertsFlappingCheck) String() string {
+	return c.instance
+}
+
+func (c AlertsFlappingCheck) Reporter() string {
+	return AlertsFlappingCheckName
+}
+
+func (c AlertsFlappingCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	if entry.Rule.AlertingRule == nil {
+		return problems
+	}
+
+	if entry.Rule.AlertingRule.Expr.SyntaxError() != nil {
+		return problems
+	}
+
+	params := promapi.NewRelativeRange(c.lookBack, c.step)
+
+	name := entry.Rule.AlertingRule.Alert.Value
+	query := fmt.Sprintf(`ALERTS{alertname=%q,alertstate="firing"}`, name)
+	qr, err := c.prom.RangeQuery(ctx, query, params).Wait()
+	if err != nil {
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+		return problems
+	}
+
+	if len(qr.Series.Ranges) == 0 {
+		// ALERTS might be dropped, try ALERTS_FOR_STATE which is present
+		// for both pending and firing alerts.
+		query = fmt.Sprintf(`ALERTS_FOR_STATE{alertname=%q}`, name)
+		qr, err = c.prom.RangeQuery(ctx, query, params).Wait()
+		if err != nil {
+			problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+			return problems
+		}
+	}
+
+	if len(qr.Series.Ranges) < 2 {
+		return problems
+	}
+
+	ranges := slices.Clone(qr.Series.Ranges)
+	slices.SortStableFunc(ranges, promapi.CompareMetricTimeRanges)
+
+	// Don't count Prometheus restarts as alerts being resolved.
+	promUptime, err := c.prom.RangeQuery(ctx, wrapExpr(c.prom.UptimeMetric(), "count"), params).Wait()
+	if err != nil {
+		slog.LogAttrs(ctx, slog.LevelWarn, "Cannot detect Prometheus uptime gaps", slog.Any("err", err), slog.String("name", c.prom.Name()))
+	} else {
+		qr.Series.FindGaps(promUptime.Series, qr.Series.From, qr.Series.Until)
+		ranges = promapi.MergeRangesWithoutGaps(ranges, qr.Series.Gaps)
+	}
+
+	var flaps, flapping int
+	var keepFiringFor, forDur time.Duration
+	for _, series := range splitRangesBySeries(ranges) {
+		if len(series)-1 <= c.maxFlaps {
+			continue
+		}
+		flapping++
+		flaps = max(flaps, len(series)-1)
+		keepFiringFor = max(keepFiringFor, c.keepFiringForToFix(series))
+		forDur = max(forDur, c.forToFix(series))
+	}
+
+	if flapping == 0 {
+		return problems
+	}
+
+	if entry.Rule.AlertingRule.For != nil && entry.Rule.AlertingRule.For.ParseError == nil {
+		forDur += entry.Rule.AlertingRule.For.Value
+	}
+	if entry.Rule.AlertingRule.KeepFiringFor != nil && entry.Rule.AlertingRule.KeepFiringFor.ParseError == nil {
+		keepFiringFor += entry.Rule.AlertingRule.KeepFiringFor.Value
+	}
+
+	delta := qr.Series.Until.Sub(qr.Series.From).Round(time.Minute)
+	details := fmt.Sprintf(
+		"Alert is flapping when it's resolved and then starts firing again shortly after.\n"+
+			"Setting `keep_firing_for: %s` or `for: %s` on this alert would reduce the number of times it started firing again to no more than %d.",
+		output.HumanizeDuration(keepFiringFor), output.HumanizeDuration(forDur), c.maxFlaps,
+	)
+	if c.comment != "" {
+		details = fmt.Sprintf("%s\n%s", details, maybeComment(c.comment))
+	}
+
+	problems = append(problems, Problem{
+		Anchor:   AnchorAfter,
+		Lines:    entry.Rule.AlertingRule.Alert.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  "flapping alert",
+		Details:  details,
+		Severity: c.severity,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message: fmt.Sprintf(
+					"`%s` on %s shows that %d alert(s) started firing again after being resolved, up to %d time(s) in the last %s.",
+					query, promText(c.prom.Name(), qr.URI), flapping, flaps, output.HumanizeDuration(delta),
+				),
+				Pos:         entry.Rule.AlertingRule.Alert.Pos,
+				FirstColumn: 1,
+				LastColumn:  len(entry.Rule.AlertingRule.Alert.Value),
+				Kind:        diags.Issue,
+			},
+		},
+	})
+	return problems
+}
+
+// keepFiringForToFix returns the minimal keep_firing_for increase that would
+// merge enough firing ranges to bring the number of flaps down to maxFlaps.
+// This is the (maxFlaps+1)-th longest period between two firing ranges.
+func (c AlertsFlappingCheck) keepFiringForToFix(series promapi.MetricTimeRanges) time.Duration {
+	gaps := make([]time.Duration, 0, len(series)-1)
+	for i := 1; i < len(series); i++ {
+		gaps = append(gaps, series[i].Start.Sub(series[i-1].End))
+	}
+	slices.Sort(gaps)
+	slices.Reverse(gaps)
+	return roundUpDuration(gaps[c.maxFlaps], c.step)
+}
+
+// forToFix returns the minimal for increase that would prevent enough
+// short firing ranges to bring the number of flaps down to maxFlaps.
+// This is the length of the longest firing range that needs to be removed
+// if we remove the shortest ranges first.
+func (c AlertsFlappingCheck) forToFix(series promapi.MetricTimeRanges) time.Duration {
+	durations := make([]time.Duration, 0, len(series))
+	for _, r := range series {
+		durations = append(durations, r.End.Sub(r.Start))
+	}
+	slices.Sort(durations)
+	return roundUpDuration(durations[len(series)-c.maxFlaps-2], c.step)
+}
+
+// splitRangesBySeries groups ranges sorted with CompareMetricTimeRanges by series.
+func splitRangesBySeries(ranges promapi.MetricTimeRanges) (series []promapi.MetricTimeRanges) {
+	for i, r := range ranges {
+		if i == 0 || ranges[i-1].Fingerprint != r.Fingerprint {
+			series = append(series, promapi.MetricTimeRanges{})
+		}
+		series[len(series)-1] = append(series[len(series)-1], r)
+	}
+	return series
+}
+
+func roundUpDuration(d, step time.Duration) time.Duration {
+	if step <= 0 {
+		return d
+	}
+	if r := d % step; r != 0 {
+		d += step - r
+	}
+	return d
+}
@@ -0,0 +1,247 @@
+package checks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newAlertsFlappingCheck(prom *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewAlertsFlappingCheck(prom, time.Hour*24, time.Minute, 2, "", checks.Warning)
+}
+
+// firingStreams returns one sample stream for each offset, every stream is 2m long.
+func firingStreams(labels map[string]string, offsets ...time.Duration) []*model.SampleStream {
+	streams := make([]*model.SampleStream, 0, len(offsets))
+	for _, offset := range offsets {
+		start := time.Now().Add(offset * -1)
+		streams = append(streams, generateSampleStream(labels, start, start.Add(time.Minute), time.Minute))
+	}
+	return streams
+}
+
+func TestAlertsFlappingCheck(t *testing.T) {
+	content := "- alert: Foo Is Down\n  expr: up{job=\"foo\"} == 0\n"
+	labels := map[string]string{"__name__": "ALERTS", "alertname": "Foo Is Down", "alertstate": "firing", "job": "foo", "instance": "a"}
+
+	testCases := []checkTest{
+		{
+			description: "ignores recording rules",
+			content:     "- record: foo\n  expr: up == 0\n",
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- alert: Foo Is Down\n  expr: sum(\n",
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "bad request",
+			content:     content,
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS{alertname="Foo Is Down",alertstate="firing"}`},
+					},
+					resp: respondWithBadData(),
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "connection refused",
+			content:     content,
+			checker:     newAlertsFlappingCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return simpleProm("prom", "http://127.0.0.1:1111", time.Second*5, false)
+			},
+			problems: true,
+		},
+		{
+			description: "no alerts",
+			content:     content,
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS{alertname="Foo Is Down",alertstate="firing"}`},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS_FOR_STATE{alertname="Foo Is Down"}`},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+			},
+		},
+		{
+			description: "flaps below maxFlaps",
+			content:     content,
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS{alertname="Foo Is Down",alertstate="firing"}`},
+					},
+					resp: matrixResponse{
+						samples: firingStreams(labels, time.Hour*20, time.Hour*19, time.Hour*17),
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nup\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+			},
+		},
+		{
+			description: "flapping alert",
+			content:     content,
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS{alertname="Foo Is Down",alertstate="firing"}`},
+					},
+					resp: matrixResponse{
+						samples: append(
+							firingStreams(labels, time.Hour*20, time.Hour*19, time.Hour*17, time.Hour*14, time.Hour*10),
+							generateSampleStream(
+								map[string]string{"__name__": "ALERTS", "alertname": "Foo Is Down", "alertstate": "firing", "job": "foo", "instance": "b"},
+								time.Now().Add(time.Hour*-10),
+								time.Now().Add(time.Hour*-8),
+								time.Minute,
+							),
+						),
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nup\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "flapping alert / for and keep_firing_for",
+			content:     "- alert: Foo Is Down\n  for: 5m\n  keep_firing_for: 10m\n  expr: up{job=\"foo\"} == 0\n",
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS{alertname="Foo Is Down",alertstate="firing"}`},
+					},
+					resp: matrixResponse{
+						samples: firingStreams(labels, time.Hour*20, time.Hour*19, time.Hour*17, time.Hour*14, time.Hour*10),
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nup\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "flapping alert / ALERTS_FOR_STATE",
+			content:     content,
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS{alertname="Foo Is Down",alertstate="firing"}`},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS_FOR_STATE{alertname="Foo Is Down"}`},
+					},
+					resp: matrixResponse{
+						samples: firingStreams(
+							map[string]string{"__name__": "ALERTS_FOR_STATE", "alertname": "Foo Is Down", "job": "foo"},
+							time.Hour*20, time.Hour*19, time.Hour*18, time.Hour*17,
+						),
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nup\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "Prometheus restarts are not flaps",
+			content:     content,
+			checker:     newAlertsFlappingCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: `ALERTS{alertname="Foo Is Down",alertstate="firing"}`},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							generateSampleStream(labels, time.Now().Add(time.Hour*-20), time.Now().Add(time.Hour*-18), time.Minute),
+							generateSampleStream(labels, time.Now().Add(time.Hour*-17), time.Now().Add(time.Hour*-15), time.Minute),
+							generateSampleStream(labels, time.Now().Add(time.Hour*-14), time.Now().Add(time.Hour*-12), time.Minute),
+							generateSampleStream(labels, time.Now().Add(time.Hour*-11), time.Now().Add(time.Hour*-9), time.Minute),
+						},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nup\n)"},
+					},
+					resp: matrixResponse{
+						samples: []*model.SampleStream{
+							generateSampleStream(map[string]string{}, time.Now().Add(time.Hour*-24), time.Now().Add(time.Hour*-18), time.Minute),
+							generateSampleStream(map[string]string{}, time.Now().Add(time.Hour*-17), time.Now().Add(time.Hour*-15), time.Minute),
+							generateSampleStream(map[string]string{}, time.Now().Add(time.Hour*-14), time.Now().Add(time.Hour*-12), time.Minute),
+							generateSampleStream(map[string]string{}, time.Now().Add(time.Hour*-11), time.Now(), time.Minute),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runTests(t, testCases)
+}
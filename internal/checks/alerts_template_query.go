@@ -33,6 +33,10 @@ type templateQueryUse struct {
 // checkTemplateQueries checks queries passed to the query function for syntax
 // errors and for labels that the query can't return.
 func (c TemplateCheck) checkTemplateQueries(rule parser.Rule, label *parser.YamlKeyValue, meta templateMeta) (problems []Problem) {
+	if rule.Expr().IsLogQL() {
+		// Loki rules would run LogQL queries, we can't parse those as PromQL.
+		return nil
+	}
 	for _, use := range meta.uses {
 		query, err := parser.DecodeExpr(use.query.expr)
 		if err != nil {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/promapi"
 )
 
@@ -1277,6 +1278,32 @@ func TestTemplateCheck(t *testing.T) {
 			checker:    newTemplateCheck,
 			prometheus: noProm,
 		},
+		{
+			description: "LogQL query with valid templates",
+			content: `
+- alert: Foo
+  expr: sum by(app) (rate({app="foo"} |= "error" [5m])) > 0
+  annotations:
+    summary: "{{ $labels.app }} is logging {{ $value }} errors per second"
+    details: '{{ with query "sum(rate({app=\"foo\"}[5m]))" }}{{ . | first | value }}{{ end }}'
+`,
+			checker:       newTemplateCheck,
+			prometheus:    noProm,
+			contentSchema: parser.LokiSchema,
+		},
+		{
+			description: "LogQL query with broken template",
+			content: `
+- alert: Foo
+  expr: sum by(app) (rate({app="foo"} |= "error" [5m])) > 0
+  annotations:
+    summary: "{{ $labels.app }"
+`,
+			checker:       newTemplateCheck,
+			prometheus:    noProm,
+			contentSchema: parser.LokiSchema,
+			problems:      true,
+		},
 	}
 	runTests(t, testCases)
 }
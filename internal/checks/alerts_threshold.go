@@ -0,0 +1,323 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/promapi"
+
+	"github.com/prometheus/common/model"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	AlertsThresholdCheckName = "alerts/threshold"
+)
+
+func NewAlertsThresholdCheck(prom *promapi.FailoverGroup, lookBack, step time.Duration, factor float64, comment string, severity Severity) AlertsThresholdCheck {
+	return AlertsThresholdCheck{
+		prom:     prom,
+		lookBack: lookBack,
+		step:     step,
+		factor:   factor,
+		comment:  comment,
+		severity: severity,
+		instance: fmt.Sprintf("%s(%s)", AlertsThresholdCheckName, prom.Name()),
+	}
+}
+
+type AlertsThresholdCheck struct {
+	prom     *promapi.FailoverGroup
+	comment  string
+	instance string
+	lookBack time.Duration
+	step     time.Duration
+	factor   float64
+	severity Severity
+}
+
+func (c AlertsThresholdCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        true,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c AlertsThresholdCheck) String() string {
+	return c.instance
+}
+
+func (c AlertsThresholdCheck) Reporter() string {
+	return AlertsThresholdCheckName
+}
+
+func (c AlertsThresholdCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	if entry.Rule.AlertingRule == nil {
+		return problems
+	}
+
+	if entry.Rule.AlertingRule.Expr.SyntaxError() != nil {
+		return problems
+	}
+
+	params := promapi.NewRelativeRange(c.lookBack, c.step)
+	root := entry.Rule.AlertingRule.Expr.Query().Expr
+	for _, th := range findThresholds(root) {
+		if expr, ok := root.(promParser.Expr); ok {
+			th.isWhole = unwrapParens(expr) == th.node
+		}
+		problem, ok, err := c.checkThreshold(ctx, entry, th, params)
+		if err != nil {
+			problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+			return problems
+		}
+		if ok {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+func (c AlertsThresholdCheck) checkThreshold(ctx context.Context, entry *discovery.Entry, th threshold, params promapi.RangeQueryTimes) (Problem, bool, error) {
+	qr, err := c.prom.RangeQuery(ctx, wrapExpr(th.lhs.String(), "count"), params).Wait()
+	if err != nil {
+		return Problem{}, false, err
+	}
+	// No data to compare the threshold with, promql/series will report missing metrics.
+	if len(qr.Series.Ranges) == 0 {
+		return Problem{}, false, nil
+	}
+
+	delta := output.HumanizeDuration(qr.Series.Until.Sub(qr.Series.From).Round(time.Minute))
+
+	// Check if there was any moment when the condition wasn't true.
+	qr, err = c.prom.RangeQuery(ctx, th.query(negateComparison(th.op), th.value), params).Wait()
+	if err != nil {
+		return Problem{}, false, err
+	}
+	if len(qr.Series.Ranges) == 0 {
+		outcome := "this condition is always true"
+		if th.isWhole {
+			outcome = "this alert will always fire"
+		}
+		observed, err := c.observedValues(ctx, th)
+		if err != nil {
+			return Problem{}, false, err
+		}
+		return c.newProblem(entry, th, "always satisfied alert threshold", fmt.Sprintf(
+			"%s shows that `%s` was always `%s %s` in the last %s, so %s.%s",
+			promText(c.prom.Name(), qr.URI), th.lhs, th.op, formatThreshold(th.value), delta, outcome, observed,
+		)), true, nil
+	}
+
+	// Check if observed values were ever close to the threshold.
+	margin, ok := th.margin(c.factor)
+	if !ok {
+		return Problem{}, false, nil
+	}
+	qr, err = c.prom.RangeQuery(ctx, th.query(th.op, margin), params).Wait()
+	if err != nil {
+		return Problem{}, false, err
+	}
+	if len(qr.Series.Ranges) == 0 {
+		outcome := "this condition will never be true"
+		if th.isWhole {
+			outcome = "this alert will never fire"
+		}
+		observed, err := c.observedValues(ctx, th)
+		if err != nil {
+			return Problem{}, false, err
+		}
+		return c.newProblem(entry, th, "alert threshold outside of observed values", fmt.Sprintf(
+			"%s shows that `%s` was never `%s %s` in the last %s, `%s` threshold is over %s times away from observed values, so %s.%s",
+			promText(c.prom.Name(), qr.URI), th.lhs, th.op, formatThreshold(margin), delta, formatThreshold(th.value), formatThreshold(c.factor), outcome, observed,
+		)), true, nil
+	}
+
+	return Problem{}, false, nil
+}
+
+// observedQuantiles are the quantiles of observed values included in reports.
+var observedQuantiles = []float64{0.5, 0.9, 0.99}
+
+// observedValues returns a sentence with the lowest, the highest and the quantiles
+// of values returned by the query, or an empty string if there's no data.
+func (c AlertsThresholdCheck) observedValues(ctx context.Context, th threshold) (string, error) {
+	window := fmt.Sprintf("[%s:%s]", model.Duration(c.lookBack), model.Duration(c.step))
+	reqs := make([]*promapi.Request[*promapi.QueryResult], 0, len(observedQuantiles)+2)
+	reqs = append(reqs,
+		c.prom.Query(ctx, fmt.Sprintf("min_over_time(min(%s)%s)", th.lhs, window)),
+		c.prom.Query(ctx, fmt.Sprintf("max_over_time(max(%s)%s)", th.lhs, window)),
+	)
+	for _, q := range observedQuantiles {
+		reqs = append(reqs, c.prom.Query(ctx, fmt.Sprintf("quantile_over_time(%s, quantile(%s, %s)%s)", formatThreshold(q), formatThreshold(q), th.lhs, window)))
+	}
+
+	values := make([]float64, 0, len(reqs))
+	var err error
+	for _, req := range reqs {
+		qr, qerr := req.Wait()
+		switch {
+		case qerr != nil:
+			err = qerr
+		case err == nil && len(qr.Series) > 0:
+			values = append(values, float64(qr.Series[0].Value))
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(values) != len(reqs) {
+		return "", nil
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, " Observed values were between `%s` and `%s`", formatThreshold(values[0]), formatThreshold(values[1]))
+	for i, q := range observedQuantiles {
+		if i == len(observedQuantiles)-1 {
+			buf.WriteString(" and")
+		} else {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, " %s%% of them were below `%s`", formatThreshold(math.Round(q*100)), formatThreshold(values[i+2]))
+	}
+	buf.WriteString(".")
+	return buf.String(), nil
+}
+
+func (c AlertsThresholdCheck) newProblem(entry *discovery.Entry, th threshold, summary, msg string) Problem {
+	details := "Alert thresholds should be set based on values this query is expected to return."
+	if c.comment != "" {
+		details = fmt.Sprintf("%s\n%s", details, maybeComment(c.comment))
+	}
+	return Problem{
+		Anchor:   AnchorAfter,
+		Lines:    entry.Rule.AlertingRule.Expr.Value.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  summary,
+		Details:  details,
+		Severity: c.severity,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message:     msg,
+				Pos:         entry.Rule.AlertingRule.Expr.Value.Pos,
+				Expr:        entry.Rule.AlertingRule.Expr.Query().Expr,
+				FirstColumn: int(th.node.PositionRange().Start) + 1,
+				LastColumn:  int(th.node.PositionRange().End),
+				Kind:        diags.Issue,
+			},
+		},
+	}
+}
+
+// threshold is a comparison between a query and a number, like `foo > 5`.
+// If the number is on the left side the operator is swapped, so `5 < foo`
+// becomes `foo > 5`.
+type threshold struct {
+	node  *promParser.BinaryExpr
+	lhs   promParser.Expr
+	op    promParser.ItemType
+	value float64
+	// True if this comparison is the entire alerting rule query.
+	isWhole bool
+}
+
+func (th threshold) query(op promParser.ItemType, value float64) string {
+	return fmt.Sprintf("%s %s %s", th.lhs, op, formatThreshold(value))
+}
+
+// margin returns the value that is factor times closer to observed values
+// than the threshold. If the query never passes it then the threshold is
+// too far away from observed values.
+// It only works with positive values, since we can't tell what's the
+// magnitude of observed values otherwise.
+func (th threshold) margin(factor float64) (float64, bool) {
+	if th.value <= 0 || factor <= 1 {
+		return 0, false
+	}
+	switch th.op {
+	case promParser.GTR, promParser.GTE:
+		return th.value / factor, true
+	case promParser.LSS, promParser.LTE:
+		return th.value * factor, true
+	default:
+		return 0, false
+	}
+}
+
+// findThresholds returns all comparisons between a query and a number
+// that will decide if the alert fires.
+func findThresholds(node promParser.Node) (thresholds []threshold) {
+	switch n := node.(type) {
+	case *promParser.ParenExpr:
+		return findThresholds(n.Expr)
+	case *promParser.BinaryExpr:
+		switch {
+		case n.Op == promParser.LAND, n.Op == promParser.LOR:
+			thresholds = append(thresholds, findThresholds(n.LHS)...)
+			thresholds = append(thresholds, findThresholds(n.RHS)...)
+		case n.Op == promParser.LUNLESS:
+			thresholds = append(thresholds, findThresholds(n.LHS)...)
+		case n.Op.IsComparisonOperator() && !n.ReturnBool:
+			if n.Op == promParser.EQLC || n.Op == promParser.NEQ {
+				return thresholds
+			}
+			if v, ok := unwrapParens(n.RHS).(*promParser.NumberLiteral); ok && n.LHS.Type() == promParser.ValueTypeVector {
+				thresholds = append(thresholds, threshold{node: n, lhs: n.LHS, op: n.Op, value: v.Val, isWhole: false})
+			}
+			if v, ok := unwrapParens(n.LHS).(*promParser.NumberLiteral); ok && n.RHS.Type() == promParser.ValueTypeVector {
+				thresholds = append(thresholds, threshold{node: n, lhs: n.RHS, op: swapComparison(n.Op), value: v.Val, isWhole: false})
+			}
+		}
+	}
+	return thresholds
+}
+
+// swapComparison returns the operator to use when both sides of a comparison are swapped.
+func swapComparison(op promParser.ItemType) promParser.ItemType {
+	switch op {
+	case promParser.GTR:
+		return promParser.LSS
+	case promParser.GTE:
+		return promParser.LTE
+	case promParser.LSS:
+		return promParser.GTR
+	case promParser.LTE:
+		return promParser.GTE
+	default:
+		return op
+	}
+}
+
+// negateComparison returns the operator that matches values not matched by op.
+func negateComparison(op promParser.ItemType) promParser.ItemType {
+	switch op {
+	case promParser.GTR:
+		return promParser.LTE
+	case promParser.GTE:
+		return promParser.LSS
+	case promParser.LSS:
+		return promParser.GTE
+	case promParser.LTE:
+		return promParser.GTR
+	default:
+		return op
+	}
+}
+
+func formatThreshold(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,556 @@
+package checks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+
+	"github.com/prometheus/common/model"
+)
+
+func newAlertsThresholdCheck(prom *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewAlertsThresholdCheck(prom, time.Hour*24, time.Minute, 10, "", checks.Warning)
+}
+
+func newAlertsThresholdCheckWithComment(prom *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewAlertsThresholdCheck(prom, time.Hour*24, time.Minute, 100, "some text", checks.Bug)
+}
+
+func TestAlertsThresholdCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores recording rules",
+			content:     "- record: foo\n  expr: latency_seconds > 5000\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- alert: Foo Is Down\n  expr: sum(\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores alerts without a number threshold",
+			content:     "- alert: Foo\n  expr: foo > bar\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores equality comparisons",
+			content:     "- alert: Foo\n  expr: up == 0\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores bool comparisons",
+			content:     "- alert: Foo\n  expr: latency_seconds > bool 5000\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "bad request",
+			content:     "- alert: Foo\n  expr: latency_seconds > 5000\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nlatency_seconds\n)"},
+					},
+					resp: respondWithBadData(),
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "connection refused",
+			content:     "- alert: Foo\n  expr: latency_seconds > 5000\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return simpleProm("prom", "http://127.0.0.1:1111", time.Second*5, false)
+			},
+			problems: true,
+		},
+		{
+			description: "no data",
+			content:     "- alert: Foo\n  expr: latency_seconds > 5000\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nlatency_seconds\n)"},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+			},
+		},
+		{
+			description: "threshold within observed values",
+			content:     "- alert: Foo\n  expr: latency_seconds > 5\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nlatency_seconds\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "latency_seconds <= 5"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "latency_seconds > 0.5"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+			},
+		},
+		{
+			description: "threshold above observed values",
+			content:     "- alert: Foo\n  expr: latency_seconds > 5000\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nlatency_seconds\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "latency_seconds <= 5000"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "latency_seconds > 500"},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "min_over_time(min(latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 12.5)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "max_over_time(max(latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 48)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.5, quantile(0.5, latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 20)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.9, quantile(0.9, latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 40)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.99, quantile(0.99, latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 47)},
+					},
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "threshold below observed values",
+			content:     "- alert: Foo\n  expr: disk_free_ratio < 0.001\n",
+			checker:     newAlertsThresholdCheckWithComment,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\ndisk_free_ratio\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "disk_free_ratio >= 0.001"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "disk_free_ratio < 0.1"},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "min_over_time(min(disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.2)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "max_over_time(max(disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.9)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.5, quantile(0.5, disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.5)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.9, quantile(0.9, disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.8)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.99, quantile(0.99, disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.85)},
+					},
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "always satisfied threshold",
+			content:     "- alert: Foo\n  expr: disk_free_ratio < 1.5\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\ndisk_free_ratio\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "disk_free_ratio >= 1.5"},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "min_over_time(min(disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.2)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "max_over_time(max(disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.9)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.5, quantile(0.5, disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.5)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.9, quantile(0.9, disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.8)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.99, quantile(0.99, disk_free_ratio)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 0.85)},
+					},
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "always satisfied threshold with number on the left",
+			content:     "- alert: Foo\n  expr: 1.5 > disk_free_ratio\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\ndisk_free_ratio\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "disk_free_ratio >= 1.5"},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "min_over_time(min(disk_free_ratio)[1d:1m])"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "max_over_time(max(disk_free_ratio)[1d:1m])"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.5, quantile(0.5, disk_free_ratio)[1d:1m])"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.9, quantile(0.9, disk_free_ratio)[1d:1m])"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.99, quantile(0.99, disk_free_ratio)[1d:1m])"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+			},
+			problems: true,
+		},
+		{
+			description: "negative threshold is only checked if always satisfied",
+			content:     "- alert: Foo\n  expr: deriv(foo[5m]) < -10\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nderiv(foo[5m])\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "deriv(foo[5m]) >= -10"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+			},
+		},
+		{
+			description: "multiple thresholds",
+			content:     "- alert: Foo\n  expr: (latency_seconds > 5000 and rate(requests_total[5m]) > 1) unless disk_free_ratio < 0.1\n",
+			checker:     newAlertsThresholdCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nlatency_seconds\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "latency_seconds <= 5000"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "latency_seconds > 500"},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "count(\nrate(requests_total[5m])\n)"},
+					},
+					resp: respondWithSingleRangeVector1D(),
+				},
+				{
+					conds: []requestCondition{
+						requireRangeQueryPath,
+						formCond{key: "query", value: "rate(requests_total[5m]) <= 1"},
+					},
+					resp: respondWithEmptyMatrix(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "min_over_time(min(latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 12.5)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "max_over_time(max(latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 48)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.5, quantile(0.5, latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 20)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.9, quantile(0.9, latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 40)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.99, quantile(0.99, latency_seconds)[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 47)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "min_over_time(min(rate(requests_total[5m]))[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "max_over_time(max(rate(requests_total[5m]))[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 5)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.5, quantile(0.5, rate(requests_total[5m]))[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 3)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.9, quantile(0.9, rate(requests_total[5m]))[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 4)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "quantile_over_time(0.99, quantile(0.99, rate(requests_total[5m]))[1d:1m])"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 5)},
+					},
+				},
+			},
+			problems: true,
+		},
+	}
+	runTests(t, testCases)
+}
@@ -20,15 +20,23 @@ var (
 		AnnotationCheckName,
 		ComparisonCheckName,
 		AlertsCheckName,
+		AlertsDuplicateCheckName,
 		AlertsExternalLabelsCheckName,
+		AlertsFlappingCheckName,
 		AlertForCheckName,
 		TemplateCheckName,
+		AlertsThresholdCheckName,
 		LabelsConflictCheckName,
 		AggregationCheckName,
 		CounterCheckName,
+		CustomCheckName,
+		DivisionCheckName,
 		FeaturesCheckName,
 		FragileCheckName,
 		GroupIntervalCheckName,
+		GroupLimitCheckName,
+		GroupQueryOffsetCheckName,
+		HistogramCheckName,
 		ImpossibleCheckName,
 		NaNCheckName,
 		OffsetCheckName,
@@ -37,33 +45,57 @@ var (
 		RegexpCheckName,
 		SelectorCheckName,
 		SeriesCheckName,
+		SubqueryCheckName,
 		SyntaxCheckName,
 		VectorMatchingCheckName,
 		CostCheckName,
+		CardinalityCheckName,
 		RuleDependencyCheckName,
 		RuleDuplicateCheckName,
 		RuleForCheckName,
 		LabelCheckName,
 		RuleLinkCheckName,
 		RuleNameCheckName,
+		RuleNamingCheckName,
 		RejectCheckName,
 		ReportCheckName,
 	}
 	OnlineChecks = []string{
 		AlertsAbsentCheckName,
 		AlertsCheckName,
+		AlertsDuplicateCheckName,
 		AlertsExternalLabelsCheckName,
+		AlertsFlappingCheckName,
+		AlertsThresholdCheckName,
 		LabelsConflictCheckName,
 		CounterCheckName,
 		FeaturesCheckName,
+		GroupLimitCheckName,
+		GroupQueryOffsetCheckName,
 		OffsetCheckName,
 		RangeQueryCheckName,
 		RateCheckName,
 		SeriesCheckName,
+		SubqueryCheckName,
 		VectorMatchingCheckName,
 		CostCheckName,
+		CardinalityCheckName,
 		RuleLinkCheckName,
 	}
+	// LogQLChecks is the list of checks that can be used with Loki rules.
+	LogQLChecks = []string{
+		AnnotationCheckName,
+		AlertsCheckName,
+		AlertForCheckName,
+		TemplateCheckName,
+		SyntaxCheckName,
+		RuleForCheckName,
+		LabelCheckName,
+		RuleLinkCheckName,
+		RuleNameCheckName,
+		RejectCheckName,
+		ReportCheckName,
+	}
 )
 
 // Severity of the problem reported.
@@ -195,6 +227,13 @@ func promText(name, uri string) string {
 	return fmt.Sprintf("`%s` Prometheus server at %s", name, uri)
 }
 
+func configDetails(name string, cfg *promapi.ConfigResult) string {
+	if cfg.Path != "" {
+		return fmt.Sprintf("`%s` Prometheus configuration was loaded from `%s`.", name, cfg.Path)
+	}
+	return fmt.Sprintf("[Click here](%s/config) to see `%s` Prometheus runtime configuration.", cfg.URI, name)
+}
+
 func retentionFromFlags(flags map[string]string, reporter string, expr *parser.PromQLExpr) (time.Duration, *Problem) {
 	var retention time.Duration
 	if v, ok := flags["storage.tsdb.retention.time"]; ok {
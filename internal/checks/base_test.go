@@ -101,7 +101,7 @@ func simpleProm(name, uri string, timeout time.Duration, required bool) *promapi
 		name,
 		uri,
 		[]*promapi.Prometheus{
-			promapi.NewPrometheus(name, uri, simplePromPublicURI, map[string]string{"X-Debug": "1"}, timeout, 16, 1000, nil),
+			promapi.NewPrometheus(name, uri, simplePromPublicURI, map[string]string{"X-Debug": "1"}, timeout, 16, 1000, 0, nil),
 		},
 		required,
 		"up",
@@ -138,10 +138,12 @@ type checkTest struct {
 	setup         func(t *testing.T)
 	description   string
 	content       string
+	before        string
 	entries       []*discovery.Entry
 	mocks         []*prometheusMock
 	problems      bool
 	contentStrict bool
+	contentSchema parser.Schema
 }
 
 type Snapshot struct {
@@ -213,8 +215,19 @@ func runTests(t *testing.T, testCases []checkTest) {
 			}
 
 			ctx, cancel := context.WithCancel(t.Context())
-			entries, err := parseContent(tc.content, parser.DefaultOptions.WithStrict(tc.contentStrict), nil)
+			opts := parser.DefaultOptions.WithStrict(tc.contentStrict)
+			opts.Schema = tc.contentSchema
+			entries, err := parseContent(tc.content, opts, nil)
 			require.NoError(t, err, "cannot parse rule content")
+			if tc.before != "" {
+				before, err := parseContent(tc.before, opts, nil)
+				require.NoError(t, err, "cannot parse rule content before")
+				require.Len(t, before, len(entries), "rule content before must have the same number of rules")
+				for i, entry := range entries {
+					entry.State = discovery.Modified
+					entry.Before = &before[i].Rule
+				}
+			}
 			for _, entry := range entries {
 				if tc.ctx != nil {
 					ctx = tc.ctx(ctx, uri)
@@ -416,6 +429,7 @@ var (
 	requireQueryPath      = requestPathCond{path: promapi.APIPathQuery}
 	requireRangeQueryPath = requestPathCond{path: promapi.APIPathQueryRange}
 	requireMetadataPath   = requestPathCond{path: promapi.APIPathMetadata}
+	requireTSDBPath       = requestPathCond{path: promapi.APIPathTSDB}
 )
 
 type httpResponse struct {
@@ -625,6 +639,27 @@ func (mr metadataResponse) respond(w http.ResponseWriter, _ *http.Request) {
 	_, _ = w.Write(d)
 }
 
+type tsdbResponse struct {
+	stats v1.TSDBResult
+}
+
+func (tr tsdbResponse) respond(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	result := struct {
+		Data   v1.TSDBResult `json:"data"`
+		Status string        `json:"status"`
+	}{
+		Status: "success",
+		Data:   tr.stats,
+	}
+	d, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	_, _ = w.Write(d)
+}
+
 type sleepResponse struct {
 	resp  responseWriter
 	sleep time.Duration
@@ -650,6 +685,9 @@ var (
 	respondWithTimeoutExpandingSeriesSamples = func() responseWriter {
 		return promError{code: 422, errorType: v1.ErrExec, err: "expanding series: context deadline exceeded"}
 	}
+	respondWithDuplicateSeries = func() responseWriter {
+		return promError{code: 422, errorType: v1.ErrExec, err: "found duplicate series for the match group {job=\"a\"} on the right hand-side of the operation: [{job=\"a\", instance=\"b\"}, {job=\"a\", instance=\"c\"}];many-to-many matching not allowed: matching labels must be unique on one side"}
+	}
 	respondWithEmptyVector = func() responseWriter {
 		return vectorResponse{samples: model.Vector{}}
 	}
@@ -2,21 +2,38 @@ package checks
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/cloudflare/pint/internal/diags"
 	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/promapi"
 )
 
 const (
 	GroupIntervalCheckName = "group/interval"
 )
 
-func NewGroupIntervalCheck() GroupIntervalCheck {
-	return GroupIntervalCheck{}
+// NewGroupIntervalCheck creates a new group/interval check.
+// If prom is nil then only explicitly set group intervals are checked,
+// otherwise it checks groups without an interval using the evaluation_interval
+// from the Prometheus configuration.
+func NewGroupIntervalCheck(prom *promapi.FailoverGroup) GroupIntervalCheck {
+	instance := GroupIntervalCheckName
+	if prom != nil {
+		instance = fmt.Sprintf("%s(%s)", GroupIntervalCheckName, prom.Name())
+	}
+	return GroupIntervalCheck{
+		prom:     prom,
+		instance: instance,
+	}
 }
 
-type GroupIntervalCheck struct{}
+type GroupIntervalCheck struct {
+	prom     *promapi.FailoverGroup
+	instance string
+}
 
 func (c GroupIntervalCheck) Meta() CheckMeta {
 	return CheckMeta{
@@ -32,26 +49,24 @@ func (c GroupIntervalCheck) Meta() CheckMeta {
 }
 
 func (c GroupIntervalCheck) String() string {
-	return GroupIntervalCheckName
+	return c.instance
 }
 
 func (c GroupIntervalCheck) Reporter() string {
 	return GroupIntervalCheckName
 }
 
-func (c GroupIntervalCheck) Check(_ context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
-	if entry.Group.Interval == nil {
-		return nil
+func (c GroupIntervalCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	if c.prom != nil {
+		return c.checkEvaluationInterval(ctx, entry)
 	}
 
-	if entry.Group.Interval.Value <= time.Minute*5 {
+	if entry.Group.Interval == nil {
 		return nil
 	}
 
-	if entry.Rule.AlertingRule != nil && entry.Rule.AlertingRule.KeepFiringFor != nil && entry.Rule.AlertingRule.KeepFiringFor.ParseError == nil {
-		if entry.Rule.AlertingRule.KeepFiringFor.Value >= entry.Group.Interval.Value {
-			return nil
-		}
+	if !isIntervalTooLong(entry, entry.Group.Interval.Value) {
+		return nil
 	}
 
 	problems = append(problems, Problem{
@@ -75,3 +90,61 @@ func (c GroupIntervalCheck) Check(_ context.Context, entry *discovery.Entry, _ [
 
 	return problems
 }
+
+func (c GroupIntervalCheck) checkEvaluationInterval(ctx context.Context, entry *discovery.Entry) (problems []Problem) {
+	if entry.Group.Interval != nil {
+		return nil
+	}
+
+	expr := entry.Rule.Expr()
+	if expr.SyntaxError() != nil {
+		return nil
+	}
+
+	cfg, err := c.prom.Config(ctx, 0).Wait()
+	if err != nil {
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+		return problems
+	}
+
+	interval := cfg.Config.Global.EvaluationInterval
+	if !isIntervalTooLong(entry, interval) {
+		return nil
+	}
+
+	problems = append(problems, Problem{
+		Anchor:   AnchorAfter,
+		Lines:    expr.Value.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  "interval too long",
+		Details:  configDetails(c.prom.Name(), cfg),
+		Severity: Warning,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message: fmt.Sprintf("This group doesn't set an interval so it will use the global:evaluation_interval of %s, which is %s. Using group interval > 5m will cause gaps in recording rule results and flapping alerts.",
+					promText(c.prom.Name(), cfg.URI), output.HumanizeDuration(interval)),
+				Pos:         expr.Value.Pos,
+				Expr:        nil,
+				FirstColumn: 1,
+				LastColumn:  len(expr.Value.Value),
+				Kind:        diags.Issue,
+			},
+		},
+	})
+
+	return problems
+}
+
+func isIntervalTooLong(entry *discovery.Entry, interval time.Duration) bool {
+	if interval <= time.Minute*5 {
+		return false
+	}
+
+	if entry.Rule.AlertingRule != nil && entry.Rule.AlertingRule.KeepFiringFor != nil && entry.Rule.AlertingRule.KeepFiringFor.ParseError == nil {
+		if entry.Rule.AlertingRule.KeepFiringFor.Value >= interval {
+			return false
+		}
+	}
+
+	return true
+}
@@ -2,13 +2,34 @@ package checks_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/promapi"
 )
 
 func newGroupIntervalCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
-	return checks.NewGroupIntervalCheck()
+	return checks.NewGroupIntervalCheck(nil)
+}
+
+func newGroupIntervalPromCheck(prom *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewGroupIntervalCheck(prom)
+}
+
+func newLocalConfigProm(evaluationInterval time.Duration) func(string) *promapi.FailoverGroup {
+	return func(_ string) *promapi.FailoverGroup {
+		prom := promapi.NewFailoverGroup("prom", "prometheus.yml", nil, false, "up", nil, nil, nil)
+		prom.SetLocalConfig(&promapi.ConfigResult{
+			URI:  "prometheus.yml",
+			Path: "prometheus.yml",
+			Config: promapi.PrometheusConfig{
+				Global: promapi.ConfigSectionGlobal{
+					EvaluationInterval: evaluationInterval,
+				},
+			},
+		})
+		return prom
+	}
 }
 
 func TestGroupIntervalCheck(t *testing.T) {
@@ -154,6 +175,55 @@ func TestGroupIntervalCheck(t *testing.T) {
 			prometheus: noProm,
 			problems:   true,
 		},
+		{
+			// Groups without interval use global:evaluation_interval from local configuration.
+			description: "global evaluation_interval above 5m",
+			content: `
+- name: test
+  rules:
+  - alert: foo
+    expr: foo > 0
+`,
+			checker:    newGroupIntervalPromCheck,
+			prometheus: newLocalConfigProm(time.Minute * 10),
+			problems:   true,
+		},
+		{
+			description: "global evaluation_interval below 5m",
+			content: `
+- name: test
+  rules:
+  - alert: foo
+    expr: foo > 0
+`,
+			checker:    newGroupIntervalPromCheck,
+			prometheus: newLocalConfigProm(time.Minute),
+		},
+		{
+			// Explicit group interval is handled by the global check instance.
+			description: "global evaluation_interval ignored when group sets interval",
+			content: `
+- name: test
+  interval: 1m
+  rules:
+  - alert: foo
+    expr: foo > 0
+`,
+			checker:    newGroupIntervalPromCheck,
+			prometheus: newLocalConfigProm(time.Minute * 10),
+		},
+		{
+			description: "global evaluation_interval covered by keep_firing_for",
+			content: `
+- name: test
+  rules:
+  - alert: foo
+    expr: foo > 0
+    keep_firing_for: 10m
+`,
+			checker:    newGroupIntervalPromCheck,
+			prometheus: newLocalConfigProm(time.Minute * 10),
+		},
 	}
 	runTests(t, testCases)
 }
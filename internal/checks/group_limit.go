@@ -0,0 +1,104 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	GroupLimitCheckName = "group/limit"
+
+	GroupLimitCheckDetails = `Rule groups can set a ` + "`limit`" + ` on the number of series a recording rule and alerts an alerting rule can produce.
+If a rule returns more results than the limit allows then the rule evaluation will fail and all its results will be discarded.
+See [Prometheus docs](https://prometheus.io/docs/prometheus/latest/configuration/recording_rules/#limiting-alerts-and-series) for details.`
+)
+
+func NewGroupLimitCheck(prom *promapi.FailoverGroup) GroupLimitCheck {
+	return GroupLimitCheck{
+		prom:     prom,
+		instance: fmt.Sprintf("%s(%s)", GroupLimitCheckName, prom.Name()),
+	}
+}
+
+type GroupLimitCheck struct {
+	prom     *promapi.FailoverGroup
+	instance string
+}
+
+func (c GroupLimitCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        true,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c GroupLimitCheck) String() string {
+	return c.instance
+}
+
+func (c GroupLimitCheck) Reporter() string {
+	return GroupLimitCheckName
+}
+
+func (c GroupLimitCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	if entry.Group == nil || entry.Group.Limit == nil || entry.Group.Limit.ParseError != nil || entry.Group.Limit.Value <= 0 {
+		return problems
+	}
+
+	expr := entry.Rule.Expr()
+	if expr.SyntaxError() != nil {
+		return problems
+	}
+
+	qr, err := c.prom.Query(ctx, wrapExpr(expr.Value.Value, "count")).Wait()
+	if err != nil {
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+		return problems
+	}
+
+	var results int
+	for _, s := range qr.Series {
+		results = int(s.Value)
+		break
+	}
+	if results <= entry.Group.Limit.Value {
+		return problems
+	}
+
+	outcome := "this recording rule will fail and won't produce any results"
+	if entry.Rule.AlertingRule != nil {
+		outcome = "this alerting rule will fail and won't produce any alerts"
+	}
+
+	problems = append(problems, Problem{
+		Anchor:   AnchorAfter,
+		Lines:    expr.Value.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  "rule results exceed group limit",
+		Details:  GroupLimitCheckDetails,
+		Severity: Bug,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message: fmt.Sprintf("%s returned %d result(s) for this query, but this group sets `limit: %d`, %s.",
+					promText(c.prom.Name(), qr.URI), results, entry.Group.Limit.Value, outcome),
+				Pos:         expr.Value.Pos,
+				Expr:        expr.Query().Expr,
+				FirstColumn: 1,
+				LastColumn:  len(expr.Value.Value),
+				Kind:        diags.Issue,
+			},
+		},
+	})
+
+	return problems
+}
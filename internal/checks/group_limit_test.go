@@ -0,0 +1,186 @@
+package checks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newGroupLimitCheck(prom *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewGroupLimitCheck(prom)
+}
+
+func TestGroupLimitCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores groups without limit",
+			content:     "- record: foo\n  expr: sum(foo) by (job)\n",
+			checker:     newGroupLimitCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores rules with syntax errors",
+			content: `
+- name: test
+  limit: 10
+  rules:
+  - record: foo
+    expr: sum(foo) without(
+`,
+			checker:    newGroupLimitCheck,
+			prometheus: newSimpleProm,
+		},
+		{
+			description: "ignores zero limit",
+			content: `
+- name: test
+  limit: 0
+  rules:
+  - record: foo
+    expr: sum(foo) by (job)
+`,
+			checker:    newGroupLimitCheck,
+			prometheus: newSimpleProm,
+		},
+		{
+			description: "query error",
+			content: `
+- name: test
+  limit: 10
+  rules:
+  - record: foo
+    expr: sum(foo) by (job)
+`,
+			checker:    newGroupLimitCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nsum(foo) by (job)\n)"},
+					},
+					resp: respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "connection refused",
+			content: `
+- name: test
+  limit: 10
+  rules:
+  - record: foo
+    expr: sum(foo) by (job)
+`,
+			checker: newGroupLimitCheck,
+			prometheus: func(_ string) *promapi.FailoverGroup {
+				return simpleProm("prom", "http://127.0.0.1:1111", time.Second*5, false)
+			},
+			problems: true,
+		},
+		{
+			description: "no results",
+			content: `
+- name: test
+  limit: 10
+  rules:
+  - record: foo
+    expr: sum(foo) by (job)
+`,
+			checker:    newGroupLimitCheck,
+			prometheus: newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nsum(foo) by (job)\n)"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+			},
+		},
+		{
+			description: "results equal to limit",
+			content: `
+- name: test
+  limit: 10
+  rules:
+  - record: foo
+    expr: sum(foo) by (job)
+`,
+			checker:    newGroupLimitCheck,
+			prometheus: newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nsum(foo) by (job)\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{}, 10),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "recording rule results above limit",
+			content: `
+- name: test
+  limit: 10
+  rules:
+  - record: foo
+    expr: sum(foo) by (job)
+`,
+			checker:    newGroupLimitCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nsum(foo) by (job)\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{}, 25),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "alerting rule results above limit",
+			content: `
+- name: test
+  limit: 5
+  rules:
+  - alert: foo
+    expr: up == 0
+`,
+			checker:    newGroupLimitCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nup == 0\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{}, 7),
+						},
+					},
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
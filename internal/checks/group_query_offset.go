@@ -0,0 +1,201 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	GroupQueryOffsetCheckName = "group/query_offset"
+
+	queryOffsetFlag = "rules.query-offset"
+)
+
+// NewGroupQueryOffsetCheck creates a new group/query_offset check.
+// If prom is nil then query_offset is only compared with the for field of alerting rules,
+// otherwise it's compared with the global:rule_query_offset config option of the Prometheus server.
+func NewGroupQueryOffsetCheck(prom *promapi.FailoverGroup) GroupQueryOffsetCheck {
+	instance := GroupQueryOffsetCheckName
+	if prom != nil {
+		instance = fmt.Sprintf("%s(%s)", GroupQueryOffsetCheckName, prom.Name())
+	}
+	return GroupQueryOffsetCheck{
+		prom:     prom,
+		instance: instance,
+	}
+}
+
+type GroupQueryOffsetCheck struct {
+	prom     *promapi.FailoverGroup
+	instance string
+}
+
+func (c GroupQueryOffsetCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        c.prom != nil,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c GroupQueryOffsetCheck) String() string {
+	return c.instance
+}
+
+func (c GroupQueryOffsetCheck) Reporter() string {
+	return GroupQueryOffsetCheckName
+}
+
+func (c GroupQueryOffsetCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	if entry.Group == nil || entry.Group.QueryOffset == nil || entry.Group.QueryOffset.ParseError != nil {
+		return problems
+	}
+
+	if c.prom != nil {
+		return c.checkServerOffset(ctx, entry)
+	}
+
+	if entry.Rule.AlertingRule == nil {
+		return problems
+	}
+
+	// Alerts without for will fire as soon as the query returns results,
+	// so it's the same as using for: 0s.
+	offset := entry.Group.QueryOffset.Value
+	pos := entry.Rule.AlertingRule.Alert.Pos
+	msg := fmt.Sprintf("This group is using `query_offset: %s`, but this alert doesn't have `for` set. The alert is meant to fire as soon as the query returns results, but it will be delayed by the query offset.",
+		output.HumanizeDuration(offset))
+	if entry.Rule.AlertingRule.For != nil {
+		if entry.Rule.AlertingRule.For.ParseError != nil {
+			return problems
+		}
+		forVal := entry.Rule.AlertingRule.For.Value
+		if offset <= forVal {
+			return problems
+		}
+		pos = entry.Rule.AlertingRule.For.Pos
+		msg = fmt.Sprintf("This group is using `query_offset: %s`, which is longer than `for: %s` of this alert. The alert will be delayed more by the query offset than by waiting for `for` to pass.",
+			output.HumanizeDuration(offset), output.HumanizeDuration(forVal))
+	}
+
+	problems = append(problems, Problem{
+		Anchor:   AnchorAfter,
+		Lines:    pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  "query offset longer than for",
+		Details:  "",
+		Severity: Warning,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message:     msg,
+				Pos:         pos,
+				Expr:        nil,
+				FirstColumn: 1,
+				LastColumn:  pos.Len(),
+				Kind:        diags.Issue,
+			},
+		},
+	})
+
+	return problems
+}
+
+func (c GroupQueryOffsetCheck) checkServerOffset(ctx context.Context, entry *discovery.Entry) (problems []Problem) {
+	serverOffset, source, uri, problems, ok := c.serverOffset(ctx, entry)
+	if !ok {
+		return problems
+	}
+
+	pos := entry.Group.QueryOffset.Pos
+	offset := entry.Group.QueryOffset.Value
+	switch {
+	case offset == serverOffset:
+		problems = append(problems, c.newServerProblem(pos, "redundant query offset", Information,
+			fmt.Sprintf("This group is using `query_offset: %s`, which is already the default on %s configured via %s. You can remove it from this group.",
+				output.HumanizeDuration(offset), promText(c.prom.Name(), uri), source)))
+	case offset < serverOffset:
+		problems = append(problems, c.newServerProblem(pos, "query offset lower than server default", Warning,
+			fmt.Sprintf("This group is using `query_offset: %s`, but %s is configured with %s set to `%s`. Rules in this group will be evaluated using more recent data than the server default, which might not be fully ingested yet.",
+				output.HumanizeDuration(offset), promText(c.prom.Name(), uri), source, output.HumanizeDuration(serverOffset))))
+	}
+
+	return problems
+}
+
+// serverOffset returns the default query offset of the Prometheus server.
+// It's read from global:rule_query_offset in the server config, or from
+// the --rules.query-offset flag if the config doesn't set it.
+func (c GroupQueryOffsetCheck) serverOffset(ctx context.Context, entry *discovery.Entry) (offset time.Duration, source, uri string, problems []Problem, ok bool) {
+	cfg, err := c.prom.Config(ctx, 0).Wait()
+	switch {
+	case err == nil:
+		if cfg.Config.Global.RuleQueryOffset > 0 {
+			return cfg.Config.Global.RuleQueryOffset, "`global:rule_query_offset`", cfg.URI, problems, true
+		}
+	case errors.Is(err, promapi.ErrUnsupported):
+		// Fallback to flags.
+	default:
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Warning))
+		return offset, source, uri, problems, false
+	}
+
+	flags, err := c.prom.Flags(ctx).Wait()
+	if err != nil {
+		if errors.Is(err, promapi.ErrUnsupported) {
+			c.prom.DisableCheck(promapi.APIPathFlags, c.Reporter())
+			return offset, source, uri, problems, false
+		}
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Warning))
+		return offset, source, uri, problems, false
+	}
+
+	v, found := flags.Flags[queryOffsetFlag]
+	if !found {
+		return offset, source, uri, problems, false
+	}
+
+	flagOffset, err := model.ParseDuration(v)
+	if err != nil {
+		pos := entry.Group.QueryOffset.Pos
+		problems = append(problems, c.newServerProblem(pos, "unable to run checks", Warning,
+			fmt.Sprintf("Cannot parse --%s=%q flag value: %s", queryOffsetFlag, v, err)))
+		return offset, source, uri, problems, false
+	}
+
+	return time.Duration(flagOffset), fmt.Sprintf("`--%s` flag", queryOffsetFlag), flags.URI, problems, true
+}
+
+func (c GroupQueryOffsetCheck) newServerProblem(pos diags.PositionRanges, summary string, severity Severity, msg string) Problem {
+	return Problem{
+		Anchor:   AnchorAfter,
+		Lines:    pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  summary,
+		Details:  "",
+		Severity: severity,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message:     msg,
+				Pos:         pos,
+				Expr:        nil,
+				FirstColumn: 1,
+				LastColumn:  pos.Len(),
+				Kind:        diags.Issue,
+			},
+		},
+	}
+}
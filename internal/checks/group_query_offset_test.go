@@ -0,0 +1,366 @@
+package checks_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newGroupQueryOffsetCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewGroupQueryOffsetCheck(nil)
+}
+
+func newGroupQueryOffsetPromCheck(prom *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewGroupQueryOffsetCheck(prom)
+}
+
+func TestGroupQueryOffsetCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores groups without query_offset",
+			content:     "- alert: foo\n  expr: up == 0\n  for: 1m\n",
+			checker:     newGroupQueryOffsetCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "ignores recording rules",
+			content: `
+- name: test
+  query_offset: 5m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetCheck,
+			prometheus: noProm,
+		},
+		{
+			description: "alert without for",
+			content: `
+- name: test
+  query_offset: 5m
+  rules:
+  - alert: foo
+    expr: up == 0
+`,
+			checker:    newGroupQueryOffsetCheck,
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "ignores alerts with invalid for",
+			content: `
+- name: test
+  query_offset: 5m
+  rules:
+  - alert: foo
+    expr: up == 0
+    for: abc
+`,
+			checker:    newGroupQueryOffsetCheck,
+			prometheus: noProm,
+		},
+		{
+			description: "query_offset shorter than for",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - alert: foo
+    expr: up == 0
+    for: 5m
+`,
+			checker:    newGroupQueryOffsetCheck,
+			prometheus: noProm,
+		},
+		{
+			description: "query_offset longer than for",
+			content: `
+- name: test
+  query_offset: 10m
+  rules:
+  - alert: foo
+    expr: up == 0
+    for: 5m
+`,
+			checker:    newGroupQueryOffsetCheck,
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "ignores groups without query_offset / online",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newGroupQueryOffsetPromCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "flags error",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "flags unsupported",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  httpResponse{code: http.StatusNotFound, body: "Not Found"},
+				},
+			},
+		},
+		{
+			description: "flag not set",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+			},
+		},
+		{
+			description: "invalid flag value",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp: flagsResponse{flags: map[string]string{
+						"rules.query-offset": "abc",
+					}},
+				},
+			},
+		},
+		{
+			description: "query_offset equal to server default",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp: flagsResponse{flags: map[string]string{
+						"rules.query-offset": "1m",
+					}},
+				},
+			},
+		},
+		{
+			description: "query_offset lower than server default",
+			content: `
+- name: test
+  query_offset: 30s
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp: flagsResponse{flags: map[string]string{
+						"rules.query-offset": "2m",
+					}},
+				},
+			},
+		},
+		{
+			description: "query_offset higher than server default",
+			content: `
+- name: test
+  query_offset: 5m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp: flagsResponse{flags: map[string]string{
+						"rules.query-offset": "1m",
+					}},
+				},
+			},
+		},
+		{
+			description: "config error",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "config unsupported",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  httpResponse{code: http.StatusNotFound, body: "Not Found"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp: flagsResponse{flags: map[string]string{
+						"rules.query-offset": "1m",
+					}},
+				},
+			},
+		},
+		{
+			description: "query_offset equal to rule_query_offset",
+			content: `
+- name: test
+  query_offset: 1m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  rule_query_offset: 1m\n"},
+				},
+			},
+		},
+		{
+			description: "query_offset lower than rule_query_offset",
+			content: `
+- name: test
+  query_offset: 30s
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			problems:   true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  rule_query_offset: 2m\n"},
+				},
+			},
+		},
+		{
+			description: "query_offset higher than rule_query_offset",
+			content: `
+- name: test
+  query_offset: 5m
+  rules:
+  - record: foo
+    expr: sum(foo)
+`,
+			checker:    newGroupQueryOffsetPromCheck,
+			prometheus: newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  rule_query_offset: 1m\n"},
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
@@ -81,7 +81,7 @@ func (c LabelsConflictCheck) Check(ctx context.Context, entry *discovery.Entry,
 					},
 					Reporter: c.Reporter(),
 					Summary:  "conflicting labels",
-					Details:  fmt.Sprintf("[Click here](%s/config) to see `%s` Prometheus runtime configuration.", cfg.URI, c.prom.Name()),
+					Details:  configDetails(c.prom.Name(), cfg),
 					Severity: Warning,
 					Diagnostics: []diags.Diagnostic{
 						{
@@ -97,11 +97,57 @@ LOOP:
 		if len(metadata.Metadata) == 0 {
 			continue LOOP
 		}
+		var isCounter, isHistogram bool
 		for _, m := range metadata.Metadata {
-			if m.Type != v1.MetricTypeCounter {
+			// nolint:exhaustive
+			switch m.Type {
+			case v1.MetricTypeCounter:
+				isCounter = true
+			case v1.MetricTypeHistogram:
+				isHistogram = true
+			default:
 				continue LOOP
 			}
 		}
+		if isCounter && isHistogram {
+			continue LOOP
+		}
+		if isHistogram {
+			// Classic histograms don't have any series without a suffix, so if this selector
+			// returns histogram samples then we know it's a native histogram.
+			qr, err := c.prom.BatchQuery(ctx, wrapExpr(wrapExpr(selector.String(), "histogram_count"), "count")).Wait()
+			if err != nil {
+				problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Warning))
+				continue LOOP
+			}
+			if len(qr.Series) == 0 || qr.Series[0].Value == 0 {
+				continue LOOP
+			}
+			problems = append(problems, Problem{
+				Anchor:   AnchorAfter,
+				Lines:    expr.Value.Pos.Lines(),
+				Reporter: c.Reporter(),
+				Summary:  "direct native histogram read",
+				Details:  CounterCheckDetails,
+				Severity: Warning,
+				Diagnostics: []diags.Diagnostic{
+					{
+						Message: fmt.Sprintf(
+							"`%s` is a native histogram according to metrics metadata and query results from %s, it tracks observations since the application was started, you most likely want to use it with `rate()` or `increase()`.",
+							selector.Name,
+							promText(c.prom.Name(), qr.URI),
+						),
+						Pos:         expr.Value.Pos,
+						Expr:        expr.Query().Expr,
+						FirstColumn: int(selector.PosRange.Start) + 1,
+						LastColumn:  int(selector.PosRange.End),
+						Kind:        diags.Issue,
+					},
+				},
+			})
+			done[selector.Name] = struct{}{}
+			continue LOOP
+		}
 		problems = append(problems, Problem{
 			Anchor:   AnchorAfter,
 			Lines:    expr.Value.Pos.Lines(),
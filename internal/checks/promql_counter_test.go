@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/promapi"
@@ -282,6 +283,76 @@ func TestCounterCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "native histogram with metadata",
+			content:     "- alert: my alert\n  expr: histogram_quantile(0.9, http_request_duration_seconds) > 1\n",
+			checker:     newCounterCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"http_request_duration_seconds": {{Type: "histogram"}},
+					}},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nhistogram_count(\nhttp_request_duration_seconds\n)\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 5)},
+					},
+				},
+			},
+		},
+		{
+			description: "classic histogram with metadata",
+			content:     "- alert: my alert\n  expr: http_request_duration_seconds > 1\n",
+			checker:     newCounterCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"http_request_duration_seconds": {{Type: "histogram"}},
+					}},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nhistogram_count(\nhttp_request_duration_seconds\n)\n)"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+			},
+		},
+		{
+			description: "native histogram query error",
+			content:     "- alert: my alert\n  expr: http_request_duration_seconds > 1\n",
+			checker:     newCounterCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"http_request_duration_seconds": {{Type: "histogram"}},
+					}},
+				},
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithBadData(),
+				},
+			},
+		},
+		{
+			description: "histogram_quantile(rate(native histogram))",
+			content:     "- alert: my alert\n  expr: histogram_quantile(0.9, rate(http_request_duration_seconds[5m])) > 1\n",
+			checker:     newCounterCheck,
+			prometheus:  newSimpleProm,
+		},
 	}
 	runTests(t, testCases)
 }
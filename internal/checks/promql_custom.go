@@ -0,0 +1,239 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	CustomCheckName = "promql/custom"
+)
+
+// CustomPattern describes a PromQL query node.
+// All fields are optional, a node matches the pattern if it matches all set fields.
+type CustomPattern struct {
+	// Name of the function call.
+	Function *TemplatedRegexp
+	// Name of the aggregation operator.
+	Aggregation *TemplatedRegexp
+	// Name of the metric used in a vector selector.
+	Selector *TemplatedRegexp
+	// Name of any function call or aggregation that the node is nested in.
+	Inside *TemplatedRegexp
+	// Labels that must be preserved by aggregations.
+	By []string
+	// Labels that vector selectors must have matchers for.
+	Matchers []string
+}
+
+func (cp CustomPattern) expand(rule parser.Rule) customPattern {
+	expand := func(tr *TemplatedRegexp) *regexp.Regexp {
+		if tr == nil {
+			return nil
+		}
+		return tr.MustExpand(rule)
+	}
+	return customPattern{
+		function:    expand(cp.Function),
+		aggregation: expand(cp.Aggregation),
+		selector:    expand(cp.Selector),
+		inside:      expand(cp.Inside),
+		by:          cp.By,
+		matchers:    cp.Matchers,
+	}
+}
+
+type customPattern struct {
+	function    *regexp.Regexp
+	aggregation *regexp.Regexp
+	selector    *regexp.Regexp
+	inside      *regexp.Regexp
+	by          []string
+	matchers    []string
+}
+
+// isMatch returns true if node matches this pattern, path is the list of all parent nodes.
+func (cp customPattern) isMatch(node promParser.Node, path []promParser.Node) bool {
+	if cp.function != nil {
+		call, ok := node.(*promParser.Call)
+		if !ok || !cp.function.MatchString(call.Func.Name) {
+			return false
+		}
+	}
+	if cp.aggregation != nil {
+		aggr, ok := node.(*promParser.AggregateExpr)
+		if !ok || !cp.aggregation.MatchString(aggr.Op.String()) {
+			return false
+		}
+	}
+	if cp.selector != nil {
+		vs, ok := node.(*promParser.VectorSelector)
+		if !ok || !cp.selector.MatchString(metricName(vs)) {
+			return false
+		}
+	}
+	if cp.inside != nil && !slices.ContainsFunc(path, func(n promParser.Node) bool {
+		return cp.inside.MatchString(customNodeName(n))
+	}) {
+		return false
+	}
+	for _, name := range cp.by {
+		for _, aggr := range customAggregations(node) {
+			if !aggregationKeepsLabel(aggr, name) {
+				return false
+			}
+		}
+	}
+	for _, name := range cp.matchers {
+		for _, vs := range customSelectors(node) {
+			if !slices.ContainsFunc(vs.LabelMatchers, func(lm *labels.Matcher) bool { return lm.Name == name }) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func NewCustomCheck(name string, match CustomPattern, require *CustomPattern, message, comment string, severity Severity) CustomCheck {
+	return CustomCheck{
+		name:     name,
+		match:    match,
+		require:  require,
+		message:  message,
+		comment:  comment,
+		severity: severity,
+		instance: fmt.Sprintf("%s(%s)", CustomCheckName, name),
+	}
+}
+
+type CustomCheck struct {
+	require  *CustomPattern
+	name     string
+	message  string
+	comment  string
+	instance string
+	match    CustomPattern
+	severity Severity
+}
+
+func (c CustomCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        false,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c CustomCheck) String() string {
+	return c.instance
+}
+
+func (c CustomCheck) Reporter() string {
+	return CustomCheckName
+}
+
+func (c CustomCheck) Check(_ context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	expr := entry.Rule.Expr()
+	if expr.SyntaxError() != nil {
+		return problems
+	}
+
+	match := c.match.expand(entry.Rule)
+	var require *customPattern
+	if c.require != nil {
+		r := c.require.expand(entry.Rule)
+		require = &r
+	}
+
+	promParser.Inspect(expr.Query().Expr, func(node promParser.Node, path []promParser.Node) error {
+		if node == nil || !match.isMatch(node, path) {
+			return nil
+		}
+		if require != nil && require.isMatch(node, path) {
+			return nil
+		}
+		problems = append(problems, Problem{
+			Anchor:   AnchorAfter,
+			Lines:    expr.Value.Pos.Lines(),
+			Reporter: c.Reporter(),
+			Summary:  c.name,
+			Details:  maybeComment(c.comment),
+			Severity: c.severity,
+			Diagnostics: []diags.Diagnostic{
+				{
+					Message:     c.message,
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(node.PositionRange().Start) + 1,
+					LastColumn:  int(node.PositionRange().End),
+					Kind:        diags.Issue,
+				},
+			},
+		})
+		return nil
+	})
+
+	return problems
+}
+
+// customNodeName returns the name of a function call or aggregation.
+func customNodeName(node promParser.Node) string {
+	switch n := node.(type) {
+	case *promParser.Call:
+		return n.Func.Name
+	case *promParser.AggregateExpr:
+		return n.Op.String()
+	default:
+		return ""
+	}
+}
+
+// customAggregations returns node if it's an aggregation, or the most outer
+// aggregations nested in it otherwise.
+// Aggregations that only select some series, like topk(), are skipped since
+// they keep all labels.
+func customAggregations(node promParser.Node) (aggrs []*promParser.AggregateExpr) {
+	if aggr, ok := node.(*promParser.AggregateExpr); ok {
+		switch aggr.Op {
+		case promParser.TOPK, promParser.BOTTOMK, promParser.LIMITK, promParser.LIMIT_RATIO:
+		default:
+			return []*promParser.AggregateExpr{aggr}
+		}
+	}
+	for _, child := range promParser.Children(node) {
+		aggrs = append(aggrs, customAggregations(child)...)
+	}
+	return aggrs
+}
+
+// customSelectors returns all vector selectors used by node.
+func customSelectors(node promParser.Node) (selectors []*promParser.VectorSelector) {
+	promParser.Inspect(node, func(n promParser.Node, _ []promParser.Node) error {
+		if vs, ok := n.(*promParser.VectorSelector); ok {
+			selectors = append(selectors, vs)
+		}
+		return nil
+	})
+	return selectors
+}
+
+func aggregationKeepsLabel(aggr *promParser.AggregateExpr, name string) bool {
+	if aggr.Without {
+		return !slices.Contains(aggr.Grouping, name)
+	}
+	return slices.Contains(aggr.Grouping, name)
+}
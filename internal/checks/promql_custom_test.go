@@ -0,0 +1,183 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newCustomCheck(match checks.CustomPattern, require *checks.CustomPattern) func(*promapi.FailoverGroup) checks.RuleChecker {
+	return func(_ *promapi.FailoverGroup) checks.RuleChecker {
+		return checks.NewCustomCheck("test", match, require, "This query breaks the rules.", "", checks.Warning)
+	}
+}
+
+func TestCustomCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     newCustomCheck(checks.CustomPattern{Function: checks.MustTemplatedRegexp("irate")}, nil),
+			prometheus:  noProm,
+		},
+		{
+			description: "function not used",
+			content:     "- alert: foo\n  expr: rate(foo[5m]) > 0\n",
+			checker:     newCustomCheck(checks.CustomPattern{Function: checks.MustTemplatedRegexp("irate")}, nil),
+			prometheus:  noProm,
+		},
+		{
+			description: "function used",
+			content:     "- alert: foo\n  expr: sum(irate(foo[5m])) > 0 or irate(bar[5m]) > 0\n",
+			checker:     newCustomCheck(checks.CustomPattern{Function: checks.MustTemplatedRegexp("irate")}, nil),
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "function used with comment and severity",
+			content:     "- alert: foo\n  expr: irate(foo[5m]) > 0\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCustomCheck(
+					"no-irate",
+					checks.CustomPattern{Function: checks.MustTemplatedRegexp("irate")},
+					nil,
+					"Don't use irate() in alerts.",
+					"irate() only looks at the last two samples.",
+					checks.Bug,
+				)
+			},
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "aggregation with required by label",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by (le, cluster))\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Function: checks.MustTemplatedRegexp("histogram_quantile")},
+				&checks.CustomPattern{By: []string{"cluster"}},
+			),
+			prometheus: noProm,
+		},
+		{
+			description: "aggregation without required by label",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by (le))\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Function: checks.MustTemplatedRegexp("histogram_quantile")},
+				&checks.CustomPattern{By: []string{"cluster"}},
+			),
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "aggregation with without keeps label",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) without (instance))\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Function: checks.MustTemplatedRegexp("histogram_quantile")},
+				&checks.CustomPattern{By: []string{"cluster"}},
+			),
+			prometheus: noProm,
+		},
+		{
+			description: "aggregation with without removes label",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) without (cluster))\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Function: checks.MustTemplatedRegexp("histogram_quantile")},
+				&checks.CustomPattern{By: []string{"cluster"}},
+			),
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "topk is skipped when checking by labels",
+			content:     "- record: foo\n  expr: topk(5, sum(rate(foo[5m])) by (job))\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Function: nil, Aggregation: checks.MustTemplatedRegexp("topk")},
+				&checks.CustomPattern{By: []string{"cluster"}},
+			),
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "aggregation pattern",
+			content:     "- record: foo\n  expr: count(foo) by (job) / sum(foo) by (job, cluster)\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Aggregation: checks.MustTemplatedRegexp("sum|count")},
+				&checks.CustomPattern{By: []string{"cluster"}},
+			),
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "selector with required matcher",
+			content:     "- alert: foo\n  expr: up{job=\"foo\"} == 0\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Selector: checks.MustTemplatedRegexp("up")},
+				&checks.CustomPattern{Matchers: []string{"job"}},
+			),
+			prometheus: noProm,
+		},
+		{
+			description: "selector without required matcher",
+			content:     "- alert: foo\n  expr: up{instance=\"foo\"} == 0\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Selector: checks.MustTemplatedRegexp("up")},
+				&checks.CustomPattern{Matchers: []string{"job"}},
+			),
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "function with selectors missing matchers",
+			content:     "- record: foo\n  expr: absent(up) or absent(foo{job=\"bar\"})\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Function: checks.MustTemplatedRegexp("absent")},
+				&checks.CustomPattern{Matchers: []string{"job"}},
+			),
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "function inside aggregation",
+			content:     "- record: foo\n  expr: sum(rate(foo[5m])) + rate(bar[5m])\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Function: checks.MustTemplatedRegexp("rate")},
+				&checks.CustomPattern{Inside: checks.MustTemplatedRegexp("sum")},
+			),
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "function inside nested aggregation",
+			content:     "- record: foo\n  expr: max(sum(rate(foo[5m])) by (job))\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Function: checks.MustTemplatedRegexp("rate")},
+				&checks.CustomPattern{Inside: checks.MustTemplatedRegexp("sum")},
+			),
+			prometheus: noProm,
+		},
+		{
+			description: "function not allowed inside another function",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:5m])\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{
+					Function: checks.MustTemplatedRegexp("rate"),
+					Inside:   checks.MustTemplatedRegexp(".+_over_time"),
+				},
+				nil,
+			),
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "templated pattern",
+			content:     "- record: foo:rate5m\n  expr: rate(foo[5m])\n",
+			checker: newCustomCheck(
+				checks.CustomPattern{Selector: checks.MustTemplatedRegexp("{{ $record }}")},
+				nil,
+			),
+			prometheus: noProm,
+		},
+	}
+	runTests(t, testCases)
+}
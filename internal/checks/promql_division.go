@@ -0,0 +1,297 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	promParser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/promql/parser/posrange"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/source"
+)
+
+const (
+	DivisionCheckName = "promql/division"
+
+	DivisionCheckDetails = `Dividing by a value that can be zero will return ` + "`NaN`" + ` or ` + "`+Inf`" + `.
+Ratio alerts like ` + "`errors / requests > 0.05`" + ` might stop firing or fire when there's no traffic.
+To avoid this, filter out zero-valued divisor series, for example:
+
+- ` + "`errors / (requests > 0)`" + `
+- ` + "`errors / clamp_min(requests, 1)`" + `
+- ` + "`errors / requests > 0.05 and requests > 0`" + `
+
+See [promql/division](https://cloudflare.github.io/pint/checks/promql/division.html) for more details.`
+)
+
+// Functions that return zero when input series are not changing.
+var divisionZeroFuncs = map[string]string{
+	"rate":     "the counter doesn't increase",
+	"irate":    "the counter doesn't increase",
+	"increase": "the counter doesn't increase",
+	"delta":    "the value doesn't change",
+	"idelta":   "the value doesn't change",
+	"deriv":    "the value doesn't change",
+	"changes":  "the value doesn't change",
+	"resets":   "the counter doesn't reset",
+}
+
+// Functions that will return zero if the input is zero.
+var divisionPassthroughFuncs = []string{"abs", "clamp", "clamp_max", "clamp_min"}
+
+func NewDivisionCheck(comment string, severity Severity) DivisionCheck {
+	return DivisionCheck{
+		nan:      NewNaNCheck(),
+		comment:  comment,
+		severity: severity,
+	}
+}
+
+type DivisionCheck struct {
+	nan      NaNCheck
+	comment  string
+	severity Severity
+}
+
+func (c DivisionCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        false,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c DivisionCheck) String() string {
+	return DivisionCheckName
+}
+
+func (c DivisionCheck) Reporter() string {
+	return DivisionCheckName
+}
+
+func (c DivisionCheck) Check(_ context.Context, entry *discovery.Entry, entries []*discovery.Entry) (problems []Problem) {
+	expr := entry.Rule.Expr()
+	if expr.SyntaxError() != nil {
+		return problems
+	}
+	recordingRulesByName := c.nan.collectRecordingRulesByName(entries)
+
+	for _, src := range expr.Source() {
+		src.WalkSources(func(s *source.Source, _ *source.Join, _ *source.Unless) {
+			for _, j := range s.Joins {
+				if j.Op != promParser.DIV && j.Op != promParser.MOD {
+					continue
+				}
+				// sum(foo / bar) is already reported by promql/nan.
+				if j.Depth > 0 && c.isReportedByNaN(s) {
+					continue
+				}
+				if p, ok := c.checkDivisor(expr, s, j.Src, recordingRulesByName); ok {
+					problems = append(problems, p)
+				}
+			}
+			for _, ind := range s.Indirect {
+				if ind.Op != promParser.DIV && ind.Op != promParser.MOD {
+					continue
+				}
+				// 1 / foo - number on the left, foo is the divisor.
+				if ind.Side != source.LHS || c.isReportedByNaN(s) {
+					continue
+				}
+				if p, ok := c.checkDivisor(expr, s, s, recordingRulesByName); ok {
+					problems = append(problems, p)
+				}
+			}
+		})
+	}
+
+	return problems
+}
+
+func (c DivisionCheck) checkDivisor(expr *parser.PromQLExpr, root, divisor *source.Source, recordingRulesByName map[string][]*parser.PromQLExpr) (Problem, bool) {
+	if c.nan.safeDivisorSource(divisor) || c.isGuarded(root, divisor) {
+		return Problem{}, false
+	}
+	pos := c.divisorPosition(divisor)
+	fragment := source.GetQueryFragment(expr.Value.Value, pos)
+
+	var msg string
+	if divisor.ReturnInfo.KnownReturn {
+		if divisor.ReturnInfo.ReturnedNumber != 0 {
+			return Problem{}, false
+		}
+		msg = fmt.Sprintf("`%s` will always return zero, dividing by zero will return NaN or +Inf.", fragment)
+	} else {
+		reason, fromRule, ok := c.zeroReason(divisor, recordingRulesByName, map[string]bool{})
+		if !ok {
+			return Problem{}, false
+		}
+		if fromRule {
+			reason = "it's produced by a recording rule where " + reason
+		}
+		msg = fmt.Sprintf("`%s` can be zero because %s, dividing by zero will return NaN or +Inf.", fragment, reason)
+	}
+	return c.reportFor(expr, msg, pos), true
+}
+
+// zeroReason returns the reason why the divisor can evaluate to zero.
+// It only reports divisors that we know will be zero when there's no traffic,
+// any other divisors are assumed to be safe.
+// fromRule is true if the divisor is a recording rule that can be zero.
+func (c DivisionCheck) zeroReason(s *source.Source, recordingRulesByName map[string][]*parser.PromQLExpr, visited map[string]bool) (reason string, fromRule, ok bool) {
+	// Operations are ordered from the most inner one, walk them starting at the end.
+	for i := len(s.Operations) - 1; i >= 0; i-- {
+		switch n := s.Operations[i].Node.(type) {
+		case *promParser.AggregateExpr:
+			switch n.Op {
+			case promParser.SUM,
+				promParser.AVG,
+				promParser.MIN,
+				promParser.MAX,
+				promParser.QUANTILE,
+				promParser.TOPK,
+				promParser.BOTTOMK,
+				promParser.LIMITK,
+				promParser.LIMIT_RATIO:
+				// These aggregations will return zero if the input is zero.
+				continue
+			default:
+				// count() or group() will never return zero.
+				return "", false, false
+			}
+		case *promParser.Call:
+			if reason, ok := divisionZeroFuncs[n.Func.Name]; ok {
+				return fmt.Sprintf("`%s()` will return zero when %s", n.Func.Name, reason), false, true
+			}
+			if slices.Contains(divisionPassthroughFuncs, n.Func.Name) {
+				continue
+			}
+			return "", false, false
+		case *promParser.VectorSelector:
+			reason, ok = c.recordingRuleZeroReason(n.Name, recordingRulesByName, visited)
+			return reason, ok, ok
+		default:
+			return "", false, false
+		}
+	}
+	return "", false, false
+}
+
+func (c DivisionCheck) recordingRuleZeroReason(name string, recordingRulesByName map[string][]*parser.PromQLExpr, visited map[string]bool) (string, bool) {
+	if visited[name] {
+		return "", false
+	}
+	visited[name] = true
+
+	for _, expr := range recordingRulesByName[name] {
+		for _, src := range expr.Source() {
+			if c.nan.safeDivisorSource(src) {
+				continue
+			}
+			if reason, _, ok := c.zeroReason(src, recordingRulesByName, visited); ok {
+				return reason, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isGuarded returns true if the query removes all series where the divisor
+// is zero, for example `foo / bar > 0.05 and bar > 0`.
+func (c DivisionCheck) isGuarded(root, divisor *source.Source) bool {
+	vs, ok := source.MostOuterOperation[*promParser.VectorSelector](divisor)
+	if !ok {
+		return false
+	}
+	sameSelector := func(s *source.Source) bool {
+		other, ok := source.MostOuterOperation[*promParser.VectorSelector](s)
+		return ok && other.Name == vs.Name
+	}
+	for _, j := range root.Joins {
+		if j.Op == promParser.LAND && sameSelector(j.Src) && c.nan.excludesZero(j.Src.Condition) && !j.Src.ReturnInfo.IsReturnBool {
+			return true
+		}
+	}
+	for _, u := range root.Unless {
+		if sameSelector(u.Src) && c.matchesZero(u.Src.Condition) && !u.Src.ReturnInfo.IsReturnBool {
+			return true
+		}
+	}
+	return false
+}
+
+func (c DivisionCheck) matchesZero(cond source.Condition) bool {
+	if !cond.Present || !cond.KnownValue {
+		return false
+	}
+	switch cond.Op {
+	case promParser.EQLC:
+		return cond.Value == 0
+	case promParser.LTE:
+		return cond.Value >= 0
+	case promParser.LSS:
+		return cond.Value > 0
+	default:
+		return false
+	}
+}
+
+func (c DivisionCheck) isReportedByNaN(s *source.Source) bool {
+	if s.Type != source.AggregateSource {
+		return false
+	}
+	aggr, ok := source.MostOuterOperation[*promParser.AggregateExpr](s)
+	if !ok {
+		return true
+	}
+	switch aggr.Op {
+	case promParser.SUM,
+		promParser.AVG,
+		promParser.STDDEV,
+		promParser.STDVAR:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c DivisionCheck) divisorPosition(s *source.Source) posrange.PositionRange {
+	if len(s.Operations) > 0 {
+		return s.Operations[len(s.Operations)-1].Node.PositionRange()
+	}
+	return s.Position
+}
+
+func (c DivisionCheck) reportFor(expr *parser.PromQLExpr, message string, highlight posrange.PositionRange) Problem {
+	details := DivisionCheckDetails
+	if c.comment != "" {
+		details = fmt.Sprintf("%s\n%s", details, maybeComment(c.comment))
+	}
+	return Problem{
+		Anchor:   AnchorAfter,
+		Lines:    expr.Value.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  "division by zero",
+		Details:  details,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message:     message,
+				Pos:         expr.Value.Pos,
+				Expr:        expr.Query().Expr,
+				FirstColumn: int(highlight.Start) + 1,
+				LastColumn:  int(highlight.End),
+				Kind:        diags.Issue,
+			},
+		},
+		Severity: c.severity,
+	}
+}
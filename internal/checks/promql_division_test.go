@@ -0,0 +1,192 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newDivisionCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewDivisionCheck("", checks.Warning)
+}
+
+func TestDivisionCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "syntax error is ignored",
+			content:     "- record: foo\n  expr: up ==\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division by selector is allowed",
+			content:     "- record: foo\n  expr: foo / bar\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "multiplication of rates is allowed",
+			content:     "- record: foo\n  expr: rate(foo[5m]) * rate(bar[5m])\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division by rate is reported",
+			content:     "- alert: foo\n  expr: rate(errors_total[5m]) / rate(requests_total[5m]) > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "division by sum of increase is reported",
+			content:     "- alert: foo\n  expr: sum(increase(errors_total[5m])) / sum(increase(requests_total[5m])) > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "modulo by irate is reported",
+			content:     "- record: foo\n  expr: foo % irate(bar_total[5m])\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "number divided by deriv is reported",
+			content:     "- record: foo\n  expr: 1 / deriv(foo[5m])\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "division by count is allowed",
+			content:     "- record: foo\n  expr: count(up == 0) / count(up)\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division by count of rate is allowed",
+			content:     "- record: foo\n  expr: sum(rate(foo[5m])) / count(rate(foo[5m]))\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division by vector(0) fallback is reported",
+			content:     "- record: foo\n  expr: sum(foo) / (count(bar) or vector(0))\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "division by filtered rate is allowed",
+			content:     "- alert: foo\n  expr: rate(errors_total[5m]) / (rate(requests_total[5m]) > 0) > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division by rate filtered with bool is reported",
+			content:     "- record: foo\n  expr: rate(errors_total[5m]) / (rate(requests_total[5m]) > bool 0)\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "division by clamp_min of rate is allowed",
+			content:     "- alert: foo\n  expr: rate(errors_total[5m]) / clamp_min(rate(requests_total[5m]), 1) > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division by clamp_min(0) of rate is reported",
+			content:     "- alert: foo\n  expr: rate(errors_total[5m]) / clamp_min(rate(requests_total[5m]), 0) > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "division guarded with and is allowed",
+			content:     "- alert: foo\n  expr: rate(errors_total[5m]) / rate(requests_total[5m]) > 0.05 and rate(requests_total[5m]) > 0\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division guarded with and on a different metric is reported",
+			content:     "- alert: foo\n  expr: rate(errors_total[5m]) / rate(requests_total[5m]) > 0.05 and rate(errors_total[5m]) > 0\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "division guarded with unless is allowed",
+			content:     "- alert: foo\n  expr: rate(errors_total[5m]) / rate(requests_total[5m]) > 0.05 unless rate(requests_total[5m]) == 0\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division inside sum is left to promql/nan",
+			content:     "- record: foo\n  expr: sum(foo / rate(bar[5m]))\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "division inside max is reported",
+			content:     "- record: foo\n  expr: max(foo / rate(bar[5m]))\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "division by recording rule using rate is reported",
+			content:     "- alert: foo\n  expr: job:errors:rate5m / job:requests:rate5m > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+			entries: mustParseContent(
+				"- record: job:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by (job)\n",
+			),
+		},
+		{
+			description: "division by guarded recording rule is allowed",
+			content:     "- alert: foo\n  expr: job:errors:rate5m / job:requests:rate5m > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			entries: mustParseContent(
+				"- record: job:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by (job) > 0\n",
+			),
+		},
+		{
+			description: "removed recording rule is ignored",
+			content:     "- alert: foo\n  expr: job:errors:rate5m / job:requests:rate5m > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			entries: parseWithState(
+				"- record: job:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by (job)\n",
+				discovery.Removed,
+			),
+		},
+		{
+			description: "transitive recording rule using rate is reported",
+			content:     "- alert: foo\n  expr: errors / job:requests:rate5m:sum > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			problems:    true,
+			entries: mustParseContent(
+				"- record: job:requests:rate5m\n  expr: rate(requests_total[5m])\n" +
+					"- record: job:requests:rate5m:sum\n  expr: sum(job:requests:rate5m) by (job)\n",
+			),
+		},
+		{
+			description: "recursive recording rules are ignored",
+			content:     "- alert: foo\n  expr: errors / rec_a > 0.05\n",
+			checker:     newDivisionCheck,
+			prometheus:  noProm,
+			entries: mustParseContent(
+				"- record: rec_a\n  expr: rec_b\n" +
+					"- record: rec_b\n  expr: rec_a\n",
+			),
+		},
+	}
+	runTests(t, testCases)
+}
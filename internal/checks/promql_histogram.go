@@ -0,0 +1,286 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/source"
+)
+
+const (
+	HistogramCheckName    = "promql/histogram"
+	HistogramCheckDetails = `Classic histograms are exported as a set of time series, one for each bucket, with the upper bound of each bucket stored in the ` + "`le`" + ` label.
+[Click here](https://prometheus.io/docs/practices/histograms/) to read more about histograms in Prometheus documentation.`
+)
+
+func NewHistogramCheck() HistogramCheck {
+	return HistogramCheck{}
+}
+
+type HistogramCheck struct{}
+
+func (c HistogramCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        false,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c HistogramCheck) String() string {
+	return HistogramCheckName
+}
+
+func (c HistogramCheck) Reporter() string {
+	return HistogramCheckName
+}
+
+func (c HistogramCheck) Check(_ context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	expr := entry.Rule.Expr()
+	if expr.SyntaxError() != nil {
+		return problems
+	}
+
+	for _, node := range parser.WalkDownExpr[*promParser.Call](expr.Query()) {
+		call := node.Expr.(*promParser.Call)
+		switch call.Func.Name {
+		case "histogram_quantile":
+			problems = append(problems, c.checkQuantile(expr, call)...)
+			problems = append(problems, c.checkBuckets(expr, call)...)
+		case "histogram_fraction":
+			problems = append(problems, c.checkBuckets(expr, call)...)
+		}
+	}
+
+	for _, src := range expr.Source() {
+		src.WalkSources(func(s *source.Source, _ *source.Join, _ *source.Unless) {
+			problems = append(problems, c.checkAggregation(expr, s)...)
+		})
+	}
+
+	for _, node := range parser.WalkDownExpr[*promParser.VectorSelector](expr.Query()) {
+		problems = append(problems, c.checkMatchers(expr, node.Expr.(*promParser.VectorSelector))...)
+	}
+
+	return problems
+}
+
+// checkQuantile reports histogram_quantile() calls with a quantile value outside of [0, 1] range.
+func (c HistogramCheck) checkQuantile(expr *parser.PromQLExpr, call *promParser.Call) (problems []Problem) {
+	for _, s := range source.LabelsSource(expr.Value.Value, call.Args[0]) {
+		if !s.ReturnInfo.KnownReturn {
+			continue
+		}
+		q := s.ReturnInfo.ReturnedNumber
+		if q >= 0 && q <= 1 {
+			continue
+		}
+
+		ret := "-Inf"
+		if q > 1 {
+			ret = "+Inf"
+		}
+		msg := fmt.Sprintf("Quantile passed to `%s()` must be between 0 and 1, using `%s` will always return `%s`.",
+			call.Func.Name, strconv.FormatFloat(q, 'f', -1, 64), ret)
+		if q > 1 && q <= 100 {
+			msg += fmt.Sprintf(" If you want to calculate the %sth percentile use `%s` instead.",
+				strconv.FormatFloat(q, 'f', -1, 64), strconv.FormatFloat(q/100, 'f', -1, 64))
+		}
+
+		pos := call.Args[0].PositionRange()
+		problems = append(problems, Problem{
+			Anchor:   AnchorAfter,
+			Lines:    expr.Value.Pos.Lines(),
+			Reporter: c.Reporter(),
+			Summary:  "invalid quantile",
+			Details:  "",
+			Severity: Bug,
+			Diagnostics: []diags.Diagnostic{
+				{
+					Message:     msg,
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(pos.Start) + 1,
+					LastColumn:  int(pos.End),
+					Kind:        diags.Issue,
+				},
+			},
+		})
+	}
+	return problems
+}
+
+// checkBuckets reports histogram_quantile() and histogram_fraction() calls
+// on classic histogram buckets with the le label already removed.
+func (c HistogramCheck) checkBuckets(expr *parser.PromQLExpr, call *promParser.Call) (problems []Problem) {
+	for _, s := range source.LabelsSource(expr.Value.Value, call.Args[len(call.Args)-1]) {
+		vs, ok := isClassicBucketsSource(s)
+		if !ok || s.CanHaveLabel(model.BucketLabel) {
+			continue
+		}
+
+		reason, fragment := s.LabelExcludeReason(model.BucketLabel)
+		namePos := source.FindFuncNamePosition(expr.Value.Value, call.PosRange, call.Func.Name)
+		problems = append(problems, Problem{
+			Anchor:   AnchorAfter,
+			Lines:    expr.Value.Pos.Lines(),
+			Reporter: c.Reporter(),
+			Summary:  "missing le label",
+			Details:  HistogramCheckDetails,
+			Severity: Bug,
+			Diagnostics: []diags.Diagnostic{
+				{
+					Message: fmt.Sprintf("`%s()` needs the `%s` label to calculate results from classic histogram buckets of `%s` but that label is removed before this call, this query will never return anything.",
+						call.Func.Name, model.BucketLabel, vs.Name),
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(namePos.Start) + 1,
+					LastColumn:  int(namePos.End),
+					Kind:        diags.Issue,
+				},
+				{
+					Message:     reason,
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(fragment.Start) + 1,
+					LastColumn:  int(fragment.End),
+					Kind:        diags.Context,
+				},
+			},
+		})
+	}
+	return problems
+}
+
+// checkAggregation reports aggregations that merge all classic histogram
+// buckets together by removing the le label.
+func (c HistogramCheck) checkAggregation(expr *parser.PromQLExpr, s *source.Source) (problems []Problem) {
+	vs, ok := isClassicBucketsSource(s)
+	if !ok || s.CanHaveLabel(model.BucketLabel) {
+		return problems
+	}
+
+	for _, op := range s.Operations {
+		aggr, ok := op.Node.(*promParser.AggregateExpr)
+		if !ok {
+			continue
+		}
+		if aggr.Without != slices.Contains(aggr.Grouping, model.BucketLabel) {
+			// This aggregation keeps the le label.
+			continue
+		}
+		// nolint: exhaustive
+		switch aggr.Op {
+		case promParser.SUM, promParser.AVG, promParser.MIN, promParser.MAX,
+			promParser.QUANTILE, promParser.STDDEV, promParser.STDVAR:
+		default:
+			// Other aggregations, like count(), are safe to use on buckets.
+			return problems
+		}
+
+		reason, fragment := s.LabelExcludeReason(model.BucketLabel)
+		problems = append(problems, Problem{
+			Anchor:   AnchorAfter,
+			Lines:    expr.Value.Pos.Lines(),
+			Reporter: c.Reporter(),
+			Summary:  "aggregation removes le label",
+			Details:  HistogramCheckDetails,
+			Severity: Warning,
+			Diagnostics: []diags.Diagnostic{
+				{
+					Message: fmt.Sprintf("`%s()` is used here on classic histogram buckets of `%s` without preserving the `%s` label, which will merge all buckets together. %s",
+						promParser.ItemTypeStr[aggr.Op], vs.Name, model.BucketLabel, reason),
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(fragment.Start) + 1,
+					LastColumn:  int(fragment.End),
+					Kind:        diags.Issue,
+				},
+			},
+		})
+		break
+	}
+	return problems
+}
+
+// checkMatchers reports le matchers using values that won't match normalised
+// le labels, like le="1" instead of le="1.0".
+func (c HistogramCheck) checkMatchers(expr *parser.PromQLExpr, vs *promParser.VectorSelector) (problems []Problem) {
+	for _, m := range vs.LabelMatchers {
+		if m.Name != model.BucketLabel {
+			continue
+		}
+		if m.Type != labels.MatchEqual && m.Type != labels.MatchNotEqual {
+			continue
+		}
+		f, err := strconv.ParseFloat(m.Value, 64)
+		if err != nil || math.IsNaN(f) {
+			continue
+		}
+		canonical := labels.FormatOpenMetricsFloat(f)
+		if canonical == m.Value {
+			continue
+		}
+
+		effect := "won't match any series"
+		if m.Type == labels.MatchNotEqual {
+			effect = "will match all series"
+		}
+
+		pos := source.FindMatcherPos(expr.Value.Value, vs.PosRange, m)
+		problems = append(problems, Problem{
+			Anchor:   AnchorAfter,
+			Lines:    expr.Value.Pos.Lines(),
+			Reporter: c.Reporter(),
+			Summary:  "non-canonical le value",
+			Details:  HistogramCheckDetails,
+			Severity: Warning,
+			Diagnostics: []diags.Diagnostic{
+				{
+					Message: fmt.Sprintf("Since Prometheus 3.0 values of the `%s` label on classic histogram buckets are normalised, `%s%s%q` %s, use `%s%s%q` instead.",
+						model.BucketLabel, m.Name, m.Type, m.Value, effect, m.Name, m.Type, canonical),
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(pos.Start) + 1,
+					LastColumn:  int(pos.End),
+					Kind:        diags.Issue,
+				},
+			},
+		})
+	}
+	return problems
+}
+
+// isClassicBucketsSource returns the selector of a source that is reading
+// all classic histogram buckets, not just some of them.
+func isClassicBucketsSource(s *source.Source) (*promParser.VectorSelector, bool) {
+	if s.Histogram != source.ClassicHistogram {
+		return nil, false
+	}
+	vs, ok := source.MostOuterOperation[*promParser.VectorSelector](s)
+	if !ok || !strings.HasSuffix(vs.Name, "_bucket") {
+		return nil, false
+	}
+	for _, m := range vs.LabelMatchers {
+		if m.Name == model.BucketLabel && m.Type == labels.MatchEqual {
+			return nil, false
+		}
+	}
+	return vs, true
+}
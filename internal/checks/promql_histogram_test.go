@@ -0,0 +1,162 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newHistogramCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewHistogramCheck()
+}
+
+func TestHistogramCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores queries without histograms",
+			content:     "- record: foo\n  expr: sum(rate(foo[5m])) by(job)\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "histogram_quantile with le",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "histogram_quantile with le and other labels",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) without(instance))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "histogram_quantile without le",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(job))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "histogram_quantile with le removed by without()",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) without(le))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "histogram_fraction without le",
+			content:     "- record: foo\n  expr: histogram_fraction(0, 0.2, sum(rate(foo_bucket[5m])))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "histogram_quantile on native histogram",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo[5m])))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "sum without le",
+			content:     "- record: foo\n  expr: sum(rate(foo_bucket[5m]))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "sum by job",
+			content:     "- record: foo\n  expr: sum(rate(foo_bucket[5m])) by(job)\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "sum on a single bucket",
+			content:     "- record: foo\n  expr: sum(rate(foo_bucket{le=\"+Inf\"}[5m]))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "count without le",
+			content:     "- record: foo\n  expr: count(foo_bucket)\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "sum by le then sum",
+			content:     "- record: foo\n  expr: sum(sum(rate(foo_bucket[5m])) by(le, job))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "quantile above 1",
+			content:     "- record: foo\n  expr: histogram_quantile(99, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "quantile below 0",
+			content:     "- record: foo\n  expr: histogram_quantile(-0.5, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "quantile way above 1",
+			content:     "- record: foo\n  expr: histogram_quantile(1000, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "quantile 1",
+			content:     "- record: foo\n  expr: histogram_quantile(1, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "quantile from a query",
+			content:     "- record: foo\n  expr: histogram_quantile(scalar(bar), sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "non-canonical le value",
+			content:     "- record: foo\n  expr: rate(foo_bucket{le=\"1\"}[5m])\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "non-canonical le value with !=",
+			content:     "- record: foo\n  expr: rate(foo_bucket{le!=\"0.50\"}[5m])\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+		},
+		{
+			description: "canonical le values",
+			content:     "- record: foo\n  expr: rate(foo_bucket{le=\"1.0\"}[5m]) or rate(foo_bucket{le=\"+Inf\"}[5m]) or rate(foo_bucket{le=\"0.25\"}[5m])\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "le regexp",
+			content:     "- record: foo\n  expr: rate(foo_bucket{le=~\"1|2\"}[5m])\n",
+			checker:     newHistogramCheck,
+			prometheus:  newSimpleProm,
+		},
+	}
+
+	runTests(t, testCases)
+}
@@ -113,6 +113,21 @@ func (c RangeQueryCheck) Check(ctx context.Context, entry *discovery.Entry, _ []
 			Warning,
 		)...,
 	)
+	if len(problems) > 0 {
+		return problems
+	}
+
+	cfg, err := c.prom.Config(ctx, 0).Wait()
+	if err != nil {
+		if errors.Is(err, promapi.ErrUnsupported) {
+			c.prom.DisableCheck(promapi.APIPathConfig, c.Reporter())
+			return problems
+		}
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Warning))
+		return problems
+	}
+
+	problems = append(problems, c.checkScrapeInterval(ctx, expr, cfg)...)
 
 	return problems
 }
@@ -152,3 +167,55 @@ func (c RangeQueryCheck) checkSources(expr *parser.PromQLExpr, retention time.Du
 	}
 	return problems
 }
+
+// checkScrapeInterval reports range selectors with a time window shorter than the scrape
+// interval of the queried job, these will only find any samples on some evaluations.
+// Rate functions and absent_over_time() are skipped since they have their own checks.
+func (c RangeQueryCheck) checkScrapeInterval(ctx context.Context, expr *parser.PromQLExpr, cfg *promapi.ConfigResult) (problems []Problem) {
+	for _, src := range expr.Source() {
+		src.WalkSources(func(src *source.Source, _ *source.Join, _ *source.Unless) {
+			if _, ok := findRateCall(src); ok {
+				return
+			}
+			if src.Operation() == "absent_over_time" {
+				return
+			}
+			n, ok := source.MostOuterOperation[*promParser.MatrixSelector](src)
+			if !ok {
+				return
+			}
+			vs, ok := n.VectorSelector.(*promParser.VectorSelector)
+			if !ok {
+				return
+			}
+			si := resolveScrapeInterval(ctx, c.prom, cfg, vs)
+			if n.Range >= si.interval {
+				return
+			}
+			problems = append(problems, Problem{
+				Anchor:   AnchorAfter,
+				Lines:    expr.Value.Pos.Lines(),
+				Reporter: c.Reporter(),
+				Summary:  "range query shorter than scrape interval",
+				Details: "Range selectors will only return samples scraped within the selected time window.\n" +
+					"If that window is shorter than the scrape interval then some evaluations won't find any samples and your query will return no results.",
+				Severity: Warning,
+				Diagnostics: []diags.Diagnostic{
+					{
+						Message: fmt.Sprintf(
+							"`%s` selector is trying to query Prometheus for %s worth of metrics, but %s is using `%s` %s.",
+							n, model.Duration(n.Range), promText(c.prom.Name(), cfg.URI),
+							output.HumanizeDuration(si.interval), si,
+						),
+						Pos:         expr.Value.Pos,
+						Expr:        expr.Query().Expr,
+						FirstColumn: int(n.PositionRange().Start) + 1,
+						LastColumn:  int(n.PositionRange().End),
+						Kind:        diags.Issue,
+					},
+				},
+			})
+		})
+	}
+	return problems
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
+
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/promapi"
 )
@@ -75,6 +77,10 @@ func TestRangeQueryCheck(t *testing.T) {
 					conds: []requestCondition{requireFlagsPath},
 					resp:  flagsResponse{flags: map[string]string{}},
 				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
 			},
 		},
 		{
@@ -102,6 +108,10 @@ func TestRangeQueryCheck(t *testing.T) {
 						"storage.tsdb.retention.time": "11d",
 					}},
 				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
 			},
 		},
 		{
@@ -150,6 +160,137 @@ func TestRangeQueryCheck(t *testing.T) {
 						"storage.tsdb.retention.time": "0s",
 					}},
 				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+			},
+		},
+		{
+			description: "max_over_time < scrape_interval",
+			content:     "- record: foo\n  expr: max_over_time(foo[30s])\n",
+			checker:     newRangeQueryCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+			},
+		},
+		{
+			description: "max_over_time == scrape_interval",
+			content:     "- record: foo\n  expr: max_over_time(foo[1m])\n",
+			checker:     newRangeQueryCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+			},
+		},
+		{
+			description: "rate < scrape_interval",
+			content:     "- record: foo\n  expr: rate(foo[30s])\n",
+			checker:     newRangeQueryCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+			},
+		},
+		{
+			description: "max_over_time < job scrape_interval",
+			content:     "- record: foo\n  expr: max_over_time(foo{job=\"slow\"}[1m])\n",
+			checker:     newRangeQueryCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: fast\n- job_name: slow\n  scrape_interval: 2m\n"},
+				},
+			},
+		},
+		{
+			description: "max_over_time > job scrape_interval",
+			content:     "- record: foo\n  expr: max_over_time(foo{job=\"fast\"}[1m])\n",
+			checker:     newRangeQueryCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: fast\n- job_name: slow\n  scrape_interval: 2m\n"},
+				},
+			},
+		},
+		{
+			description: "max_over_time < scrape_interval of jobs from series",
+			content:     "- record: foo\n  expr: max_over_time(foo[1m])\n",
+			checker:     newRangeQueryCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: fast\n- job_name: slow\n  scrape_interval: 2m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(foo) by (job)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSample(map[string]string{"job": "slow"}),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "config error",
+			content:     "- record: foo\n  expr: max_over_time(foo[1m])\n",
+			checker:     newRangeQueryCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  respondWithInternalError(),
+				},
 			},
 		},
 		{
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/cloudflare/pint/internal/diags"
@@ -15,11 +16,10 @@ import (
 	"github.com/cloudflare/pint/internal/promapi"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 	promParser "github.com/prometheus/prometheus/promql/parser"
 )
 
-// FIXME: add native histograms
-
 const (
 	RateCheckName    = "promql/rate"
 	RateCheckDetails = `Using [rate](https://prometheus.io/docs/prometheus/latest/querying/functions/#rate) and [irate](https://prometheus.io/docs/prometheus/latest/querying/functions/#irate) function comes with a few requirements:
@@ -30,6 +30,8 @@ const (
 The type of your metric is defined by the application that exports that metric.
 The number of samples depends on how often your application is being scraped by Prometheus.
 Each scrape produces a sample, so if your application is scraped every minute then the minimal time window you can use is two minutes.`
+	RateCheckHistogramDetails = `Classic histograms are exported as a set of time series, one for each bucket, with the upper bound of each bucket stored in the ` + "`le`" + ` label.
+Functions like [histogram_quantile](https://prometheus.io/docs/prometheus/latest/querying/functions/#histogram_quantile) need the ` + "`le`" + ` label to calculate the result, so any aggregation done before calling them must preserve it, for example ` + "`histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le))`" + `.`
 )
 
 var allowedRateTypes = []v1.MetricType{
@@ -97,7 +99,8 @@ func (c RateCheck) Check(ctx context.Context, entry *discovery.Entry, entries []
 		pending[name] = c.prom.Metadata(ctx, name)
 	}
 
-	problems = append(problems, c.checkSources(entry.Rule, expr, entries, cfg, pending)...)
+	problems = append(problems, c.checkSources(ctx, entry.Rule, expr, entries, cfg, pending)...)
+	problems = append(problems, c.checkHistograms(entry.Rule, expr, pending)...)
 	return problems
 }
 
@@ -151,10 +154,17 @@ func (c RateCheck) collectRateMetricNames(expr *parser.PromQLExpr, entries []*di
 		})
 	}
 
+	for _, ha := range findClassicHistogramArgs(expr) {
+		if !ha.src.CanHaveLabel(model.BucketLabel) {
+			add(ha.metric)
+		}
+	}
+
 	return names
 }
 
 func (c RateCheck) checkSources(
+	ctx context.Context,
 	rule parser.Rule,
 	expr *parser.PromQLExpr,
 	entries []*discovery.Entry,
@@ -175,7 +185,11 @@ func (c RateCheck) checkSources(
 				return
 			}
 
-			if m.Range < cfg.Config.Global.ScrapeInterval*time.Duration(c.minIntervals) {
+			// MatrixSelector always wraps a VectorSelector, so this can't fail.
+			vs, _ := source.MostOuterOperation[*promParser.VectorSelector](s)
+
+			si := resolveScrapeInterval(ctx, c.prom, cfg, vs)
+			if m.Range < si.interval*time.Duration(c.minIntervals) {
 				problems = append(problems, Problem{
 					Anchor:   AnchorAfter,
 					Lines:    expr.Value.Pos.Lines(),
@@ -186,10 +200,10 @@ func (c RateCheck) checkSources(
 					Diagnostics: []diags.Diagnostic{
 						{
 							Message: fmt.Sprintf(
-								"Duration for `%s()` must be at least %d x scrape_interval, %s is using `%s` scrape_interval.",
+								"Duration for `%s()` must be at least %d x scrape_interval, %s is using `%s` %s.",
 								call.Func.Name, c.minIntervals,
 								promText(c.prom.Name(), cfg.URI),
-								output.HumanizeDuration(cfg.Config.Global.ScrapeInterval),
+								output.HumanizeDuration(si.interval), si,
 							),
 							Pos:         expr.Value.Pos,
 							Expr:        expr.Query().Expr,
@@ -206,9 +220,6 @@ func (c RateCheck) checkSources(
 				return
 			}
 
-			// MatrixSelector always wraps a VectorSelector, so this can't fail.
-			vs, _ := source.MostOuterOperation[*promParser.VectorSelector](s)
-
 			if _, ok := done[vs.Name]; ok {
 				return
 			}
@@ -336,3 +347,96 @@ func findRateCall(s *source.Source) (*promParser.Call, bool) {
 	}
 	return nil, false
 }
+
+type classicHistogramArg struct {
+	call     *promParser.Call
+	src      *source.Source
+	selector *promParser.VectorSelector
+	metric   string
+}
+
+// findClassicHistogramArgs returns all sources passed to histogram_quantile() and
+// histogram_fraction() calls that are reading classic histogram buckets.
+func findClassicHistogramArgs(expr *parser.PromQLExpr) (args []classicHistogramArg) {
+	for _, node := range parser.WalkDownExpr[*promParser.Call](expr.Query()) {
+		call := node.Expr.(*promParser.Call)
+		if call.Func.Name != "histogram_quantile" && call.Func.Name != "histogram_fraction" {
+			continue
+		}
+		for _, s := range source.LabelsSource(expr.Value.Value, call.Args[len(call.Args)-1]) {
+			if s.Histogram != source.ClassicHistogram {
+				continue
+			}
+			vs, ok := source.MostOuterOperation[*promParser.VectorSelector](s)
+			if !ok {
+				continue
+			}
+			args = append(args, classicHistogramArg{
+				call:     call,
+				src:      s,
+				selector: vs,
+				metric:   strings.TrimSuffix(vs.Name, "_bucket"),
+			})
+		}
+	}
+	return args
+}
+
+func (c RateCheck) checkHistograms(
+	rule parser.Rule,
+	expr *parser.PromQLExpr,
+	pending map[string]*promapi.Request[*promapi.MetadataResult],
+) (problems []Problem) {
+	for _, ha := range findClassicHistogramArgs(expr) {
+		if ha.src.CanHaveLabel(model.BucketLabel) {
+			continue
+		}
+
+		metadata, err := pending[ha.metric].Wait()
+		if err != nil {
+			if errors.Is(err, promapi.ErrUnsupported) {
+				return problems
+			}
+			problems = append(problems, problemFromError(err, rule, c.Reporter(), c.prom.Name(), Bug))
+			return problems
+		}
+		if !slices.ContainsFunc(metadata.Metadata, func(md v1.Metadata) bool {
+			return md.Type == v1.MetricTypeHistogram
+		}) {
+			continue
+		}
+
+		reason, fragment := ha.src.LabelExcludeReason(model.BucketLabel)
+		problems = append(problems, Problem{
+			Anchor:   AnchorAfter,
+			Lines:    expr.Value.Pos.Lines(),
+			Reporter: c.Reporter(),
+			Summary:  "classic histogram without le label",
+			Details:  RateCheckHistogramDetails,
+			Severity: Bug,
+			Diagnostics: []diags.Diagnostic{
+				{
+					Message: fmt.Sprintf(
+						"`%s()` is called on buckets of `%s` which is a classic histogram according to metrics metadata from %s, but the `%s` label is removed from `%s` before that, this query will never return anything.",
+						ha.call.Func.Name, ha.metric, promText(c.prom.Name(), metadata.URI),
+						model.BucketLabel, ha.selector.Name,
+					),
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(ha.call.PosRange.Start) + 1,
+					LastColumn:  int(ha.call.PosRange.End),
+					Kind:        diags.Issue,
+				},
+				{
+					Message:     reason,
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(fragment.Start) + 1,
+					LastColumn:  int(fragment.End),
+					Kind:        diags.Context,
+				},
+			},
+		})
+	}
+	return problems
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/discovery"
@@ -890,6 +891,278 @@ func TestRateCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "histogram_quantile with le",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le, job))\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo_bucket"},
+					},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+			},
+		},
+		{
+			description: "histogram_quantile without le",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(job))\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo_bucket"},
+					},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo"},
+					},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "histogram"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "histogram_quantile without le on a non-histogram",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(job))\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo_bucket"},
+					},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo"},
+					},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "gauge"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "histogram_fraction without le",
+			content:     "- record: foo\n  expr: histogram_fraction(0, 0.1, sum(rate(foo_bucket[5m])))\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo_bucket"},
+					},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo"},
+					},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "histogram"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "histogram_quantile without le with metadata error",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum without(le) (rate(foo_bucket[5m])))\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo_bucket"},
+					},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{}},
+				},
+				{
+					conds: []requestCondition{
+						requireMetadataPath,
+						formCond{"metric", "foo"},
+					},
+					resp: respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "rate < 2x job scrape_interval",
+			content:     "- record: foo\n  expr: rate(foo{job=\"slow\"}[3m])\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: fast\n- job_name: slow\n  scrape_interval: 2m\n"},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "counter"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "rate == 2x job scrape_interval",
+			content:     "- record: foo\n  expr: rate(foo{job=\"slow\"}[4m])\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: fast\n- job_name: slow\n  scrape_interval: 2m\n"},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "counter"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "rate > 2x job scrape_interval, < 2x global scrape_interval",
+			content:     "- record: foo\n  expr: rate(foo{job=\"fast\"}[1m])\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 1m\nscrape_configs:\n- job_name: fast\n  scrape_interval: 15s\n"},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "counter"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "rate < 2x scrape_interval of jobs from series",
+			content:     "- record: foo\n  expr: rate(foo[3m])\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: fast\n- job_name: slow\n  scrape_interval: 2m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(foo) by (job)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSample(map[string]string{"job": "fast"}),
+							generateSample(map[string]string{"job": "slow"}),
+						},
+					},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "counter"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "rate with series from unknown jobs",
+			content:     "- record: foo\n  expr: rate(foo[1m])\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: fast\n- job_name: slow\n  scrape_interval: 2m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(foo) by (job)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSample(map[string]string{"job": "other"}),
+						},
+					},
+				},
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "counter"}},
+					}},
+				},
+			},
+		},
+		{
+			description: "rate with series query error",
+			content:     "- record: foo\n  expr: rate(foo[1m])\n",
+			checker:     newRateCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  scrape_interval: 15s\nscrape_configs:\n- job_name: fast\n- job_name: slow\n  scrape_interval: 2m\n"},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(foo) by (job)"},
+					},
+					resp: respondWithInternalError(),
+				},
+				{
+					conds: []requestCondition{requireMetadataPath},
+					resp: metadataResponse{metadata: map[string][]v1.Metadata{
+						"foo": {{Type: "counter"}},
+					}},
+				},
+			},
+		},
 	}
 	runTests(t, testCases)
 }
@@ -99,7 +99,9 @@ To fully validate your changes it's best to first deploy the rules that generate
 	SeriesCheckCommonProblemDetails   = `[Click here](https://cloudflare.github.io/pint/checks/promql/series.html#common-problems) to see a list of common problems that might cause this.`
 	SeriesCheckMinAgeDetails          = `You have a comment that tells pint how long a metric can be missing before it warns you about it but this comment is not formatted correctly.
 [Click here](https://cloudflare.github.io/pint/checks/promql/series.html#min-age) to see supported syntax.`
-	SeriesCheckUnusedDisableComment = "One of the `# pint disable promql/series` comments used in this rule doesn't have any effect and won't disable anything. Make sure that the comment targets series that are used in the rule query and are not already ignored.\n[Click here](https://cloudflare.github.io/pint/checks/promql/series.html#how-to-disable-it) to see docs about disable comment syntax."
+	SeriesCheckUnusedDisableComment   = "One of the `# pint disable promql/series` comments used in this rule doesn't have any effect and won't disable anything. Make sure that the comment targets series that are used in the rule query and are not already ignored.\n[Click here](https://cloudflare.github.io/pint/checks/promql/series.html#how-to-disable-it) to see docs about disable comment syntax."
+	SeriesCheckNativeHistogramDetails = `Native histograms are stored as a single time series with all buckets stored in each sample, there are no separate ` + "`_bucket`" + ` series like with classic histograms.
+To query native histograms use the metric name without any suffix, for example ` + "`histogram_quantile(0.9, sum(rate(foo[5m])))`" + ` instead of ` + "`histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le))`" + `.`
 	SeriesCheckUnusedRuleSetComment = "One of the `# pint rule/set promql/series` comments used in this rule doesn't have any effect. Make sure that the comment targets series and labels that are used in the rule query and are not already ignored.\n[Click here](https://cloudflare.github.io/pint/checks/promql/series.html#ignorelabel-value) for docs about comment syntax."
 )
 
@@ -364,6 +366,37 @@ func (c SeriesCheck) Check(ctx context.Context, entry *discovery.Entry, entries
 				continue
 			}
 
+			if metric, ok := strings.CutSuffix(bareSelectorString, "_bucket"); ok && metric != "" {
+				slog.LogAttrs(ctx, slog.LevelDebug, "Checking if metric is a native histogram", slog.String("check", c.Reporter()), slog.String("selector", metric))
+				native, err := c.instantSeriesCount(ctx, wrapExpr(wrapExpr(metric, "histogram_count"), "count"))
+				if err != nil {
+					problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+					continue
+				}
+				if native > 0 {
+					problems = append(problems, Problem{
+						Anchor:   AnchorAfter,
+						Lines:    expr.Value.Pos.Lines(),
+						Reporter: c.Reporter(),
+						Summary:  "query on classic histogram buckets of a native histogram",
+						Details:  SeriesCheckNativeHistogramDetails,
+						Severity: Bug,
+						Diagnostics: []diags.Diagnostic{
+							{
+								Message: fmt.Sprintf("%s didn't have any series for the `%s` metric in the last %s but `%s` is exported as a native histogram, which doesn't have any `_bucket` series.",
+									promText(c.prom.Name(), trs.URI), bareSelectorString, sinceDesc(trs.Series.From), metric),
+								Pos:         expr.Value.Pos,
+								Expr:        expr.Query().Expr,
+								FirstColumn: int(selector.PosRange.Start) + 1,
+								LastColumn:  int(selector.PosRange.End),
+								Kind:        diags.Issue,
+							},
+						},
+					})
+					continue
+				}
+			}
+
 			text, severity := c.textAndSeverity(
 				settings,
 				bareSelectorString,
@@ -806,7 +839,7 @@ func (c SeriesCheck) checkOtherServer(ctx context.Context, query string, setting
 		)
 		pending = append(pending, pendingQuery{
 			prom:    prom,
-			pending: prom.Query(queryCtx, wrapExpr(query, "count")),
+			pending: prom.BatchQuery(queryCtx, wrapExpr(query, "count")),
 		})
 	}
 
@@ -904,7 +937,7 @@ func (c SeriesCheck) hasSeriesWithSelector(ctx context.Context, prom *promapi.Fa
 }
 
 func (c SeriesCheck) instantSeriesCount(ctx context.Context, query string) (int, error) {
-	qr, err := c.prom.Query(ctx, query).Wait()
+	qr, err := c.prom.BatchQuery(ctx, query).Wait()
 	if err != nil {
 		return 0, err
 	}
@@ -3923,6 +3923,80 @@ func TestSeriesCheck(t *testing.T) {
 			checker:    newSeriesCheck,
 			prometheus: newSimpleProm,
 		},
+		{
+			description: "#2 series never present, native histogram",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newSeriesCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nhistogram_count(\nfoo\n)\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 3)},
+					},
+				},
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{requireRangeQueryPath},
+					resp:  respondWithEmptyMatrix(),
+				},
+			},
+		},
+		{
+			description: "#2 series never present, classic histogram",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newSeriesCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nhistogram_count(\nfoo\n)\n)"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{requireRangeQueryPath},
+					resp:  respondWithEmptyMatrix(),
+				},
+			},
+		},
+		{
+			description: "#2 series never present, native histogram query error",
+			content:     "- record: foo\n  expr: histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le))\n",
+			checker:     newSeriesCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nhistogram_count(\nfoo\n)\n)"},
+					},
+					resp: respondWithInternalError(),
+				},
+				{
+					conds: []requestCondition{requireQueryPath},
+					resp:  respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{requireRangeQueryPath},
+					resp:  respondWithEmptyMatrix(),
+				},
+			},
+		},
 	}
 	runTests(t, testCases)
 }
@@ -0,0 +1,295 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/source"
+	"github.com/cloudflare/pint/internal/promapi"
+
+	promParser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/promql/parser/posrange"
+)
+
+const (
+	SubqueryCheckName = "promql/subquery"
+
+	// Same as the maximum resolution of range queries in Prometheus.
+	subqueryMaxPoints = 11000
+
+	// Default value of the --query.max-samples Prometheus flag.
+	defaultQueryMaxSamples = 50000000
+)
+
+// Functions where applying the same function to a subquery of it
+// gives the same result as using a longer range.
+var subqueryMergeableFuncs = []string{"max_over_time", "min_over_time"}
+
+// NewSubqueryCheck creates a new promql/subquery check.
+// If prom is nil then only the query itself is checked, otherwise it will
+// use Prometheus configuration and series counts to estimate subquery cost.
+func NewSubqueryCheck(prom *promapi.FailoverGroup) SubqueryCheck {
+	instance := SubqueryCheckName
+	if prom != nil {
+		instance = fmt.Sprintf("%s(%s)", SubqueryCheckName, prom.Name())
+	}
+	return SubqueryCheck{
+		prom:     prom,
+		instance: instance,
+	}
+}
+
+type SubqueryCheck struct {
+	prom     *promapi.FailoverGroup
+	instance string
+}
+
+func (c SubqueryCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        c.prom != nil,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c SubqueryCheck) String() string {
+	return c.instance
+}
+
+func (c SubqueryCheck) Reporter() string {
+	return SubqueryCheckName
+}
+
+func (c SubqueryCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	expr := entry.Rule.Expr()
+	if expr.SyntaxError() != nil {
+		return problems
+	}
+
+	subqueries := findSubqueries(expr)
+	if len(subqueries) == 0 {
+		return problems
+	}
+
+	if c.prom != nil {
+		return c.checkCost(ctx, entry, expr, subqueries)
+	}
+
+	for _, sq := range subqueries {
+		if sq.nested != nil {
+			problems = append(problems, c.newProblem(expr, sq.node, "nested subquery", Warning,
+				fmt.Sprintf("`%s` subquery is nested inside another subquery, which will run it again for every step of the outer subquery.", sq.nested)))
+		}
+
+		if sq.node.Step > 0 {
+			if points := sq.node.Range / sq.node.Step; points > subqueryMaxPoints {
+				problems = append(problems, c.newProblem(expr, sq.node, "expensive subquery", Warning,
+					fmt.Sprintf("`%s` subquery will evaluate `%s` query %d times on every rule evaluation, try using a longer step or a shorter range.",
+						subqueryRangeText(sq.node), sq.node.Expr, points)))
+			}
+
+			if entry.Group.Interval != nil && sq.node.Step < entry.Group.Interval.Value {
+				problems = append(problems, c.newProblem(expr, sq.node, "subquery resolution finer than group interval", Information,
+					fmt.Sprintf("`%s` subquery is using `%s` step but this rule is only evaluated every `%s`, using a step that is shorter than the group interval is more expensive without giving any more accurate results.",
+						subqueryRangeText(sq.node), output.HumanizeDuration(sq.node.Step), output.HumanizeDuration(entry.Group.Interval.Value))))
+			}
+
+			if sq.innerCall != nil && sq.innerRange > 0 && sq.innerRange < sq.node.Step {
+				problems = append(problems, c.newProblem(expr, sq.node, "subquery is skipping samples", Warning,
+					fmt.Sprintf("`%s()` inside `%s` subquery is only looking at `%s` of data on each step, but the subquery step is `%s`, so %s of samples between each step will be ignored.",
+						sq.innerCall.Func.Name, subqueryRangeText(sq.node), output.HumanizeDuration(sq.innerRange),
+						output.HumanizeDuration(sq.node.Step), output.HumanizeDuration(sq.node.Step-sq.innerRange))))
+			}
+		}
+
+		if sq.outerCall != nil && sq.innerCall != nil && sq.outerCall.Func.Name == sq.innerCall.Func.Name &&
+			slices.Contains(subqueryMergeableFuncs, sq.outerCall.Func.Name) && sq.innerRange > 0 &&
+			(sq.node.Step == 0 || sq.node.Step <= sq.innerRange) {
+			problems = append(problems, c.newProblem(expr, sq.node, "redundant subquery", Information,
+				fmt.Sprintf("`%s()` is applied to a subquery of `%s()`, you can use a single `%s()` call with `[%s]` range instead.",
+					sq.outerCall.Func.Name, sq.innerCall.Func.Name, sq.outerCall.Func.Name,
+					output.HumanizeDuration(sq.node.Range+sq.innerRange))))
+		}
+	}
+
+	return problems
+}
+
+func (c SubqueryCheck) checkCost(ctx context.Context, entry *discovery.Entry, expr *parser.PromQLExpr, subqueries []subquery) (problems []Problem) {
+	cfg, err := c.prom.Config(ctx, 0).Wait()
+	if err != nil {
+		if errors.Is(err, promapi.ErrUnsupported) {
+			c.prom.DisableCheck(promapi.APIPathConfig, c.Reporter())
+			return problems
+		}
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+		return problems
+	}
+
+	maxSamples := defaultQueryMaxSamples
+	flags, err := c.prom.Flags(ctx).Wait()
+	switch {
+	case errors.Is(err, promapi.ErrUnsupported):
+		c.prom.DisableCheck(promapi.APIPathFlags, c.Reporter())
+	case err != nil:
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+		return problems
+	default:
+		if v, ok := flags.Flags["query.max-samples"]; ok {
+			if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+				maxSamples = ms
+			}
+		}
+	}
+
+	for _, sq := range subqueries {
+		step := sq.node.Step
+		if step == 0 {
+			// Subqueries without a step are using the global evaluation interval.
+			step = cfg.Config.Global.EvaluationInterval
+		} else if entry.Group.Interval == nil && step < cfg.Config.Global.EvaluationInterval {
+			problems = append(problems, c.newProblem(expr, sq.node, "subquery resolution finer than group interval", Information,
+				fmt.Sprintf("`%s` subquery is using `%s` step but this rule is only evaluated every `%s` according to the global:evaluation_interval of %s, using a step that is shorter than the group interval is more expensive without giving any more accurate results.",
+					subqueryRangeText(sq.node), output.HumanizeDuration(step), output.HumanizeDuration(cfg.Config.Global.EvaluationInterval),
+					promText(c.prom.Name(), cfg.URI))))
+		}
+
+		query := wrapExpr(sq.node.Expr.String(), "count")
+		qr, err := c.prom.Query(ctx, query).Wait()
+		if err != nil {
+			problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+			continue
+		}
+		var series int
+		for _, s := range qr.Series {
+			series += int(s.Value)
+		}
+
+		points := int(sq.node.Range / step)
+		if samples := series * points; samples > maxSamples {
+			problems = append(problems, c.newProblem(expr, sq.node, "subquery will load too many samples", Bug,
+				fmt.Sprintf("`%s` subquery will load %d sample(s), %s currently returns %d series for `%s` query and the subquery will evaluate it %d times, this is more than the `--query.max-samples=%d` limit, so this query will fail.",
+					subqueryRangeText(sq.node), samples, promText(c.prom.Name(), qr.URI), series, sq.node.Expr, points, maxSamples)))
+		}
+	}
+
+	return problems
+}
+
+func (c SubqueryCheck) newProblem(expr *parser.PromQLExpr, sq *promParser.SubqueryExpr, summary string, severity Severity, msg string) Problem {
+	return Problem{
+		Anchor:   AnchorAfter,
+		Lines:    expr.Value.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  summary,
+		Details:  "",
+		Severity: severity,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message:     msg,
+				Pos:         expr.Value.Pos,
+				Expr:        expr.Query().Expr,
+				FirstColumn: int(sq.PositionRange().Start) + 1,
+				LastColumn:  int(sq.PositionRange().End),
+				Kind:        diags.Issue,
+			},
+		},
+	}
+}
+
+type subquery struct {
+	node *promParser.SubqueryExpr
+	// Subquery nested inside this one.
+	nested *promParser.SubqueryExpr
+	// Function call this subquery is passed to.
+	outerCall *promParser.Call
+	// Function call that this subquery wraps, if it takes a range.
+	innerCall  *promParser.Call
+	innerRange time.Duration
+}
+
+// findSubqueries returns all unique subqueries used in the query.
+func findSubqueries(expr *parser.PromQLExpr) (subqueries []subquery) {
+	seen := map[posrange.PositionRange]struct{}{}
+	for _, src := range expr.Source() {
+		src.WalkSources(func(s *source.Source, _ *source.Join, _ *source.Unless) {
+			var nodes []*promParser.SubqueryExpr
+			for _, op := range s.Operations {
+				if sq, ok := op.Node.(*promParser.SubqueryExpr); ok {
+					nodes = append(nodes, sq)
+				}
+			}
+			for _, node := range nodes {
+				if _, ok := seen[node.PositionRange()]; ok {
+					continue
+				}
+				seen[node.PositionRange()] = struct{}{}
+
+				sq := subquery{node: node} // nolint: exhaustruct
+				for _, other := range nodes {
+					if other != node && subqueryContains(node, other) {
+						sq.nested = other
+					}
+				}
+				for _, op := range s.Operations {
+					call, ok := op.Node.(*promParser.Call)
+					if !ok {
+						continue
+					}
+					if slices.ContainsFunc(call.Args, func(e promParser.Expr) bool { return unwrapParens(e) == node }) {
+						sq.outerCall = call
+					}
+				}
+				if call, ok := unwrapParens(node.Expr).(*promParser.Call); ok {
+					for _, arg := range call.Args {
+						if ms, ok := arg.(*promParser.MatrixSelector); ok {
+							sq.innerCall = call
+							sq.innerRange = ms.Range
+						}
+					}
+				}
+				subqueries = append(subqueries, sq)
+			}
+		})
+	}
+	slices.SortFunc(subqueries, func(a, b subquery) int {
+		return int(a.node.PositionRange().Start - b.node.PositionRange().Start)
+	})
+	return subqueries
+}
+
+func subqueryContains(outer, inner *promParser.SubqueryExpr) bool {
+	return inner.PositionRange().Start >= outer.PositionRange().Start &&
+		inner.PositionRange().End <= outer.PositionRange().End
+}
+
+func unwrapParens(e promParser.Expr) promParser.Expr {
+	for {
+		pe, ok := e.(*promParser.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = pe.Expr
+	}
+}
+
+func subqueryRangeText(sq *promParser.SubqueryExpr) string {
+	var step string
+	if sq.Step > 0 {
+		step = output.HumanizeDuration(sq.Step)
+	}
+	return fmt.Sprintf("[%s:%s]", output.HumanizeDuration(sq.Range), step)
+}
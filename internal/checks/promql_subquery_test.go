@@ -0,0 +1,305 @@
+package checks_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newSubqueryCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewSubqueryCheck(nil)
+}
+
+func newSubqueryPromCheck(prom *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewSubqueryCheck(prom)
+}
+
+func TestSubqueryCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "ignores queries without subqueries",
+			content:     "- record: foo\n  expr: max_over_time(foo[1h])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "ignores cheap subquery",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:1m])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "ignores subquery without step",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "nested subquery",
+			content:     "- record: foo\n  expr: max_over_time(avg_over_time(rate(foo[5m])[1h:1m])[1d:1h])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "expensive subquery",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[30d:1m])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "subquery step equal to group interval",
+			content: `
+- name: test
+  interval: 1m
+  rules:
+  - record: foo
+    expr: max_over_time(rate(foo[5m])[1h:1m])
+`,
+			checker:    newSubqueryCheck,
+			prometheus: noProm,
+		},
+		{
+			description: "subquery step finer than group interval",
+			content: `
+- name: test
+  interval: 5m
+  rules:
+  - record: foo
+    expr: max_over_time(rate(foo[5m])[1h:1m])
+`,
+			checker:    newSubqueryCheck,
+			prometheus: noProm,
+			problems:   true,
+		},
+		{
+			description: "subquery is skipping samples",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[2m])[1h:5m])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "redundant max_over_time subquery",
+			content:     "- record: foo\n  expr: max_over_time(max_over_time(foo[5m])[1h:5m])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "redundant min_over_time subquery",
+			content:     "- record: foo\n  expr: min_over_time((min_over_time(foo[10m]))[1h:])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "avg_over_time of avg_over_time is not redundant",
+			content:     "- record: foo\n  expr: avg_over_time(avg_over_time(foo[5m])[1h:5m])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "multiple subqueries",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[30d:1m]) / max_over_time(rate(bar[1m])[1h:5m])\n",
+			checker:     newSubqueryCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "ignores queries without subqueries / online",
+			content:     "- record: foo\n  expr: max_over_time(foo[1h])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "config error",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:1m])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "config unsupported",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:1m])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  httpResponse{code: http.StatusNotFound, body: "Not Found"},
+				},
+			},
+		},
+		{
+			description: "flags error",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:1m])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  evaluation_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "query error",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:1m])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  evaluation_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nrate(foo[5m])\n)"},
+					},
+					resp: respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "sample count below default limit",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:1m])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  evaluation_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nrate(foo[5m])\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{}, 1000),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "sample count above max-samples flag",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:1m])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  evaluation_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp: flagsResponse{flags: map[string]string{
+						"query.max-samples": "50000",
+					}},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nrate(foo[5m])\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{}, 1000),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "subquery without step uses evaluation_interval",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1d:])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  evaluation_interval: 10s\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp: flagsResponse{flags: map[string]string{
+						"query.max-samples": "1000000",
+					}},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nrate(foo[5m])\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{}, 200),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "subquery step finer than evaluation_interval",
+			content:     "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:30s])\n",
+			checker:     newSubqueryPromCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireConfigPath},
+					resp:  configResponse{yaml: "global:\n  evaluation_interval: 1m\n"},
+				},
+				{
+					conds: []requestCondition{requireFlagsPath},
+					resp:  flagsResponse{flags: map[string]string{}},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nrate(foo[5m])\n)"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+			},
+		},
+	}
+	runTests(t, testCases)
+}
@@ -11,8 +11,9 @@ import (
 )
 
 const (
-	SyntaxCheckName    = "promql/syntax"
-	SyntaxCheckDetails = "[Click here](https://prometheus.io/docs/prometheus/latest/querying/basics/) for PromQL documentation."
+	SyntaxCheckName         = "promql/syntax"
+	SyntaxCheckDetails      = "[Click here](https://prometheus.io/docs/prometheus/latest/querying/basics/) for PromQL documentation."
+	SyntaxCheckLogQLDetails = "[Click here](https://grafana.com/docs/loki/latest/query/) for LogQL documentation."
 )
 
 func NewSyntaxCheck() SyntaxCheck {
@@ -71,12 +72,17 @@ func (c SyntaxCheck) Check(_ context.Context, entry *discovery.Entry, _ []*disco
 			}
 		}
 
+		summary, details := "PromQL syntax error", SyntaxCheckDetails
+		if expr.IsLogQL() {
+			summary, details = "LogQL syntax error", SyntaxCheckLogQLDetails
+		}
+
 		problems = append(problems, Problem{
 			Anchor:      AnchorAfter,
 			Lines:       expr.Value.Pos.Lines(),
 			Reporter:    c.Reporter(),
-			Summary:     "PromQL syntax error",
-			Details:     SyntaxCheckDetails,
+			Summary:     summary,
+			Details:     details,
 			Diagnostics: []diags.Diagnostic{diag},
 			Severity:    Fatal,
 		})
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/promapi"
 )
 
@@ -39,6 +40,29 @@ func TestSyntaxCheck(t *testing.T) {
 			prometheus:  noProm,
 			problems:    true,
 		},
+		{
+			description:   "valid LogQL query",
+			content:       "- alert: foo\n  expr: sum(count_over_time({app=\"foo\"} |= \"error\" [5m])) > 0\n",
+			checker:       newSyntaxCheck,
+			prometheus:    noProm,
+			contentSchema: parser.LokiSchema,
+		},
+		{
+			description:   "invalid LogQL query",
+			content:       "- alert: foo\n  expr: sum(count_over_time({app=\"foo\"} |= \"error\")) > 0\n",
+			checker:       newSyntaxCheck,
+			prometheus:    noProm,
+			contentSchema: parser.LokiSchema,
+			problems:      true,
+		},
+		{
+			description:   "PromQL query in Loki rules",
+			content:       "- record: foo\n  expr: sum(rate(foo[5m]))\n",
+			checker:       newSyntaxCheck,
+			prometheus:    noProm,
+			contentSchema: parser.LokiSchema,
+			problems:      true,
+		},
 	}
 	runTests(t, testCases)
 }
@@ -93,8 +93,16 @@ func (c VectorMatchingCheck) checkNode(ctx context.Context, rule parser.Rule, ex
 		}
 
 		q := wrapExpr(n.String(), "count")
-		qr, err := c.prom.Query(ctx, q).Wait()
+		qr, err := c.prom.BatchQuery(ctx, q).Wait()
 		if err != nil {
+			// Query might have failed because there are duplicated series on the "one" side
+			// of the binary operation, try to find them and report that instead.
+			if !promapi.IsUnavailableError(err) && !promapi.IsQueryTooExpensive(err) {
+				if dups := c.checkDuplicates(ctx, expr, n); len(dups) > 0 {
+					problems = append(problems, dups...)
+					return problems
+				}
+			}
 			problems = append(problems, problemFromError(err, rule, c.Reporter(), c.prom.Name(), Bug))
 			return problems
 		}
@@ -361,6 +369,91 @@ func canJoinStatically(lhsSources, rhsSources []*source.Source, vm *promParser.V
 	return true
 }
 
+// checkDuplicates runs a count query on each side of the binary operation that
+// must return unique series for every match group and reports any match group
+// with more than one series.
+func (c VectorMatchingCheck) checkDuplicates(ctx context.Context, expr *parser.PromQLExpr, n *promParser.BinaryExpr) (problems []Problem) {
+	type binarySide struct {
+		node promParser.Expr
+		name string
+		hint string
+	}
+
+	var sides []binarySide
+	switch n.VectorMatching.Card {
+	case promParser.CardOneToOne:
+		sides = append(sides,
+			binarySide{node: n.LHS, name: "left", hint: "If this is expected then use `group_left` to allow many-to-one matching."},
+			binarySide{node: n.RHS, name: "right", hint: "If this is expected then use `group_right` to allow one-to-many matching."},
+		)
+	case promParser.CardManyToOne:
+		sides = append(sides, binarySide{node: n.RHS, name: "right", hint: "You need to match on more labels to make each match group unique."})
+	case promParser.CardOneToMany:
+		sides = append(sides, binarySide{node: n.LHS, name: "left", hint: "You need to match on more labels to make each match group unique."})
+	case promParser.CardManyToMany:
+	}
+
+	opPos := source.FindBinOpsOperatorPosition(expr.Value.Value, n, promParser.ItemTypeStr[n.Op])
+	for _, side := range sides {
+		q := matchGroupsQuery(side.node.String(), n.VectorMatching)
+		qr, err := c.prom.Query(ctx, q).Wait()
+		if err != nil || len(qr.Series) == 0 {
+			continue
+		}
+
+		sidePos := side.node.PositionRange()
+		link := fmt.Sprintf("%s/query?g0.expr=%s&&g0.tab=table", qr.URI, url.QueryEscape(q))
+		problems = append(problems, Problem{
+			Anchor:   AnchorAfter,
+			Lines:    expr.Value.Pos.Lines(),
+			Reporter: c.Reporter(),
+			Summary:  "duplicated series in binary operation",
+			Details:  VectorMatchingCheckDetails,
+			Severity: Bug,
+			Diagnostics: []diags.Diagnostic{
+				{
+					Message: fmt.Sprintf(
+						"The %s hand side of this binary operation must return only one series for each match group, but [this query](%s) on %s found %d match group(s) with duplicated series, for example there are %d series matching `%s`. %s",
+						side.name, link, promText(c.prom.Name(), qr.URI), len(qr.Series),
+						int(qr.Series[0].Value), qr.Series[0].Labels.String(), side.hint,
+					),
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(opPos.Start) + 1,
+					LastColumn:  int(opPos.End),
+					Kind:        diags.Issue,
+				},
+				{
+					Message:     fmt.Sprintf("Duplicated series are returned from the %s hand side.", side.name),
+					Pos:         expr.Value.Pos,
+					Expr:        expr.Query().Expr,
+					FirstColumn: int(sidePos.Start) + 1,
+					LastColumn:  int(sidePos.End),
+					Kind:        diags.Context,
+				},
+			},
+		})
+	}
+
+	return problems
+}
+
+// matchGroupsQuery returns a query that counts series for each match group
+// and only returns match groups with more than one series.
+func matchGroupsQuery(query string, vm *promParser.VectorMatching) string {
+	var expr strings.Builder
+	expr.WriteString(wrapExpr(query, "count"))
+	if vm.On {
+		expr.WriteString(" by(")
+		expr.WriteString(strings.Join(vm.MatchingLabels, ","))
+	} else {
+		expr.WriteString(" without(")
+		expr.WriteString(strings.Join(append([]string{model.MetricNameLabel}, vm.MatchingLabels...), ","))
+	}
+	expr.WriteString(") > 1")
+	return expr.String()
+}
+
 // removeConditions recursively strips scalar comparisons from a PromQL AST.
 // It walks through aggregations, function calls (vector/matrix args only),
 // subqueries, parens, and unary expressions to find and remove binary
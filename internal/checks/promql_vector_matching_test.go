@@ -1386,6 +1386,145 @@ func TestVectorMatchingCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "duplicated series on the right hand side",
+			content:     "- record: foo\n  expr: foo / bar\n",
+			checker:     newVectorMatchingCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo / bar\n)"},
+					},
+					resp: respondWithDuplicateSeries(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n) without(__name__) > 1"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nbar\n) without(__name__) > 1"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{"job": "a", "instance": "b"}, 2),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "duplicated series on both sides",
+			content:     "- record: foo\n  expr: foo / ignoring(instance) bar\n",
+			checker:     newVectorMatchingCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo / ignoring (instance) bar\n)"},
+					},
+					resp: respondWithDuplicateSeries(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n) without(__name__,instance) > 1"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{"job": "a"}, 3),
+							generateSampleWithValue(map[string]string{"job": "b"}, 2),
+						},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nbar\n) without(__name__,instance) > 1"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{"job": "a"}, 2),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "duplicated series on the one side of group_left",
+			content:     "- record: foo\n  expr: sum(foo) by(job, instance) * on(job) group_left(version) bar > 0\n",
+			checker:     newVectorMatchingCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nsum by (job, instance) (foo) * on (job) group_left (version) bar\n)"},
+					},
+					resp: respondWithDuplicateSeries(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nbar\n) by(job) > 1"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{
+							generateSampleWithValue(map[string]string{"job": "a"}, 2),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "no duplicated series on the one side of group_right",
+			content:     "- record: foo\n  expr: foo * on(job) group_right bar\n",
+			checker:     newVectorMatchingCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo * on (job) group_right () bar\n)"},
+					},
+					resp: respondWithDuplicateSeries(),
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n) by(job) > 1"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+			},
+		},
+		{
+			description: "duplicated series are not checked on connection errors",
+			content:     "- record: foo\n  expr: foo / bar\n",
+			checker:     newVectorMatchingCheck,
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo / bar\n)"},
+					},
+					resp: respondWithInternalError(),
+				},
+			},
+		},
 	}
 	runTests(t, testCases)
 }
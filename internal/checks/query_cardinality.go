@@ -0,0 +1,257 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/source"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	CardinalityCheckName = "query/cardinality"
+)
+
+func NewCardinalityCheck(prom *promapi.FailoverGroup, maxSeries, maxGrowth int, comment string, severity Severity) CardinalityCheck {
+	var instance string
+	if maxSeries > 0 {
+		instance = fmt.Sprintf("%s(%s:%d)", CardinalityCheckName, prom.Name(), maxSeries)
+	} else {
+		instance = fmt.Sprintf("%s(%s)", CardinalityCheckName, prom.Name())
+	}
+
+	return CardinalityCheck{
+		prom:      prom,
+		maxSeries: maxSeries,
+		maxGrowth: maxGrowth,
+		comment:   comment,
+		severity:  severity,
+		instance:  instance,
+	}
+}
+
+type CardinalityCheck struct {
+	prom      *promapi.FailoverGroup
+	comment   string
+	instance  string
+	maxSeries int
+	maxGrowth int
+	severity  Severity
+}
+
+func (c CardinalityCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        true,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c CardinalityCheck) String() string {
+	return c.instance
+}
+
+func (c CardinalityCheck) Reporter() string {
+	return CardinalityCheckName
+}
+
+func (c CardinalityCheck) Check(ctx context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	if entry.Rule.RecordingRule == nil {
+		return problems
+	}
+
+	if c.maxSeries == 0 && c.maxGrowth == 0 {
+		return problems
+	}
+
+	expr := entry.Rule.Expr()
+	if expr.SyntaxError() != nil {
+		return problems
+	}
+
+	if !hasVectorSelector(expr.Source()) {
+		return problems
+	}
+
+	stats, err := c.prom.TSDB(ctx).Wait()
+	if err != nil {
+		if errors.Is(err, promapi.ErrUnsupported) {
+			c.prom.DisableCheck(promapi.APIPathTSDB, c.Reporter())
+			return problems
+		}
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+		return problems
+	}
+
+	after, err := c.estimate(ctx, expr, stats)
+	if err != nil {
+		problems = append(problems, problemFromError(err, entry.Rule, c.Reporter(), c.prom.Name(), Bug))
+		return problems
+	}
+
+	if c.maxSeries > 0 && after.series > c.maxSeries {
+		problems = append(problems, c.report(
+			expr,
+			"recording rule cardinality is too high",
+			fmt.Sprintf("%s data suggests that this recording rule can produce up to %d series, maximum allowed is %d.",
+				promText(c.prom.Name(), stats.URI), after.series, c.maxSeries),
+			after.details(),
+			c.severity,
+		))
+		return problems
+	}
+
+	if c.maxGrowth > 0 && entry.State == discovery.Modified && entry.Before != nil && entry.Before.RecordingRule != nil {
+		beforeExpr := entry.Before.Expr()
+		if beforeExpr.SyntaxError() == nil {
+			before, err := c.estimate(ctx, beforeExpr, stats)
+			if err == nil && before.series > 0 && after.series > before.series {
+				growth := (float64(after.series-before.series) / float64(before.series)) * 100
+				if growth > float64(c.maxGrowth) {
+					problems = append(problems, c.report(
+						expr,
+						"recording rule cardinality grows too much",
+						fmt.Sprintf("%s data suggests that this change increases the number of series produced by this recording rule from %d to %d (%s%%), maximum allowed growth is %d%%.",
+							promText(c.prom.Name(), stats.URI), before.series, after.series, formatDelta(growth), c.maxGrowth),
+						after.details(),
+						c.severity,
+					))
+					return problems
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+func (c CardinalityCheck) report(expr *parser.PromQLExpr, summary, msg, details string, severity Severity) Problem {
+	if c.comment != "" {
+		details = details + "\n\n" + maybeComment(c.comment)
+	}
+	return Problem{
+		Anchor:   AnchorAfter,
+		Lines:    expr.Value.Pos.Lines(),
+		Reporter: c.Reporter(),
+		Summary:  summary,
+		Details:  details,
+		Severity: severity,
+		Diagnostics: []diags.Diagnostic{
+			{
+				Message:     msg,
+				Pos:         expr.Value.Pos,
+				Expr:        expr.Query().Expr,
+				FirstColumn: 1,
+				LastColumn:  len(expr.Value.Value),
+				Kind:        diags.Issue,
+			},
+		},
+	}
+}
+
+type selectorCardinality struct {
+	selector string
+	labels   []string
+	series   int
+	estimate int
+	fixed    bool
+}
+
+type cardinalityEstimate struct {
+	selectors []selectorCardinality
+	series    int
+}
+
+func (ce cardinalityEstimate) details() string {
+	var buf strings.Builder
+	buf.WriteString("This estimate is based on the number of time series for each metric used in this query")
+	buf.WriteString(" and the number of unique values of each label used for aggregation:\n\n")
+	for _, sc := range ce.selectors {
+		buf.WriteString("- `")
+		buf.WriteString(sc.selector)
+		buf.WriteString("` has ")
+		fmt.Fprintf(&buf, "%d series", sc.series)
+		switch {
+		case !sc.fixed:
+		case len(sc.labels) == 0:
+			buf.WriteString(", all labels are removed by aggregation")
+		default:
+			buf.WriteString(", aggregated by `")
+			buf.WriteString(strings.Join(sc.labels, "`, `"))
+			buf.WriteString("`")
+		}
+		fmt.Fprintf(&buf, ", up to %d series.\n", sc.estimate)
+	}
+	return buf.String()
+}
+
+// estimate returns the number of series the query can produce.
+// Binary operations between two vectors return a single source for the side
+// that determines the results, with the other side stored as a join, so only
+// that side is counted. Multiple sources are only returned for queries like
+// "foo or bar", where we use the estimate of the biggest source.
+func (c CardinalityCheck) estimate(ctx context.Context, expr *parser.PromQLExpr, stats *promapi.TSDBResult) (ce cardinalityEstimate, err error) {
+	for _, src := range expr.Source() {
+		vs, ok := source.MostOuterOperation[*promParser.VectorSelector](src)
+		if !ok {
+			continue
+		}
+
+		sc := selectorCardinality{
+			selector: vs.String(),
+			fixed:    src.FixedLabels,
+		}
+
+		slog.LogAttrs(ctx, slog.LevelDebug, "Counting series for a selector", slog.String("selector", sc.selector))
+		qr, err := c.prom.BatchQuery(ctx, wrapExpr(sc.selector, "count")).Wait()
+		if err != nil {
+			return ce, err
+		}
+		for _, s := range qr.Series {
+			sc.series += int(s.Value)
+		}
+		sc.estimate = sc.series
+
+		if sc.fixed {
+			sc.labels = src.TransformedLabels(source.PossibleLabel, source.GuaranteedLabel)
+			slices.Sort(sc.labels)
+			sc.estimate = min(sc.series, labelsCardinality(sc.labels, stats, sc.series))
+		}
+
+		ce.selectors = append(ce.selectors, sc)
+		ce.series = max(ce.series, sc.estimate)
+	}
+	return ce, nil
+}
+
+// labelsCardinality returns the maximum number of unique label sets for given
+// label names, based on the number of unique values of each label.
+// If we don't know how many values any of labels has we return fallback.
+func labelsCardinality(names []string, stats *promapi.TSDBResult, fallback int) int {
+	total := 1
+	for _, name := range names {
+		values, ok := stats.LabelValueCount(name)
+		if !ok {
+			return fallback
+		}
+		total *= values
+		if total > fallback {
+			return fallback
+		}
+	}
+	return total
+}
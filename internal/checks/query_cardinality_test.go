@@ -0,0 +1,435 @@
+package checks_test
+
+import (
+	"net/http"
+	"testing"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newCardinalityCheck(maxSeries, maxGrowth int) func(*promapi.FailoverGroup) checks.RuleChecker {
+	return func(prom *promapi.FailoverGroup) checks.RuleChecker {
+		return checks.NewCardinalityCheck(prom, maxSeries, maxGrowth, "", checks.Bug)
+	}
+}
+
+func TestCardinalityCheck(t *testing.T) {
+	tsdbStats := tsdbResponse{
+		stats: v1.TSDBResult{
+			HeadStats: v1.TSDBHeadStats{NumSeries: 100000},
+			LabelValueCountByLabelName: []v1.Stat{
+				{Name: "instance", Value: 500},
+				{Name: "job", Value: 10},
+				{Name: "cluster", Value: 4},
+			},
+		},
+	}
+
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     newCardinalityCheck(1000, 0),
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores alerting rules",
+			content:     "- alert: foo\n  expr: sum(foo) > 0\n",
+			checker:     newCardinalityCheck(1000, 0),
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "ignores rules without selectors",
+			content:     "- record: foo\n  expr: vector(1)\n",
+			checker:     newCardinalityCheck(1000, 0),
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "tsdb API not supported",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newCardinalityCheck(1000, 0),
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  httpResponse{code: http.StatusNotFound, body: "Not Found"},
+				},
+			},
+		},
+		{
+			description: "tsdb API error",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newCardinalityCheck(1000, 0),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  respondWithInternalError(),
+				},
+			},
+		},
+		{
+			description: "query error",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newCardinalityCheck(1000, 0),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: respondWithBadData(),
+				},
+			},
+		},
+		{
+			description: "missing metric",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newCardinalityCheck(1000, 0),
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: respondWithEmptyVector(),
+				},
+			},
+		},
+		{
+			description: "no limits",
+			content:     "- record: foo\n  expr: sum(rate(foo[5m])) by(job)\n",
+			checker:     newCardinalityCheck(0, 0),
+			prometheus:  newSimpleProm,
+		},
+		{
+			description: "aggregation without labels",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newCardinalityCheck(10, 0),
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "aggregation below maxSeries",
+			content:     "- record: foo\n  expr: sum(foo) by(job, cluster)\n",
+			checker:     newCardinalityCheck(100, 0),
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "aggregation above maxSeries",
+			content:     "- record: foo\n  expr: sum(foo) by(job, instance)\n",
+			checker:     newCardinalityCheck(100, 0),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "unknown label uses series count",
+			content:     "- record: foo\n  expr: sum(foo) by(job, pod)\n",
+			checker:     newCardinalityCheck(100, 0),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 200)},
+					},
+				},
+			},
+		},
+		{
+			description: "multiple sources",
+			content:     "- record: foo\n  expr: sum(foo{job=\"a\"}) by(cluster) or bar\n",
+			checker:     newCardinalityCheck(100, 0),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo{job=\"a\"}\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nbar\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 150)},
+					},
+				},
+			},
+		},
+		{
+			description: "multiple sources below maxSeries",
+			content:     "- record: foo\n  expr: sum(foo) by(job) or sum(bar) by(job)\n",
+			checker:     newCardinalityCheck(15, 0),
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nbar\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "binary operation uses left side",
+			content:     "- record: foo\n  expr: sum(foo) by(job) / sum(bar) by(job)\n",
+			checker:     newCardinalityCheck(15, 0),
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "binary operation with on() above maxSeries",
+			content:     "- record: foo\n  expr: foo / on(instance) bar\n",
+			checker:     newCardinalityCheck(100, 0),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "binary operation with group_right() uses right side",
+			content:     "- record: foo\n  expr: sum(foo) by(job) * on(job) group_right() bar\n",
+			checker:     newCardinalityCheck(100, 0),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nbar\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "growth below maxGrowth",
+			content:     "- record: foo\n  expr: sum(foo) by(job, cluster)\n",
+			before:      "- record: foo\n  expr: sum(foo) by(job)\n",
+			checker:     newCardinalityCheck(0, 400),
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "growth above maxGrowth",
+			content:     "- record: foo\n  expr: sum(foo) by(job, cluster)\n",
+			before:      "- record: foo\n  expr: sum(foo) by(job)\n",
+			checker:     newCardinalityCheck(0, 50),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "growth with a different source metric",
+			content:     "- record: foo\n  expr: sum(bar) by(job)\n",
+			before:      "- record: foo\n  expr: sum(foo) by(job)\n",
+			checker:     newCardinalityCheck(0, 50),
+			prometheus:  newSimpleProm,
+			problems:    true,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 3)},
+					},
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nbar\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+		{
+			description: "shrinking is fine",
+			content:     "- record: foo\n  expr: sum(foo) by(job)\n",
+			before:      "- record: foo\n  expr: sum(foo) by(job, cluster)\n",
+			checker:     newCardinalityCheck(0, 10),
+			prometheus:  newSimpleProm,
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{requireTSDBPath},
+					resp:  tsdbStats,
+				},
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: "count(\nfoo\n)"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSampleWithValue(map[string]string{}, 2000)},
+					},
+				},
+			},
+		},
+	}
+
+	runTests(t, testCases)
+}
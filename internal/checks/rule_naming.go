@@ -0,0 +1,409 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/source"
+
+	"github.com/prometheus/common/model"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	RuleNamingCheckName    = "rule/naming"
+	RuleNamingCheckDetails = "Recording rule names should follow the `level:metric:operations` convention.\n" +
+		"`level` is the aggregation level and labels of the rule output, `metric` is the name of the queried metric " +
+		"and `operations` is a list of operations that were applied to the metric, newest operation first.\n" +
+		"See [Prometheus documentation](https://prometheus.io/docs/practices/rules/#naming-and-aggregation) for details."
+)
+
+var (
+	namingOperationRe = regexp.MustCompile(`^([a-z]+?)(\d+[smhdwy])?$`)
+
+	namingRangeFuncs = []string{
+		"rate", "irate", "increase", "delta", "idelta", "deriv", "changes", "resets",
+	}
+	namingAggregations = []string{
+		"sum", "avg", "min", "max", "count", "stddev", "stdvar", "group", "topk", "bottomk", "quantile",
+	}
+)
+
+func NewRuleNamingCheck(comment string, severity Severity) RuleNamingCheck {
+	return RuleNamingCheck{
+		comment:  comment,
+		severity: severity,
+	}
+}
+
+type RuleNamingCheck struct {
+	comment  string
+	severity Severity
+}
+
+func (c RuleNamingCheck) Meta() CheckMeta {
+	return CheckMeta{
+		States: []discovery.ChangeType{
+			discovery.Noop,
+			discovery.Added,
+			discovery.Modified,
+			discovery.Moved,
+		},
+		Online:        false,
+		AlwaysEnabled: false,
+	}
+}
+
+func (c RuleNamingCheck) String() string {
+	return RuleNamingCheckName
+}
+
+func (c RuleNamingCheck) Reporter() string {
+	return RuleNamingCheckName
+}
+
+func (c RuleNamingCheck) Check(_ context.Context, entry *discovery.Entry, _ []*discovery.Entry) (problems []Problem) {
+	if entry.Rule.RecordingRule == nil {
+		return problems
+	}
+
+	expr := entry.Rule.RecordingRule.Expr
+	if expr.SyntaxError() != nil || expr.IsLogQL() {
+		return problems
+	}
+
+	name := recordingRuleName(entry.Rule.RecordingRule.Record.Value)
+	if name == nil {
+		return problems
+	}
+
+	if dgs := c.checkLevel(entry.Rule, name); len(dgs) > 0 {
+		problems = append(problems, c.newProblem(entry.Rule, "recording rule level doesn't match aggregation", dgs))
+	}
+	if dgs := c.checkMetric(entry.Rule, name); len(dgs) > 0 {
+		problems = append(problems, c.newProblem(entry.Rule, "recording rule metric doesn't match query", dgs))
+	}
+	if dgs := c.checkOperations(entry.Rule, name); len(dgs) > 0 {
+		problems = append(problems, c.newProblem(entry.Rule, "recording rule operations don't match query", dgs))
+	}
+
+	return problems
+}
+
+func (c RuleNamingCheck) newProblem(rule parser.Rule, summary string, dgs []diags.Diagnostic) Problem {
+	details := RuleNamingCheckDetails
+	if c.comment != "" {
+		details = fmt.Sprintf("%s\n%s", details, maybeComment(c.comment))
+	}
+	return Problem{
+		Anchor:      AnchorAfter,
+		Lines:       rule.RecordingRule.Record.Pos.Lines(),
+		Reporter:    c.Reporter(),
+		Summary:     summary,
+		Details:     details,
+		Severity:    c.severity,
+		Diagnostics: dgs,
+	}
+}
+
+// checkLevel verifies that level is made of all labels used in by(...) of the query.
+func (c RuleNamingCheck) checkLevel(rule parser.Rule, name *recordingName) (dgs []diags.Diagnostic) {
+	var required []string
+	for _, src := range rule.RecordingRule.Expr.Source() {
+		// We can only tell which labels are present on results if they are
+		// limited by by(...) or similar.
+		if !src.FixedLabels {
+			return nil
+		}
+		for _, l := range src.TransformedLabels(source.PossibleLabel, source.GuaranteedLabel) {
+			if !slices.Contains(required, l) {
+				required = append(required, l)
+			}
+		}
+	}
+	// Queries that remove all labels will usually be named using some external label, like cluster.
+	if len(required) == 0 {
+		return nil
+	}
+	slices.Sort(required)
+
+	allowed := slices.Clone(required)
+	if rule.RecordingRule.Labels != nil {
+		for _, l := range rule.RecordingRule.Labels.Items {
+			allowed = append(allowed, l.Key.Value)
+		}
+	}
+
+	if matchLevel(name.level, required, allowed, map[string]bool{}) {
+		return nil
+	}
+
+	return append(dgs, diags.Diagnostic{
+		Message: fmt.Sprintf("Recording rule name uses `%s` as the aggregation level but the query will only keep %s labels, expected level is `%s`.",
+			name.level, joinLabels(required), strings.Join(required, "_")),
+		Pos:         rule.RecordingRule.Record.Pos,
+		Expr:        nil,
+		FirstColumn: 1,
+		LastColumn:  len(name.level),
+		Kind:        diags.Issue,
+	})
+}
+
+// checkMetric verifies that metric is the name of one of the queried metrics.
+func (c RuleNamingCheck) checkMetric(rule parser.Rule, name *recordingName) (dgs []diags.Diagnostic) {
+	var metrics []string
+	for _, node := range parser.WalkDownExpr[*promParser.VectorSelector](rule.RecordingRule.Expr.Query()) {
+		vs := node.Expr.(*promParser.VectorSelector)
+		// We don't know the name of the metric, so we can't compare it.
+		if vs.Name == "" {
+			return nil
+		}
+		metrics = append(metrics, vs.Name)
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	for _, metric := range metrics {
+		for _, candidate := range namingMetricCandidates(metric) {
+			if strings.Contains(name.metric, candidate) || strings.Contains(candidate, name.metric) {
+				return nil
+			}
+		}
+	}
+
+	slices.Sort(metrics)
+	return append(dgs, diags.Diagnostic{
+		Message: fmt.Sprintf("Recording rule name uses `%s` as the metric name but the query is using %s.",
+			name.metric, joinLabels(slices.Compact(metrics))),
+		Pos:         rule.RecordingRule.Record.Pos,
+		Expr:        nil,
+		FirstColumn: len(name.level) + 2,
+		LastColumn:  len(name.level) + 1 + len(name.metric),
+		Kind:        diags.Issue,
+	})
+}
+
+// checkOperations verifies that all operations are used in the query, and that
+// range functions used in the query are listed in operations.
+func (c RuleNamingCheck) checkOperations(rule parser.Rule, name *recordingName) (dgs []diags.Diagnostic) {
+	funcs := map[string][]time.Duration{}
+	for _, node := range parser.WalkDownExpr[*promParser.Call](rule.RecordingRule.Expr.Query()) {
+		call := node.Expr.(*promParser.Call)
+		if !slices.Contains(namingRangeFuncs, call.Func.Name) {
+			continue
+		}
+		var window time.Duration
+		for _, arg := range call.Args {
+			switch n := arg.(type) {
+			case *promParser.MatrixSelector:
+				window = n.Range
+			case *promParser.SubqueryExpr:
+				window = n.Range
+			}
+		}
+		funcs[call.Func.Name] = append(funcs[call.Func.Name], window)
+	}
+
+	var aggrs []string
+	for _, node := range parser.WalkDownExpr[*promParser.AggregateExpr](rule.RecordingRule.Expr.Query()) {
+		aggrs = append(aggrs, node.Expr.(*promParser.AggregateExpr).Op.String())
+	}
+
+	var hasDivision bool
+	for _, node := range parser.WalkDownExpr[*promParser.BinaryExpr](rule.RecordingRule.Expr.Query()) {
+		if node.Expr.(*promParser.BinaryExpr).Op == promParser.DIV {
+			hasDivision = true
+		}
+	}
+
+	// Queries using other recording rules inherit all operations from them.
+	for _, node := range parser.WalkDownExpr[*promParser.VectorSelector](rule.RecordingRule.Expr.Query()) {
+		src := recordingRuleName(node.Expr.(*promParser.VectorSelector).Name)
+		if src == nil {
+			continue
+		}
+		for op := range strings.SplitSeq(src.operations, "_") {
+			parts := namingOperationRe.FindStringSubmatch(op)
+			if parts == nil {
+				continue
+			}
+			switch fn, window := parts[1], parts[2]; {
+			case fn == "ratio":
+				hasDivision = true
+			case slices.Contains(namingAggregations, fn):
+				aggrs = append(aggrs, fn)
+			case slices.Contains(namingRangeFuncs, fn):
+				dur, _ := model.ParseDuration(window)
+				funcs[fn] = append(funcs[fn], time.Duration(dur))
+			}
+		}
+	}
+
+	firstColumn := len(name.level) + len(name.metric) + 3
+	lastColumn := firstColumn + len(name.operations) - 1
+	issue := func(msg string) {
+		dgs = append(dgs, diags.Diagnostic{
+			Message:     msg,
+			Pos:         rule.RecordingRule.Record.Pos,
+			Expr:        nil,
+			FirstColumn: firstColumn,
+			LastColumn:  lastColumn,
+			Kind:        diags.Issue,
+		})
+	}
+
+	allKnown := true
+	named := map[string]bool{}
+	for op := range strings.SplitSeq(name.operations, "_") {
+		parts := namingOperationRe.FindStringSubmatch(op)
+		if parts == nil {
+			allKnown = false
+			continue
+		}
+		fn, window := parts[1], parts[2]
+		switch {
+		case fn == "ratio" && window == "":
+			if !hasDivision {
+				issue(fmt.Sprintf("Recording rule name has `%s` operation but the query doesn't use division.", op))
+			}
+		case slices.Contains(namingAggregations, fn) && window == "":
+			if !slices.Contains(aggrs, fn) {
+				issue(fmt.Sprintf("Recording rule name has `%s` operation but the query doesn't use `%s()`.", op, fn))
+			}
+		case slices.Contains(namingRangeFuncs, fn):
+			named[fn] = true
+			windows, ok := funcs[fn]
+			if !ok {
+				issue(fmt.Sprintf("Recording rule name has `%s` operation but the query doesn't use `%s()`.", op, fn))
+				continue
+			}
+			if window == "" {
+				continue
+			}
+			dur, err := model.ParseDuration(window)
+			if err != nil {
+				continue
+			}
+			if !slices.Contains(windows, time.Duration(dur)) {
+				issue(fmt.Sprintf("Recording rule name has `%s` operation but the query is using `%s()` with %s time window.",
+					op, fn, joinDurations(windows)))
+			}
+		default:
+			allKnown = false
+		}
+	}
+
+	// Operations we don't understand might be using range functions, like meanNm.
+	if !allKnown {
+		return dgs
+	}
+
+	fns := make([]string, 0, len(funcs))
+	for fn := range funcs {
+		fns = append(fns, fn)
+	}
+	slices.Sort(fns)
+	for _, fn := range fns {
+		if !named[fn] {
+			issue(fmt.Sprintf("The query is using `%s()` but it's not listed in recording rule name operations.", fn))
+		}
+	}
+
+	return dgs
+}
+
+type recordingName struct {
+	level      string
+	metric     string
+	operations string
+}
+
+// recordingRuleName parses level:metric:operations name,
+// it returns nil for any name that doesn't follow that format.
+func recordingRuleName(name string) *recordingName {
+	parts := strings.Split(name, ":")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return nil
+	}
+	return &recordingName{level: parts[0], metric: parts[1], operations: parts[2]}
+}
+
+// matchLevel returns true if level is made of all required labels and optionally
+// some of the allowed labels, joined with underscores.
+func matchLevel(level string, required, allowed []string, used map[string]bool) bool {
+	if level == "" {
+		for _, name := range required {
+			if !used[name] {
+				return false
+			}
+		}
+		return true
+	}
+	for _, name := range allowed {
+		if used[name] || !strings.HasPrefix(level, name) {
+			continue
+		}
+		rest := level[len(name):]
+		if rest != "" {
+			if rest[0] != '_' {
+				continue
+			}
+			rest = rest[1:]
+			if rest == "" {
+				continue
+			}
+		}
+		used[name] = true
+		if matchLevel(rest, required, allowed, used) {
+			return true
+		}
+		used[name] = false
+	}
+	return false
+}
+
+// namingMetricCandidates returns all names that can be used as the metric part
+// of a recording rule that queries given metric.
+func namingMetricCandidates(metric string) []string {
+	if rn := recordingRuleName(metric); rn != nil {
+		metric = rn.metric
+	}
+	candidates := []string{metric}
+	if s, ok := strings.CutSuffix(metric, "_total"); ok {
+		candidates = append(candidates, s)
+	}
+	return candidates
+}
+
+func joinLabels(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, "`"+name+"`")
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func joinDurations(durations []time.Duration) string {
+	s := make([]string, 0, len(durations))
+	for _, d := range durations {
+		if d == 0 {
+			continue
+		}
+		s = append(s, "`"+output.HumanizeDuration(d)+"`")
+	}
+	slices.Sort(s)
+	s = slices.Compact(s)
+	if len(s) == 0 {
+		return "no"
+	}
+	return strings.Join(s, ", ")
+}
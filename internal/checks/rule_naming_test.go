@@ -0,0 +1,187 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newRuleNamingCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewRuleNamingCheck("", checks.Warning)
+}
+
+func TestRuleNamingCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores alerting rules",
+			content:     "- alert: foo\n  expr: sum(foo) by(job) > 0\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: job:foo:sum\n  expr: sum(foo) by(\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description:   "ignores LogQL queries",
+			content:       "- record: app:errors:rate5m\n  expr: sum by (app) (rate({job=\"app\"} | json [5m]))\n",
+			checker:       newRuleNamingCheck,
+			prometheus:    noProm,
+			contentSchema: parser.LokiSchema,
+		},
+		{
+			description: "ignores names without level:metric:operations",
+			content:     "- record: foo:sum\n  expr: sum(foo) by(instance)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "ignores names with empty parts",
+			content:     "- record: :foo:sum\n  expr: sum(foo) by(instance)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "valid name",
+			content:     "- record: job:foo:sum\n  expr: sum(foo) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "valid name with rate",
+			content:     "- record: instance_path:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by(instance, path)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "valid name with labels in different order",
+			content:     "- record: path_instance:requests:rate5m\n  expr: sum(rate(requests_total[5m])) by(instance, path)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "valid name with ratio",
+			content:     "- record: path:request_failures_per_requests:ratio_rate5m\n  expr: |\n    sum(rate(request_failures_total[5m])) by(path)\n    /\n    sum(rate(requests_total[5m])) by(path)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "valid name using another recording rule",
+			content:     "- record: job:requests:rate5m\n  expr: sum(instance_path:requests:rate5m) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "valid name with static label in level",
+			content:     "- record: cluster_job:foo:sum\n  expr: sum(foo) by(job)\n  labels:\n    cluster: dev\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "level with label underscores",
+			content:     "- record: job_instance_name:foo:sum\n  expr: sum(foo) by(job, instance_name)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "level without by()",
+			content:     "- record: cluster:foo:sum\n  expr: sum(foo)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "level with without()",
+			content:     "- record: job:foo:sum\n  expr: sum(foo) without(instance)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "level missing a label",
+			content:     "- record: job:foo:sum\n  expr: sum(foo) by(job, instance)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "level with extra label",
+			content:     "- record: job_instance:foo:sum\n  expr: sum(foo) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "metric doesn't match",
+			content:     "- record: job:bar:sum\n  expr: sum(foo) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "metric with regexp name",
+			content:     "- record: job:bar:sum\n  expr: sum({__name__=~\"foo|bar\"}) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "operations rate window mismatch",
+			content:     "- record: job:requests:rate5m\n  expr: sum(rate(requests_total[1m])) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "operations function mismatch",
+			content:     "- record: job:requests:rate5m\n  expr: sum(increase(requests_total[5m])) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "operations aggregation mismatch",
+			content:     "- record: job:foo:max\n  expr: sum(foo) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "operations ratio without division",
+			content:     "- record: job:foo:ratio\n  expr: sum(foo) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "operations missing range function",
+			content:     "- record: job:requests:sum\n  expr: sum(rate(requests_total[5m])) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+			problems:    true,
+		},
+		{
+			description: "operations with unknown operation",
+			content:     "- record: job:request_latency_seconds:mean5m\n  expr: |\n    sum(rate(request_latency_seconds_sum[5m])) by(job)\n    /\n    sum(rate(request_latency_seconds_count[5m])) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "operations with subquery",
+			content:     "- record: job:foo:deriv1h\n  expr: sum(deriv(foo[1h:5m])) by(job)\n",
+			checker:     newRuleNamingCheck,
+			prometheus:  noProm,
+		},
+		{
+			description: "custom comment and severity",
+			content:     "- record: job:bar:sum\n  expr: sum(foo) by(job)\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewRuleNamingCheck("some text", checks.Bug)
+			},
+			prometheus: noProm,
+			problems:   true,
+		},
+	}
+	runTests(t, testCases)
+}
@@ -0,0 +1,90 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/cloudflare/pint/internal/promapi"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+// scrapeInterval is the scrape interval used for series matching some selector.
+type scrapeInterval struct {
+	// Name of the scrape job, empty if this is the global scrape_interval.
+	job      string
+	interval time.Duration
+}
+
+func (si scrapeInterval) String() string {
+	if si.job == "" {
+		return "scrape_interval"
+	}
+	return fmt.Sprintf("scrape_interval for the `%s` job", si.job)
+}
+
+// resolveScrapeInterval returns the scrape interval for series matching given selector.
+// If the selector has a job="..." matcher then the interval of that scrape job is used.
+// If there's no job matcher and some scrape jobs are using a custom scrape_interval
+// then we'll query Prometheus for job labels on all matching series.
+// When series from more than one job are matched then the longest interval is returned.
+// It falls back to the global scrape_interval if no job can be found.
+func resolveScrapeInterval(ctx context.Context, prom *promapi.FailoverGroup, cfg *promapi.ConfigResult, vs *promParser.VectorSelector) scrapeInterval {
+	global := scrapeInterval{job: "", interval: cfg.Config.Global.ScrapeInterval}
+
+	if job, ok := selectorJob(vs); ok {
+		if sc, ok := cfg.Config.ScrapeJob(job); ok {
+			return scrapeInterval{job: sc.JobName, interval: sc.ScrapeInterval}
+		}
+		return global
+	}
+
+	if prom == nil || !cfg.Config.HasCustomScrapeIntervals() {
+		return global
+	}
+
+	sel := &promParser.VectorSelector{ // nolint: exhaustruct
+		Name:          vs.Name,
+		LabelMatchers: vs.LabelMatchers,
+	}
+	qr, err := prom.Query(ctx, fmt.Sprintf("count(%s) by (job)", sel)).Wait()
+	if err != nil {
+		slog.LogAttrs(ctx, slog.LevelWarn, "Cannot resolve scrape jobs for selector",
+			slog.String("selector", sel.String()), slog.Any("err", err), slog.String("name", prom.Name()))
+		return global
+	}
+
+	jobs := make([]string, 0, len(qr.Series))
+	for _, s := range qr.Series {
+		jobs = append(jobs, s.Labels.Get("job"))
+	}
+	slices.Sort(jobs)
+
+	si := global
+	var found bool
+	for _, job := range slices.Compact(jobs) {
+		sc, ok := cfg.Config.ScrapeJob(job)
+		if !ok {
+			continue
+		}
+		if !found || sc.ScrapeInterval > si.interval {
+			si = scrapeInterval{job: sc.JobName, interval: sc.ScrapeInterval}
+			found = true
+		}
+	}
+	return si
+}
+
+// selectorJob returns the value of the job label if selector uses job="..." matcher.
+func selectorJob(vs *promParser.VectorSelector) (string, bool) {
+	for _, lm := range vs.LabelMatchers {
+		if lm.Name == "job" && lm.Type == labels.MatchEqual {
+			return lm.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type CardinalitySettings struct {
+	Comment   string `hcl:"comment,optional" json:"comment,omitempty"`
+	Severity  string `hcl:"severity,optional" json:"severity,omitempty"`
+	MaxSeries int    `hcl:"maxSeries,optional" json:"maxSeries,omitempty"`
+	MaxGrowth int    `hcl:"maxGrowth,optional" json:"maxGrowth,omitempty"`
+}
+
+func (cs CardinalitySettings) validate() error {
+	if cs.Severity != "" {
+		if _, err := checks.ParseSeverity(cs.Severity); err != nil {
+			return err
+		}
+	}
+	if cs.MaxSeries < 0 {
+		return errors.New("maxSeries value must be >= 0")
+	}
+	if cs.MaxGrowth < 0 {
+		return errors.New("maxGrowth value must be >= 0")
+	}
+	if cs.MaxSeries == 0 && cs.MaxGrowth == 0 {
+		return errors.New("maxSeries or maxGrowth must be set")
+	}
+	return nil
+}
+
+func (cs CardinalitySettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if cs.Severity != "" {
+		sev, _ := checks.ParseSeverity(cs.Severity)
+		return sev
+	}
+	return fallback
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalitySettings(t *testing.T) {
+	type testCaseT struct {
+		err  error
+		conf CardinalitySettings
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: CardinalitySettings{},
+			err:  errors.New("maxSeries or maxGrowth must be set"),
+		},
+		{
+			conf: CardinalitySettings{
+				MaxGrowth: 10,
+			},
+		},
+		{
+			conf: CardinalitySettings{
+				MaxSeries: -1,
+				Severity:  "bug",
+			},
+			err: errors.New("maxSeries value must be >= 0"),
+		},
+		{
+			conf: CardinalitySettings{
+				MaxGrowth: -1,
+			},
+			err: errors.New("maxGrowth value must be >= 0"),
+		},
+		{
+			conf: CardinalitySettings{
+				Severity:  "foo",
+				MaxSeries: 1000,
+			},
+			err: errors.New("unknown severity: foo"),
+		},
+		{
+			conf: CardinalitySettings{
+				MaxSeries: 1000,
+				MaxGrowth: 50,
+				Severity:  "warning",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				require.Equal(t, err, tc.err)
+			} else {
+				require.EqualError(t, err, tc.err.Error())
+			}
+		})
+	}
+}
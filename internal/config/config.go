@@ -37,15 +37,17 @@ type Config struct {
 	Rules      []Rule             `hcl:"rule,block" json:"rules,omitempty"`
 
 	staticRules []staticRule
+	offline     bool
 }
 
 func (cfg *Config) DisableOnlineChecks() {
+	cfg.offline = true
 	for _, name := range checks.OnlineChecks {
-		var found bool
-		if slices.Contains(cfg.Checks.Disabled, name) {
-			found = true
+		// These checks run offline too, only instances that query Prometheus are skipped.
+		if name == checks.GroupQueryOffsetCheckName || name == checks.SubqueryCheckName {
+			continue
 		}
-		if !found {
+		if !slices.Contains(cfg.Checks.Disabled, name) {
 			cfg.Checks.Disabled = append(cfg.Checks.Disabled, name)
 		}
 	}
@@ -87,6 +89,7 @@ func (cfg *Config) GetChecksForEntry(ctx context.Context, gen *PrometheusGenerat
 	defaultMatch := []Match{{State: defaultStates}}
 	proms := gen.ServersForPath(entry.Path.Name)
 
+	var isLogQL bool
 	parsedRules := make([]*parsedRule, 0, len(cfg.Rules))
 	if entry.PathError != nil || entry.Rule.Error.Err != nil {
 		check := checks.NewErrorCheck(entry)
@@ -96,8 +99,15 @@ func (cfg *Config) GetChecksForEntry(ctx context.Context, gen *PrometheusGenerat
 		for _, rule := range cfg.Rules {
 			parsedRules = append(parsedRules, parseRule(rule, proms, defaultStates)...)
 		}
+		isLogQL = entry.Rule.Expr().IsLogQL()
 	}
 	for _, pr := range parsedRules {
+		if isLogQL && !slices.Contains(checks.LogQLChecks, pr.check.Reporter()) {
+			continue
+		}
+		if cfg.offline && pr.check.Meta().Online {
+			continue
+		}
 		if !isMatch(ctx, entry, pr.ignore, pr.match) {
 			continue
 		}
@@ -235,8 +245,11 @@ func Load(path string, failOnMissing bool) (cfg Config, fromFile bool, err error
 		staticRule{name: checks.RegexpCheckName, checker: checks.NewRegexpCheck()},
 		staticRule{name: checks.RuleDependencyCheckName, checker: checks.NewRuleDependencyCheck()},
 		staticRule{name: checks.ImpossibleCheckName, checker: checks.NewImpossibleCheck()},
+		staticRule{name: checks.HistogramCheckName, checker: checks.NewHistogramCheck()},
 		staticRule{name: checks.NaNCheckName, checker: checks.NewNaNCheck()},
-		staticRule{name: checks.GroupIntervalCheckName, checker: checks.NewGroupIntervalCheck()},
+		staticRule{name: checks.GroupIntervalCheckName, checker: checks.NewGroupIntervalCheck(nil)},
+		staticRule{name: checks.GroupQueryOffsetCheckName, checker: checks.NewGroupQueryOffsetCheck(nil)},
+		staticRule{name: checks.SubqueryCheckName, checker: checks.NewSubqueryCheck(nil)},
 	)
 
 	return cfg, fromFile, nil
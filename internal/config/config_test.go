@@ -102,8 +102,71 @@ prometheus "prom" {
 
 	cfg.DisableOnlineChecks()
 	for _, c := range checks.OnlineChecks {
+		if c == checks.GroupQueryOffsetCheckName || c == checks.SubqueryCheckName {
+			continue
+		}
 		require.Contains(t, cfg.Checks.Disabled, c)
 	}
+	require.NotContains(t, cfg.Checks.Disabled, checks.SubqueryCheckName)
+	require.NotContains(t, cfg.Checks.Disabled, checks.GroupQueryOffsetCheckName)
+
+	entry := &discovery.Entry{
+		State: discovery.Modified,
+		Path: discovery.Path{
+			Name:          "rules.yml",
+			SymlinkTarget: "rules.yml",
+		},
+		Rule: newRule(t, "- alert: foo\n  expr: sum(foo) > 0\n"),
+	}
+	var names []string
+	for _, c := range cfg.GetChecksForEntry(t.Context(), gen, entry) {
+		require.False(t, c.Meta().Online, c.String())
+		names = append(names, c.String())
+	}
+	require.Contains(t, names, checks.GroupQueryOffsetCheckName)
+	require.NotContains(t, names, checks.GroupQueryOffsetCheckName+"(prom)")
+	require.Contains(t, names, checks.SubqueryCheckName)
+	require.NotContains(t, names, checks.SubqueryCheckName+"(prom)")
+}
+
+func TestDisableOnlineChecksSubquery(t *testing.T) {
+	dir := t.TempDir()
+	path := path.Join(dir, "config.hcl")
+	err := os.WriteFile(path, []byte(`
+prometheus "prom" {
+  uri     = "http://localhost"
+}
+`), 0o644)
+	require.NoError(t, err)
+
+	cfg, _, err := config.Load(path, true)
+	require.NoError(t, err)
+
+	gen := config.NewPrometheusGenerator(cfg, prometheus.NewRegistry())
+	defer gen.Stop()
+	gen.GenerateStatic()
+
+	entry := &discovery.Entry{
+		State: discovery.Modified,
+		Path: discovery.Path{
+			Name:          "rules.yml",
+			SymlinkTarget: "rules.yml",
+		},
+		Rule: newRule(t, "- record: foo\n  expr: max_over_time(rate(foo[5m])[1h:1m])\n"),
+	}
+	checkNames := func() (names []string) {
+		for _, c := range cfg.GetChecksForEntry(t.Context(), gen, entry) {
+			names = append(names, c.String())
+		}
+		return names
+	}
+
+	require.Contains(t, checkNames(), checks.SubqueryCheckName)
+	require.Contains(t, checkNames(), checks.SubqueryCheckName+"(prom)")
+
+	cfg.DisableOnlineChecks()
+	require.Contains(t, checkNames(), checks.SubqueryCheckName)
+	require.NotContains(t, checkNames(), checks.SubqueryCheckName+"(prom)")
 }
 
 func TestDisableOnlineChecksWithoutPrometheus(t *testing.T) {
@@ -123,6 +186,9 @@ func TestDisableOnlineChecksWithoutPrometheus(t *testing.T) {
 
 	cfg.DisableOnlineChecks()
 	for _, c := range checks.OnlineChecks {
+		if c == checks.GroupQueryOffsetCheckName || c == checks.SubqueryCheckName {
+			continue
+		}
 		require.Contains(t, cfg.Checks.Disabled, c)
 	}
 }
@@ -155,6 +221,9 @@ prometheus "prom" {
 
 	cfg.DisableOnlineChecks()
 	for _, c := range checks.OnlineChecks {
+		if c == checks.GroupQueryOffsetCheckName || c == checks.SubqueryCheckName {
+			continue
+		}
 		require.Contains(t, cfg.Checks.Disabled, c)
 	}
 }
@@ -181,7 +250,13 @@ func TestSetDisabledChecks(t *testing.T) {
 }
 
 func newRule(t *testing.T, content string) parser.Rule {
-	p := parser.NewParser(parser.DefaultOptions)
+	return newRuleWithSchema(t, content, parser.PrometheusSchema)
+}
+
+func newRuleWithSchema(t *testing.T, content string, schema parser.Schema) parser.Rule {
+	opts := parser.DefaultOptions
+	opts.Schema = schema
+	p := parser.NewParser(opts)
 	file := p.Parse(strings.NewReader(content))
 	if file.Error.Err != nil {
 		t.Error(file.Error)
@@ -1819,6 +1894,286 @@ rule {
 				Rule: newRule(t, "- record: foo\n  expr: sum(foo)\n"),
 			},
 		},
+		{
+			title: "loki rule",
+			config: `
+prometheus "loki" {
+  uri     = "http://localhost/loki"
+  timeout = "1s"
+}
+rule {
+  name "total:.+" {}
+  label "team" {
+    required = true
+  }
+  cost {}
+  alerts {
+    range   = "1d"
+    step    = "1m"
+    resolve = "5m"
+  }
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRuleWithSchema(t, "- alert: foo\n  expr: sum(rate({app=\"foo\"}[5m])) > 0\n", parser.LokiSchema),
+			},
+		},
+		{
+			title: "rule with kubernetes match / no object",
+			config: `
+rule {
+  match {
+    kubernetes {
+      name      = "team-.+"
+      namespace = "monitoring"
+      label "team" {
+        value = "sre"
+      }
+    }
+  }
+  label "team" {
+    required = true
+  }
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+			},
+		},
+		{
+			title: "rule with kubernetes match / label mismatch",
+			config: `
+rule {
+  match {
+    kubernetes {
+      name      = "team-.+"
+      namespace = "monitoring"
+      label "team" {
+        value = "sre"
+      }
+    }
+  }
+  label "team" {
+    required = true
+  }
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+				Kubernetes: &parser.KubernetesObject{
+					Name:      parser.YamlNode{Value: "team-rules"},
+					Namespace: &parser.YamlNode{Value: "monitoring"},
+					Labels: &parser.YamlMap{
+						Items: []*parser.YamlKeyValue{
+							{
+								Key:   &parser.YamlNode{Value: "team"},
+								Value: &parser.YamlNode{Value: "dev"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			title: "rule with kubernetes match / match",
+			config: `
+rule {
+  match {
+    kubernetes {
+      name      = "team-.+"
+      namespace = "monitoring"
+      label "team" {
+        value = "sre"
+      }
+    }
+  }
+  label "team" {
+    required = true
+  }
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- record: foo\n  expr: sum(foo)\n"),
+				Kubernetes: &parser.KubernetesObject{
+					Name:      parser.YamlNode{Value: "team-rules"},
+					Namespace: &parser.YamlNode{Value: "monitoring"},
+					Labels: &parser.YamlMap{
+						Items: []*parser.YamlKeyValue{
+							{
+								Key:   &parser.YamlNode{Value: "team"},
+								Value: &parser.YamlNode{Value: "sre"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			title: "flapping check / unmodified rule",
+			config: `
+prometheus "prom1" {
+  uri     = "http://localhost"
+  timeout = "1s"
+}
+rule {
+  flapping {
+    range    = "7d"
+    maxFlaps = 3
+  }
+}
+checks {
+  enabled = ["alerts/flapping"]
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Noop,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- alert: foo\n  expr: sum(foo) > 0\n"),
+			},
+		},
+		{
+			title: "flapping check / modified rule",
+			config: `
+prometheus "prom1" {
+  uri     = "http://localhost"
+  timeout = "1s"
+}
+rule {
+  flapping {
+    maxFlaps = 3
+  }
+}
+checks {
+  enabled = ["alerts/flapping"]
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- alert: foo\n  expr: sum(foo) > 0\n"),
+			},
+		},
+		{
+			title: "naming check",
+			config: `
+rule {
+  naming {
+    comment  = "follow naming conventions"
+    severity = "bug"
+  }
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- record: job:foo:sum\n  expr: sum(foo) by(job)\n"),
+			},
+		},
+		{
+			title: "threshold check",
+			config: `
+prometheus "prom1" {
+  uri     = "http://localhost"
+  timeout = "1s"
+}
+rule {
+  threshold {
+    range  = "7d"
+    factor = 100
+  }
+}
+checks {
+  enabled = ["alerts/threshold"]
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- alert: foo\n  expr: sum(foo) > 5000\n"),
+			},
+		},
+		{
+			title: "custom check",
+			config: `
+rule {
+  custom "no-irate" {
+    match {
+      function = "irate"
+    }
+    message  = "Don't use irate() in alerts."
+    severity = "bug"
+  }
+  custom "quantile-by-cluster" {
+    match {
+      function = "histogram_quantile"
+    }
+    require {
+      by = ["cluster"]
+    }
+    message = "histogram_quantile() must aggregate by cluster."
+  }
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- alert: foo\n  expr: irate(foo[5m]) > 0\n"),
+			},
+		},
+		{
+			title: "division check",
+			config: `
+rule {
+  division {
+    comment  = "guard against zero divisors"
+    severity = "info"
+  }
+}
+`,
+			entry: &discovery.Entry{
+				State: discovery.Modified,
+				Path: discovery.Path{
+					Name:          "rules.yml",
+					SymlinkTarget: "rules.yml",
+				},
+				Rule: newRule(t, "- record: foo\n  expr: sum(rate(errors_total[5m])) / sum(rate(requests_total[5m]))\n"),
+			},
+		},
 	}
 
 	dir := t.TempDir()
@@ -2023,6 +2378,44 @@ func TestConfigErrors(t *testing.T) {
 		},
 		{
 			config: `rule {
+  match {
+    kubernetes {
+      name = ".+++"
+    }
+  }
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `rule {
+  match {
+    kubernetes {
+      namespace = ".+++"
+    }
+  }
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `rule {
+  match {
+    kubernetes {
+      label "foo" {
+        value = ".+++"
+      }
+    }
+  }
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `parser {
+  kubernetes = [".+++"]
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `rule {
   ignore {
     name = ".+++"
   }
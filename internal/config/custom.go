@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type CustomSettings struct {
+	Match    *CustomPatternSettings `hcl:"match,block" json:"match,omitempty"`
+	Require  *CustomPatternSettings `hcl:"require,block" json:"require,omitempty"`
+	Name     string                 `hcl:",label" json:"name"`
+	Message  string                 `hcl:"message" json:"message"`
+	Comment  string                 `hcl:"comment,optional" json:"comment,omitempty"`
+	Severity string                 `hcl:"severity,optional" json:"severity,omitempty"`
+}
+
+func (cs CustomSettings) validate() error {
+	if cs.Name == "" {
+		return errors.New("custom check name cannot be empty")
+	}
+
+	if cs.Message == "" {
+		return fmt.Errorf("custom check %q must have a message", cs.Name)
+	}
+
+	if cs.Match == nil {
+		return fmt.Errorf("custom check %q must have a match block", cs.Name)
+	}
+
+	if cs.Match.Function == "" && cs.Match.Aggregation == "" && cs.Match.Selector == "" {
+		return fmt.Errorf("custom check %q match block must set function, aggregation or selector", cs.Name)
+	}
+
+	if cs.Match.nodeTypes() > 1 {
+		return fmt.Errorf("custom check %q match block can only set one of function, aggregation or selector", cs.Name)
+	}
+
+	if err := cs.Match.validate(); err != nil {
+		return err
+	}
+
+	if cs.Require != nil {
+		if cs.Require.nodeTypes() > 1 {
+			return fmt.Errorf("custom check %q require block can only set one of function, aggregation or selector", cs.Name)
+		}
+		if err := cs.Require.validate(); err != nil {
+			return err
+		}
+	}
+
+	if cs.Severity != "" {
+		if _, err := checks.ParseSeverity(cs.Severity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cs CustomSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if cs.Severity != "" {
+		sev, _ := checks.ParseSeverity(cs.Severity)
+		return sev
+	}
+	return fallback
+}
+
+type CustomPatternSettings struct {
+	Function    string   `hcl:"function,optional" json:"function,omitempty"`
+	Aggregation string   `hcl:"aggregation,optional" json:"aggregation,omitempty"`
+	Selector    string   `hcl:"selector,optional" json:"selector,omitempty"`
+	Inside      string   `hcl:"inside,optional" json:"inside,omitempty"`
+	By          []string `hcl:"by,optional" json:"by,omitempty"`
+	Matchers    []string `hcl:"matchers,optional" json:"matchers,omitempty"`
+}
+
+func (cps CustomPatternSettings) validate() error {
+	for _, re := range []string{cps.Function, cps.Aggregation, cps.Selector, cps.Inside} {
+		if re == "" {
+			continue
+		}
+		if _, err := checks.NewTemplatedRegexp(re); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeTypes returns the number of query node types this pattern selects.
+// Each query node is either a function call, an aggregation or a vector selector,
+// so a pattern selecting more than one type can never match anything.
+func (cps CustomPatternSettings) nodeTypes() (n int) {
+	for _, re := range []string{cps.Function, cps.Aggregation, cps.Selector} {
+		if re != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func (cps CustomPatternSettings) toPattern() checks.CustomPattern {
+	pattern := checks.CustomPattern{
+		Function:    nil,
+		Aggregation: nil,
+		Selector:    nil,
+		Inside:      nil,
+		By:          cps.By,
+		Matchers:    cps.Matchers,
+	}
+	if cps.Function != "" {
+		pattern.Function = checks.MustTemplatedRegexp(cps.Function)
+	}
+	if cps.Aggregation != "" {
+		pattern.Aggregation = checks.MustTemplatedRegexp(cps.Aggregation)
+	}
+	if cps.Selector != "" {
+		pattern.Selector = checks.MustTemplatedRegexp(cps.Selector)
+	}
+	if cps.Inside != "" {
+		pattern.Inside = checks.MustTemplatedRegexp(cps.Inside)
+	}
+	return pattern
+}
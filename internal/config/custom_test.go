@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomSettings(t *testing.T) {
+	type testCaseT struct {
+		err  error
+		conf CustomSettings
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: CustomSettings{
+				Name:    "no-irate",
+				Message: "Don't use irate()",
+				Match:   &CustomPatternSettings{Function: "irate"},
+			},
+		},
+		{
+			conf: CustomSettings{
+				Name:     "quantile",
+				Message:  "Must aggregate by cluster",
+				Match:    &CustomPatternSettings{Function: "histogram_quantile"},
+				Require:  &CustomPatternSettings{By: []string{"cluster"}},
+				Severity: "bug",
+			},
+		},
+		{
+			conf: CustomSettings{},
+			err:  errors.New("custom check name cannot be empty"),
+		},
+		{
+			conf: CustomSettings{
+				Name: "foo",
+			},
+			err: errors.New(`custom check "foo" must have a message`),
+		},
+		{
+			conf: CustomSettings{
+				Name:    "foo",
+				Message: "bar",
+			},
+			err: errors.New(`custom check "foo" must have a match block`),
+		},
+		{
+			conf: CustomSettings{
+				Name:    "foo",
+				Message: "bar",
+				Match:   &CustomPatternSettings{Inside: "sum"},
+			},
+			err: errors.New(`custom check "foo" match block must set function, aggregation or selector`),
+		},
+		{
+			conf: CustomSettings{
+				Name:    "foo",
+				Message: "bar",
+				Match:   &CustomPatternSettings{Function: "rate", Aggregation: "sum"},
+			},
+			err: errors.New(`custom check "foo" match block can only set one of function, aggregation or selector`),
+		},
+		{
+			conf: CustomSettings{
+				Name:    "foo",
+				Message: "bar",
+				Match:   &CustomPatternSettings{Function: "rate"},
+				Require: &CustomPatternSettings{Aggregation: "sum", Selector: "foo"},
+			},
+			err: errors.New(`custom check "foo" require block can only set one of function, aggregation or selector`),
+		},
+		{
+			conf: CustomSettings{
+				Name:    "foo",
+				Message: "bar",
+				Match:   &CustomPatternSettings{Function: ".++"},
+			},
+			err: errors.New("error parsing regexp: invalid nested repetition operator: `++`"),
+		},
+		{
+			conf: CustomSettings{
+				Name:    "foo",
+				Message: "bar",
+				Match:   &CustomPatternSettings{Function: "rate"},
+				Require: &CustomPatternSettings{Inside: ".++"},
+			},
+			err: errors.New("error parsing regexp: invalid nested repetition operator: `++`"),
+		},
+		{
+			conf: CustomSettings{
+				Name:     "foo",
+				Message:  "bar",
+				Match:    &CustomPatternSettings{Function: "rate"},
+				Severity: "xxx",
+			},
+			err: errors.New("unknown severity: xxx"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				require.Equal(t, err, tc.err)
+			} else {
+				require.EqualError(t, err, tc.err.Error())
+			}
+		})
+	}
+}
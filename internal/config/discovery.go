@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
+	"os"
 	"path/filepath"
 	"regexp"
 	"slices"
@@ -14,6 +16,8 @@ import (
 	"text/template"
 	"time"
 
+	"go.yaml.in/yaml/v3"
+
 	"github.com/cloudflare/pint/internal/parser"
 	"github.com/cloudflare/pint/internal/promapi"
 )
@@ -60,8 +64,10 @@ func isEqualFailoverGroup(a, b *promapi.FailoverGroup) bool {
 }
 
 type Discovery struct {
-	FilePath        []FilePath        `hcl:"filepath,block" json:"filepath,omitempty"`
-	PrometheusQuery []PrometheusQuery `hcl:"prometheusQuery,block" json:"prometheusQuery,omitempty"`
+	FilePath         []FilePath             `hcl:"filepath,block" json:"filepath,omitempty"`
+	PrometheusQuery  []PrometheusQuery      `hcl:"prometheusQuery,block" json:"prometheusQuery,omitempty"`
+	PrometheusConfig []PrometheusConfigFile `hcl:"prometheusConfig,block" json:"prometheusConfig,omitempty"`
+	FileSD           []FileSD               `hcl:"fileSD,block" json:"fileSD,omitempty"`
 }
 
 func (d Discovery) validate() (err error) {
@@ -75,6 +81,16 @@ func (d Discovery) validate() (err error) {
 			return err
 		}
 	}
+	for _, pc := range d.PrometheusConfig {
+		if err = pc.validate(); err != nil {
+			return err
+		}
+	}
+	for _, sd := range d.FileSD {
+		if err = sd.validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -101,6 +117,18 @@ func (d *Discovery) Discover(ctx context.Context) ([]*promapi.FailoverGroup, err
 			return nil, err
 		}
 	}
+	for _, pd := range d.PrometheusConfig {
+		servers, err = d.discover(ctx, pd, servers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, pd := range d.FileSD {
+		servers, err = d.discover(ctx, pd, servers)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return servers, nil
 }
 
@@ -134,6 +162,7 @@ type PrometheusTemplate struct {
 	Tags        []string          `hcl:"tags,optional" json:"tags,omitempty"`
 	Concurrency int               `hcl:"concurrency,optional" json:"concurrency"`
 	RateLimit   int               `hcl:"rateLimit,optional" json:"rateLimit"`
+	BatchSize   int               `hcl:"batchSize,optional" json:"batchSize,omitempty"`
 	Required    bool              `hcl:"required,optional" json:"required"`
 }
 
@@ -243,6 +272,7 @@ func (pt PrometheusTemplate) Render(data map[string]string) (*promapi.FailoverGr
 		Timeout:     pt.Timeout,
 		Concurrency: pt.Concurrency,
 		RateLimit:   pt.RateLimit,
+		BatchSize:   pt.BatchSize,
 		Uptime:      pt.Uptime,
 		Include:     include,
 		Exclude:     exclude,
@@ -264,6 +294,7 @@ func (pt PrometheusTemplate) Render(data map[string]string) (*promapi.FailoverGr
 		slog.String("timeout", prom.Timeout),
 		slog.Int("concurrency", prom.Concurrency),
 		slog.Int("rateLimit", prom.RateLimit),
+		slog.Int("batchSize", prom.BatchSize),
 		slog.String("uptime", prom.Uptime),
 		slog.Any("tags", prom.Tags),
 		slog.Bool("required", prom.Required),
@@ -408,7 +439,7 @@ func (pq PrometheusQuery) Discover(ctx context.Context) ([]*promapi.FailoverGrou
 	timeout, _ := parseDuration(pq.Timeout)
 	tls, _ := pq.TLS.toHTTPConfig()
 
-	prom := promapi.NewPrometheus("discovery", pq.URI, "", pq.Headers, timeout, 1, 100, tls)
+	prom := promapi.NewPrometheus("discovery", pq.URI, "", pq.Headers, timeout, 1, 100, 0, tls)
 	prom.StartWorkers()
 
 	slog.LogAttrs(
@@ -436,6 +467,195 @@ func (pq PrometheusQuery) Discover(ctx context.Context) ([]*promapi.FailoverGrou
 	return servers, nil
 }
 
+type PrometheusConfigFile struct {
+	Path     string               `hcl:"path" json:"path"`
+	Template []PrometheusTemplate `hcl:"template,block" json:"template,omitempty"`
+}
+
+func (pc PrometheusConfigFile) validate() (err error) {
+	if pc.Path == "" {
+		return errors.New("prometheusConfig discovery path cannot be empty")
+	}
+	for _, t := range pc.Template {
+		if err = t.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Discover reads a local Prometheus configuration file and generates Prometheus servers
+// for all rule files listed in rule_files.
+// Generated servers will use this configuration instead of querying the config API.
+// If there are no templates then a single server without any URI is generated,
+// which can be only used by checks that need Prometheus configuration.
+func (pc PrometheusConfigFile) Discover(ctx context.Context) ([]*promapi.FailoverGroup, error) {
+	slog.LogAttrs(
+		ctx, slog.LevelInfo,
+		"Finding Prometheus servers using Prometheus configuration file",
+		slog.String("path", pc.Path),
+	)
+
+	cfg, err := promapi.LoadConfig(pc.Path)
+	if err != nil {
+		return nil, fmt.Errorf("prometheusConfig discovery failed to load Prometheus configuration: %w", err)
+	}
+
+	paths, err := ruleFilePaths(pc.Path, cfg.RuleFiles)
+	if err != nil {
+		return nil, fmt.Errorf("prometheusConfig discovery failed to expand rule_files: %w", err)
+	}
+	if len(paths) == 0 {
+		slog.LogAttrs(
+			ctx, slog.LevelWarn,
+			"Prometheus configuration file doesn't reference any existing rule files",
+			slog.String("path", pc.Path),
+			slog.Any("rule_files", cfg.RuleFiles),
+		)
+		return nil, nil
+	}
+
+	include := make([]string, 0, len(paths))
+	for _, path := range paths {
+		include = append(include, regexp.QuoteMeta(path))
+	}
+
+	servers := []*promapi.FailoverGroup{}
+	if len(pc.Template) == 0 {
+		server := promapi.NewFailoverGroup(pc.Path, pc.Path, nil, false, "up", MustCompileRegexes(include...), nil, nil)
+		server.SetSource(pc.Path)
+		server.SetLocalConfig(&promapi.ConfigResult{URI: pc.Path, Path: pc.Path, Config: cfg})
+		servers = append(servers, server)
+		return servers, nil
+	}
+
+	for _, t := range pc.Template {
+		t.Include = append(slices.Clone(t.Include), include...)
+		server, err := t.Render(maps.Clone(cfg.Global.ExternalLabels))
+		if err != nil {
+			return nil, fmt.Errorf("prometheusConfig discovery failed to generate Prometheus config from a template: %w", err)
+		}
+		server.SetLocalConfig(&promapi.ConfigResult{URI: server.URI(), Path: pc.Path, Config: cfg})
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+// ruleFilePaths expands rule_files patterns, relative patterns are resolved
+// using the directory of the Prometheus configuration file, same as Prometheus does.
+func ruleFilePaths(configPath string, patterns []string) (paths []string, err error) {
+	dir := filepath.Dir(configPath)
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule_files pattern %q: %w", pattern, err)
+		}
+		for _, path := range matches {
+			if !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
+
+type FileSD struct {
+	Files    []string             `hcl:"files" json:"files"`
+	Template []PrometheusTemplate `hcl:"template,block" json:"template"`
+}
+
+func (sd FileSD) validate() (err error) {
+	if len(sd.Files) == 0 {
+		return errors.New("fileSD discovery requires at least one file")
+	}
+	for _, pattern := range sd.Files {
+		if _, err = filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid fileSD pattern %q: %w", pattern, err)
+		}
+	}
+	if len(sd.Template) == 0 {
+		return errors.New("fileSD discovery requires at least one template")
+	}
+	for _, t := range sd.Template {
+		if err = t.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileSDTargetGroup is a single target group as used by Prometheus file_sd_configs.
+type fileSDTargetGroup struct {
+	Labels  map[string]string `yaml:"labels"`
+	Targets []string          `yaml:"targets"`
+}
+
+// Discover reads all files matching configured patterns, using the same format
+// as Prometheus file_sd_configs, and renders Prometheus server configs for every target.
+// Template variables are the target group labels, plus __address__ set to the target
+// and __meta_filepath set to the path of the file it was read from.
+func (sd FileSD) Discover(ctx context.Context) ([]*promapi.FailoverGroup, error) {
+	slog.LogAttrs(
+		ctx, slog.LevelInfo,
+		"Finding Prometheus servers using file_sd files",
+		slog.Any("files", sd.Files),
+	)
+
+	servers := []*promapi.FailoverGroup{}
+	for _, pattern := range sd.Files {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("fileSD discovery failed to expand %q: %w", pattern, err)
+		}
+		for _, path := range paths {
+			groups, err := readFileSD(path)
+			if err != nil {
+				return nil, fmt.Errorf("fileSD discovery failed to read targets: %w", err)
+			}
+			for _, group := range groups {
+				for _, target := range group.Targets {
+					data := maps.Clone(group.Labels)
+					if data == nil {
+						data = map[string]string{}
+					}
+					data["__address__"] = target
+					data["__meta_filepath"] = path
+					slog.LogAttrs(
+						ctx, slog.LevelDebug,
+						"File SD target",
+						slog.String("path", path),
+						slog.Any("labels", data),
+					)
+					for _, t := range sd.Template {
+						server, err := t.Render(data)
+						if err != nil {
+							return nil, fmt.Errorf("fileSD discovery failed to generate Prometheus config from a template: %w", err)
+						}
+						servers = append(servers, server)
+					}
+				}
+			}
+		}
+	}
+	return servers, nil
+}
+
+// readFileSD parses a file_sd file, JSON is a subset of YAML so a single decoder
+// handles both formats.
+func readFileSD(path string) (groups []fileSDTargetGroup, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(content, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return groups, nil
+}
+
 func formatAliases(data map[string]string, t string) string {
 	var vars strings.Builder
 	for k := range data {
@@ -224,6 +224,109 @@ func TestDiscoveryConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			conf: Discovery{
+				PrometheusConfig: []PrometheusConfigFile{
+					{},
+				},
+			},
+			err: "prometheusConfig discovery path cannot be empty",
+		},
+		{
+			conf: Discovery{
+				PrometheusConfig: []PrometheusConfigFile{
+					{
+						Path: "prometheus.yml",
+						Template: []PrometheusTemplate{
+							{
+								Name: "foo",
+							},
+						},
+					},
+				},
+			},
+			err: "prometheus template URI cannot be empty",
+		},
+		{
+			conf: Discovery{
+				PrometheusConfig: []PrometheusConfigFile{
+					{
+						Path: "prometheus.yml",
+					},
+				},
+			},
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{
+					{
+						Template: []PrometheusTemplate{
+							{
+								Name: "foo",
+								URI:  "http://localhost",
+							},
+						},
+					},
+				},
+			},
+			err: "fileSD discovery requires at least one file",
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{
+					{
+						Files: []string{"["},
+						Template: []PrometheusTemplate{
+							{
+								Name: "foo",
+								URI:  "http://localhost",
+							},
+						},
+					},
+				},
+			},
+			err: `invalid fileSD pattern "[": syntax error in pattern`,
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{
+					{
+						Files: []string{"targets/*.json"},
+					},
+				},
+			},
+			err: "fileSD discovery requires at least one template",
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{
+					{
+						Files: []string{"targets/*.json"},
+						Template: []PrometheusTemplate{
+							{
+								Name: "foo",
+							},
+						},
+					},
+				},
+			},
+			err: "prometheus template URI cannot be empty",
+		},
+		{
+			conf: Discovery{
+				FileSD: []FileSD{
+					{
+						Files: []string{"targets/*.json"},
+						Template: []PrometheusTemplate{
+							{
+								Name: "foo",
+								URI:  "http://localhost",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -614,3 +717,259 @@ func TestFilePathDiscover(t *testing.T) {
 		})
 	}
 }
+
+func TestPrometheusConfigFileDiscover(t *testing.T) {
+	type serverT struct {
+		name    string
+		uri     string
+		include []string
+	}
+
+	type testCaseT struct {
+		setup       func(t *testing.T) PrometheusConfigFile
+		description string
+		err         string
+		servers     []serverT
+	}
+
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "missing configuration file",
+			setup: func(_ *testing.T) PrometheusConfigFile {
+				return PrometheusConfigFile{Path: "missing.yml"}
+			},
+			err: "prometheusConfig discovery failed to load Prometheus configuration: open missing.yml: no such file or directory",
+		},
+		{
+			description: "invalid configuration file",
+			setup: func(t *testing.T) PrometheusConfigFile {
+				t.Helper()
+				writeFile(t, "prometheus.yml", "rule_files: foo\n")
+				return PrometheusConfigFile{Path: "prometheus.yml"}
+			},
+			err: "prometheusConfig discovery failed to load Prometheus configuration: failed to parse prometheus.yml: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `foo` into []string",
+		},
+		{
+			description: "invalid rule_files pattern",
+			setup: func(t *testing.T) PrometheusConfigFile {
+				t.Helper()
+				writeFile(t, "prometheus.yml", "rule_files: ['[']\n")
+				return PrometheusConfigFile{Path: "prometheus.yml"}
+			},
+			err: `prometheusConfig discovery failed to expand rule_files: invalid rule_files pattern "[": syntax error in pattern`,
+		},
+		{
+			description: "no matching rule files",
+			setup: func(t *testing.T) PrometheusConfigFile {
+				t.Helper()
+				writeFile(t, "prometheus.yml", "rule_files: ['rules/*.yml']\n")
+				return PrometheusConfigFile{Path: "prometheus.yml"}
+			},
+		},
+		{
+			description: "no template",
+			setup: func(t *testing.T) PrometheusConfigFile {
+				t.Helper()
+				writeFile(t, "cfg/prometheus.yml", "rule_files: ['rules/*.yml', 'rules/a.yml']\n")
+				writeFile(t, "cfg/rules/a.yml", "groups: []\n")
+				writeFile(t, "cfg/rules/b.yml", "groups: []\n")
+				return PrometheusConfigFile{Path: "cfg/prometheus.yml"}
+			},
+			servers: []serverT{
+				{
+					name:    "cfg/prometheus.yml",
+					uri:     "cfg/prometheus.yml",
+					include: []string{"^cfg/rules/a\\.yml$", "^cfg/rules/b\\.yml$"},
+				},
+			},
+		},
+		{
+			description: "template using external_labels",
+			setup: func(t *testing.T) PrometheusConfigFile {
+				t.Helper()
+				writeFile(t, "prometheus.yml", `
+global:
+  external_labels:
+    cluster: dev
+rule_files:
+  - rules.yml
+`)
+				writeFile(t, "rules.yml", "groups: []\n")
+				return PrometheusConfigFile{
+					Path: "prometheus.yml",
+					Template: []PrometheusTemplate{
+						{
+							Name:    "prom-{{ $cluster }}",
+							URI:     "https://{{ $cluster }}.example.com",
+							Include: []string{"extra.yml"},
+						},
+					},
+				}
+			},
+			servers: []serverT{
+				{
+					name:    "prom-dev",
+					uri:     "https://dev.example.com",
+					include: []string{"^extra.yml$", "^rules\\.yml$"},
+				},
+			},
+		},
+		{
+			description: "template with missing external label",
+			setup: func(t *testing.T) PrometheusConfigFile {
+				t.Helper()
+				writeFile(t, "prometheus.yml", "rule_files: ['rules.yml']\n")
+				writeFile(t, "rules.yml", "groups: []\n")
+				return PrometheusConfigFile{
+					Path: "prometheus.yml",
+					Template: []PrometheusTemplate{
+						{
+							Name: "prom-{{ $cluster }}",
+							URI:  "https://example.com",
+						},
+					},
+				}
+			},
+			err: `prometheusConfig discovery failed to generate Prometheus config from a template: bad name template "prom-{{ $cluster }}": template: discovery:1: undefined variable "$cluster"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			slog.SetDefault(slogt.New(t))
+			t.Chdir(t.TempDir())
+			pc := tc.setup(t)
+			servers, err := pc.Discover(context.Background())
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			got := make([]serverT, 0, len(servers))
+			for _, s := range servers {
+				require.True(t, s.HasLocalConfig())
+				got = append(got, serverT{name: s.Name(), uri: s.URI(), include: s.Include()})
+			}
+			if len(tc.servers) == 0 {
+				require.Empty(t, got)
+			} else {
+				require.Equal(t, tc.servers, got)
+			}
+		})
+	}
+}
+
+func TestFileSDDiscover(t *testing.T) {
+	type serverT struct {
+		name string
+		uri  string
+		tags []string
+	}
+
+	type testCaseT struct {
+		files       map[string]string
+		description string
+		err         string
+		sd          FileSD
+		servers     []serverT
+	}
+
+	template := PrometheusTemplate{
+		Name: "{{ $cluster }}",
+		URI:  "http://{{ $__address__ }}",
+		Tags: []string{"{{ $__meta_filepath }}"},
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no matching files",
+			sd: FileSD{
+				Files:    []string{"targets/*.json"},
+				Template: []PrometheusTemplate{template},
+			},
+		},
+		{
+			description: "invalid file",
+			files: map[string]string{
+				"targets/prom.json": `{"targets": []}`,
+			},
+			sd: FileSD{
+				Files:    []string{"targets/*.json"},
+				Template: []PrometheusTemplate{template},
+			},
+			err: "fileSD discovery failed to read targets: failed to parse targets/prom.json: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!map into []config.fileSDTargetGroup",
+		},
+		{
+			description: "json and yaml files",
+			files: map[string]string{
+				"targets/a.json": `[
+  {"targets": ["prom1:9090", "prom2:9090"], "labels": {"cluster": "dev"}},
+  {"targets": ["prom3:9090"], "labels": {"cluster": "prod"}}
+]`,
+				"targets/b.yml": `
+- targets:
+    - prom4:9090
+  labels:
+    cluster: staging
+`,
+				"targets/c.txt": "foo",
+			},
+			sd: FileSD{
+				Files:    []string{"targets/*.json", "targets/*.yml"},
+				Template: []PrometheusTemplate{template},
+			},
+			servers: []serverT{
+				{name: "dev", uri: "http://prom1:9090", tags: []string{"targets/a.json"}},
+				{name: "dev", uri: "http://prom2:9090", tags: []string{"targets/a.json"}},
+				{name: "prod", uri: "http://prom3:9090", tags: []string{"targets/a.json"}},
+				{name: "staging", uri: "http://prom4:9090", tags: []string{"targets/b.yml"}},
+			},
+		},
+		{
+			description: "missing label",
+			files: map[string]string{
+				"targets/a.json": `[{"targets": ["prom1:9090"]}]`,
+			},
+			sd: FileSD{
+				Files:    []string{"targets/*.json"},
+				Template: []PrometheusTemplate{template},
+			},
+			err: `fileSD discovery failed to generate Prometheus config from a template: bad name template "{{ $cluster }}": template: discovery:1: undefined variable "$cluster"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			slog.SetDefault(slogt.New(t))
+			t.Chdir(t.TempDir())
+			for path, content := range tc.files {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+			}
+
+			servers, err := tc.sd.Discover(context.Background())
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			got := make([]serverT, 0, len(servers))
+			for _, s := range servers {
+				got = append(got, serverT{name: s.Name(), uri: s.URI(), tags: s.Tags()})
+			}
+			if len(tc.servers) == 0 {
+				require.Empty(t, got)
+			} else {
+				require.Equal(t, tc.servers, got)
+			}
+		})
+	}
+}
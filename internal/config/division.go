@@ -0,0 +1,27 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type DivisionSettings struct {
+	Comment  string `hcl:"comment,optional" json:"comment,omitempty"`
+	Severity string `hcl:"severity,optional" json:"severity,omitempty"`
+}
+
+func (ds DivisionSettings) validate() error {
+	if ds.Severity != "" {
+		if _, err := checks.ParseSeverity(ds.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ds DivisionSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if ds.Severity != "" {
+		sev, _ := checks.ParseSeverity(ds.Severity)
+		return sev
+	}
+	return fallback
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDivisionSettings(t *testing.T) {
+	type testCaseT struct {
+		err  error
+		conf DivisionSettings
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: DivisionSettings{},
+		},
+		{
+			conf: DivisionSettings{
+				Comment:  "foo",
+				Severity: "bug",
+			},
+		},
+		{
+			conf: DivisionSettings{
+				Severity: "xxx",
+			},
+			err: errors.New("unknown severity: xxx"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				require.Equal(t, err, tc.err)
+			} else {
+				require.EqualError(t, err, tc.err.Error())
+			}
+		})
+	}
+}
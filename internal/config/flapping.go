@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type FlappingSettings struct {
+	Range    string `hcl:"range,optional" json:"range,omitempty"`
+	Step     string `hcl:"step,optional" json:"step,omitempty"`
+	Comment  string `hcl:"comment,optional" json:"comment,omitempty"`
+	Severity string `hcl:"severity,optional" json:"severity,omitempty"`
+	MaxFlaps int    `hcl:"maxFlaps" json:"maxFlaps"`
+}
+
+func (fs FlappingSettings) validate() error {
+	if fs.Range != "" {
+		if _, err := parseDuration(fs.Range); err != nil {
+			return err
+		}
+	}
+	if fs.Step != "" {
+		if _, err := parseDuration(fs.Step); err != nil {
+			return err
+		}
+	}
+	if fs.MaxFlaps < 0 {
+		return fmt.Errorf("maxFlaps cannot be < 0, got %d", fs.MaxFlaps)
+	}
+	if fs.Severity != "" {
+		if _, err := checks.ParseSeverity(fs.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fs FlappingSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if fs.Severity != "" {
+		sev, _ := checks.ParseSeverity(fs.Severity)
+		return sev
+	}
+	return fallback
+}
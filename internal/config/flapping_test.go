@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlappingSettings(t *testing.T) {
+	type testCaseT struct {
+		err  error
+		conf FlappingSettings
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: FlappingSettings{
+				Range:    "7d",
+				Step:     "5m",
+				MaxFlaps: 3,
+			},
+		},
+		{
+			conf: FlappingSettings{
+				Range: "foo",
+			},
+			err: errors.New(`not a valid duration string: "foo"`),
+		},
+		{
+			conf: FlappingSettings{
+				Step: "foo",
+			},
+			err: errors.New(`not a valid duration string: "foo"`),
+		},
+		{
+			conf: FlappingSettings{
+				MaxFlaps: -1,
+			},
+			err: errors.New("maxFlaps cannot be < 0, got -1"),
+		},
+		{
+			conf: FlappingSettings{
+				Severity: "xxx",
+			},
+			err: errors.New("unknown severity: xxx"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				require.Equal(t, err, tc.err)
+			} else {
+				require.EqualError(t, err, tc.err.Error())
+			}
+		})
+	}
+}
@@ -43,6 +43,7 @@ type (
 type Match struct {
 	Label      *MatchLabel        `hcl:"label,block" json:"label,omitempty"`
 	Annotation *MatchAnnotation   `hcl:"annotation,block" json:"annotation,omitempty"`
+	Kubernetes *MatchKubernetes   `hcl:"kubernetes,block" json:"kubernetes,omitempty"`
 	Command    *ContextCommandVal `hcl:"command,optional" json:"command,omitempty"`
 
 	pathRe                     *regexp.Regexp
@@ -94,6 +95,12 @@ func (m *Match) Validate(allowEmpty bool) (err error) {
 		}
 	}
 
+	if m.Kubernetes != nil {
+		if err := m.Kubernetes.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if m.For != "" {
 		dm, err := parseDurationMatch(m.For)
 		if err != nil {
@@ -125,6 +132,7 @@ func (m *Match) Validate(allowEmpty bool) (err error) {
 		m.Kind == "" &&
 		m.Label == nil &&
 		m.Annotation == nil &&
+		m.Kubernetes == nil &&
 		m.Command == nil &&
 		m.For == "" &&
 		m.KeepFiringFor == "" &&
@@ -184,6 +192,12 @@ func (m Match) IsMatch(ctx context.Context, path string, e *discovery.Entry) boo
 		}
 	}
 
+	if m.Kubernetes != nil {
+		if !m.Kubernetes.isMatching(e.Kubernetes) {
+			return false
+		}
+	}
+
 	if m.forDurationMatch != nil {
 		if e.Rule.AlertingRule != nil && e.Rule.AlertingRule.For != nil && e.Rule.AlertingRule.For.ParseError == nil {
 			if !m.forDurationMatch.isMatch(e.Rule.AlertingRule.For.Value) {
@@ -271,6 +285,76 @@ func (ma MatchAnnotation) isMatching(rule parser.Rule) bool {
 	return false
 }
 
+type MatchKubernetes struct {
+	Label       *MatchLabel `hcl:"label,block" json:"label,omitempty"`
+	nameRe      *regexp.Regexp
+	namespaceRe *regexp.Regexp
+	Name        string `hcl:"name,optional" json:"name,omitempty"`
+	Namespace   string `hcl:"namespace,optional" json:"namespace,omitempty"`
+}
+
+func (mk *MatchKubernetes) Validate() (err error) {
+	if mk.Name != "" {
+		mk.nameRe, err = regexp.Compile("^" + mk.Name + "$")
+		if err != nil {
+			return err
+		}
+	}
+
+	if mk.Namespace != "" {
+		mk.namespaceRe, err = regexp.Compile("^" + mk.Namespace + "$")
+		if err != nil {
+			return err
+		}
+	}
+
+	if mk.Label != nil {
+		if err = mk.Label.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mk MatchKubernetes) isMatching(obj *parser.KubernetesObject) bool {
+	if obj == nil {
+		return false
+	}
+
+	if mk.nameRe != nil && !mk.nameRe.MatchString(obj.Name.Value) {
+		return false
+	}
+
+	if mk.namespaceRe != nil {
+		var namespace string
+		if obj.Namespace != nil {
+			namespace = obj.Namespace.Value
+		}
+		if !mk.namespaceRe.MatchString(namespace) {
+			return false
+		}
+	}
+
+	if mk.Label != nil {
+		if obj.Labels == nil {
+			return false
+		}
+		var found bool
+		for _, label := range obj.Labels.Items {
+			if mk.Label.keyRe.MatchString(label.Key.Value) && mk.Label.valRe.MatchString(label.Value.Value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
 type matchOperation string
 
 const (
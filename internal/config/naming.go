@@ -0,0 +1,27 @@
+package config
+
+import (
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type NamingSettings struct {
+	Comment  string `hcl:"comment,optional" json:"comment,omitempty"`
+	Severity string `hcl:"severity,optional" json:"severity,omitempty"`
+}
+
+func (ns NamingSettings) validate() error {
+	if ns.Severity != "" {
+		if _, err := checks.ParseSeverity(ns.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ns NamingSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if ns.Severity != "" {
+		sev, _ := checks.ParseSeverity(ns.Severity)
+		return sev
+	}
+	return fallback
+}
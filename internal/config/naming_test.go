@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingSettings(t *testing.T) {
+	type testCaseT struct {
+		err  error
+		conf NamingSettings
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: NamingSettings{},
+		},
+		{
+			conf: NamingSettings{
+				Comment:  "foo",
+				Severity: "bug",
+			},
+		},
+		{
+			conf: NamingSettings{
+				Severity: "xxx",
+			},
+			err: errors.New("unknown severity: xxx"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				require.Equal(t, err, tc.err)
+			} else {
+				require.EqualError(t, err, tc.err.Error())
+			}
+		})
+	}
+}
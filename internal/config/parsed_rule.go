@@ -115,23 +115,41 @@ func defaultMatchStates(cmd ContextCommandVal) []string {
 }
 
 func baseRules(staticRules []staticRule, proms []*promapi.FailoverGroup, match []Match) (rules []*parsedRule) {
-	const checksPerProm = 11 // Number of checks registered per Prometheus server below.
+	const checksPerProm = 15 // Number of checks registered per Prometheus server below.
 	rules = make([]*parsedRule, 0, len(staticRules)+(len(proms)*checksPerProm))
 	for _, sr := range staticRules {
 		rules = append(rules, baseParsedRule(match, sr.name, sr.checker, nil))
 	}
 
 	for _, p := range proms {
+		if p.HasLocalConfig() {
+			rules = append(rules, baseParsedRule(match, checks.GroupIntervalCheckName, checks.NewGroupIntervalCheck(p), p.Tags()))
+		}
+		if p.ServerCount() == 0 {
+			// Servers generated from a local Prometheus configuration file without any URI
+			// can only be used by checks that don't need to query Prometheus.
+			rules = append(
+				rules,
+				baseParsedRule(match, checks.LabelsConflictCheckName, checks.NewLabelsConflictCheck(p), p.Tags()),
+				baseParsedRule(match, checks.AlertsExternalLabelsCheckName, checks.NewAlertsExternalLabelsCheck(p), p.Tags()),
+				baseParsedRule(match, checks.AlertsDuplicateCheckName, checks.NewAlertsDuplicateCheck(p), p.Tags()),
+			)
+			continue
+		}
 		rules = append(
 			rules,
+			baseParsedRule(match, checks.GroupLimitCheckName, checks.NewGroupLimitCheck(p), p.Tags()),
+			baseParsedRule(match, checks.GroupQueryOffsetCheckName, checks.NewGroupQueryOffsetCheck(p), p.Tags()),
 			baseParsedRule(match, checks.RateCheckName, checks.NewRateCheck(p), p.Tags()),
 			baseParsedRule(match, checks.SeriesCheckName, checks.NewSeriesCheck(p), p.Tags()),
+			baseParsedRule(match, checks.SubqueryCheckName, checks.NewSubqueryCheck(p), p.Tags()),
 			baseParsedRule(match, checks.VectorMatchingCheckName, checks.NewVectorMatchingCheck(p), p.Tags()),
 			baseParsedRule(match, checks.OffsetCheckName, checks.NewOffsetCheck(p), p.Tags()),
 			baseParsedRule(match, checks.RangeQueryCheckName, checks.NewRangeQueryCheck(p, 0, "", checks.Warning), p.Tags()),
 			baseParsedRule(match, checks.RuleDuplicateCheckName, checks.NewRuleDuplicateCheck(p), p.Tags()),
 			baseParsedRule(match, checks.LabelsConflictCheckName, checks.NewLabelsConflictCheck(p), p.Tags()),
 			baseParsedRule(match, checks.AlertsExternalLabelsCheckName, checks.NewAlertsExternalLabelsCheck(p), p.Tags()),
+			baseParsedRule(match, checks.AlertsDuplicateCheckName, checks.NewAlertsDuplicateCheck(p), p.Tags()),
 			baseParsedRule(match, checks.CounterCheckName, checks.NewCounterCheck(p), p.Tags()),
 			baseParsedRule(match, checks.AlertsAbsentCheckName, checks.NewAlertsAbsentCheck(p), p.Tags()),
 			baseParsedRule(match, checks.FeaturesCheckName, checks.NewFeaturesCheck(p), p.Tags()),
@@ -155,7 +173,16 @@ func defaultRuleMatch(match []Match, defaultStates []string) []Match {
 	return dst
 }
 
-func parseRule(rule Rule, prometheusServers []*promapi.FailoverGroup, defaultStates []string) (rules []*parsedRule) {
+func parseRule(rule Rule, proms []*promapi.FailoverGroup, defaultStates []string) (rules []*parsedRule) {
+	// All checks below that use Prometheus servers need to query them,
+	// skip servers that only have a local configuration file.
+	prometheusServers := make([]*promapi.FailoverGroup, 0, len(proms))
+	for _, prom := range proms {
+		if prom.ServerCount() > 0 {
+			prometheusServers = append(prometheusServers, prom)
+		}
+	}
+
 	if len(rule.Aggregate) > 0 {
 		var nameRegex *checks.TemplatedRegexp
 		for _, aggr := range rule.Aggregate {
@@ -198,6 +225,19 @@ func parseRule(rule Rule, prometheusServers []*promapi.FailoverGroup, defaultSta
 		}
 	}
 
+	if rule.Cardinality != nil {
+		severity := rule.Cardinality.getSeverity(checks.Bug)
+		for _, prom := range prometheusServers {
+			rules = append(rules, newParsedRule(
+				rule,
+				defaultStates,
+				checks.CardinalityCheckName,
+				checks.NewCardinalityCheck(prom, rule.Cardinality.MaxSeries, rule.Cardinality.MaxGrowth, rule.Cardinality.Comment, severity),
+				prom.Tags(),
+			))
+		}
+	}
+
 	if len(rule.Annotation) > 0 {
 		for _, ann := range rule.Annotation {
 			var tokenRegex, valueRegex *checks.TemplatedRegexp
@@ -263,6 +303,52 @@ func parseRule(rule Rule, prometheusServers []*promapi.FailoverGroup, defaultSta
 		}
 	}
 
+	if rule.Flapping != nil {
+		qRange := time.Hour * 24
+		if rule.Flapping.Range != "" {
+			qRange, _ = parseDuration(rule.Flapping.Range)
+		}
+		qStep := time.Minute
+		if rule.Flapping.Step != "" {
+			qStep, _ = parseDuration(rule.Flapping.Step)
+		}
+		severity := rule.Flapping.getSeverity(checks.Warning)
+		for _, prom := range prometheusServers {
+			rules = append(rules, newParsedRule(
+				rule,
+				defaultStates,
+				checks.AlertsFlappingCheckName,
+				checks.NewAlertsFlappingCheck(prom, qRange, qStep, rule.Flapping.MaxFlaps, rule.Flapping.Comment, severity),
+				prom.Tags(),
+			))
+		}
+	}
+
+	if rule.Threshold != nil {
+		qRange := time.Hour * 24
+		if rule.Threshold.Range != "" {
+			qRange, _ = parseDuration(rule.Threshold.Range)
+		}
+		qStep := time.Minute
+		if rule.Threshold.Step != "" {
+			qStep, _ = parseDuration(rule.Threshold.Step)
+		}
+		factor := 10.0
+		if rule.Threshold.Factor != 0 {
+			factor = rule.Threshold.Factor
+		}
+		severity := rule.Threshold.getSeverity(checks.Warning)
+		for _, prom := range prometheusServers {
+			rules = append(rules, newParsedRule(
+				rule,
+				defaultStates,
+				checks.AlertsThresholdCheckName,
+				checks.NewAlertsThresholdCheck(prom, qRange, qStep, factor, rule.Threshold.Comment, severity),
+				prom.Tags(),
+			))
+		}
+	}
+
 	if len(rule.Reject) > 0 {
 		for _, reject := range rule.Reject {
 			severity := reject.getSeverity(checks.Bug)
@@ -358,6 +444,28 @@ func parseRule(rule Rule, prometheusServers []*promapi.FailoverGroup, defaultSta
 		))
 	}
 
+	if rule.Naming != nil {
+		severity := rule.Naming.getSeverity(checks.Warning)
+		rules = append(rules, newParsedRule(
+			rule,
+			defaultStates,
+			checks.RuleNamingCheckName,
+			checks.NewRuleNamingCheck(rule.Naming.Comment, severity),
+			nil,
+		))
+	}
+
+	if rule.Division != nil {
+		severity := rule.Division.getSeverity(checks.Warning)
+		rules = append(rules, newParsedRule(
+			rule,
+			defaultStates,
+			checks.DivisionCheckName,
+			checks.NewDivisionCheck(rule.Division.Comment, severity),
+			nil,
+		))
+	}
+
 	if rule.RangeQuery != nil {
 		severity := rule.RangeQuery.getSeverity(checks.Warning)
 		limit, _ := parseDuration(rule.RangeQuery.Max)
@@ -418,5 +526,27 @@ func parseRule(rule Rule, prometheusServers []*promapi.FailoverGroup, defaultSta
 		}
 	}
 
+	for _, custom := range rule.Custom {
+		var require *checks.CustomPattern
+		if custom.Require != nil {
+			pattern := custom.Require.toPattern()
+			require = &pattern
+		}
+		rules = append(rules, newParsedRule(
+			rule,
+			defaultStates,
+			checks.CustomCheckName,
+			checks.NewCustomCheck(
+				custom.Name,
+				custom.Match.toPattern(),
+				require,
+				custom.Message,
+				custom.Comment,
+				custom.getSeverity(checks.Warning),
+			),
+			nil,
+		))
+	}
+
 	return rules
 }
@@ -1,17 +1,21 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 
 	"github.com/prometheus/common/model"
 
+	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/parser"
 )
 
 const (
 	SchemaPrometheus = "prometheus"
 	SchemaThanos     = "thanos"
+	SchemaLoki       = "loki"
+	SchemaMimir      = "mimir"
 
 	NamesLegacy = "legacy"
 	NamesUTF8   = "utf-8"
@@ -23,6 +27,12 @@ type Parser struct {
 	Relaxed []string `hcl:"relaxed,optional" json:"relaxed,omitempty"`
 	Include []string `hcl:"include,optional" json:"include,omitempty"`
 	Exclude []string `hcl:"exclude,optional" json:"exclude,omitempty"`
+	Loki    []string `hcl:"loki,optional" json:"loki,omitempty"`
+
+	Kubernetes           []string `hcl:"kubernetes,optional" json:"kubernetes,omitempty"`
+	KubernetesOwnerLabel string   `hcl:"kubernetesOwnerLabel,optional" json:"kubernetesOwnerLabel,omitempty"`
+
+	Render []Render `hcl:"render,block" json:"render,omitempty"`
 
 	opts parser.Options
 }
@@ -49,6 +59,8 @@ func (p Parser) validate() error {
 	switch s := p.getSchema(); s {
 	case SchemaPrometheus:
 	case SchemaThanos:
+	case SchemaLoki:
+	case SchemaMimir:
 	default:
 		return fmt.Errorf("unsupported parser schema: %s", s)
 	}
@@ -78,9 +90,80 @@ func (p Parser) validate() error {
 		}
 	}
 
+	for _, path := range p.Loki {
+		if _, err := regexp.Compile(path); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range p.Kubernetes {
+		if _, err := regexp.Compile(path); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range p.Render {
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func (p Parser) Renderers() (renderers []discovery.Renderer) {
+	for _, r := range p.Render {
+		for _, path := range r.Paths {
+			renderers = append(renderers, discovery.Renderer{
+				Kind:    r.Kind,
+				Path:    path,
+				Command: r.command(path),
+			})
+		}
+	}
+	return renderers
+}
+
+type Render struct {
+	Kind    string   `hcl:",label" json:"kind"`
+	Command string   `hcl:"command,optional" json:"command,omitempty"`
+	Paths   []string `hcl:"paths" json:"paths"`
+	Args    []string `hcl:"args,optional" json:"args,omitempty"`
+}
+
+func (r Render) validate() error {
+	switch r.Kind {
+	case discovery.HelmRenderer:
+	case discovery.KustomizeRenderer:
+	default:
+		return fmt.Errorf("unsupported render kind: %s", r.Kind)
+	}
+	if len(r.Paths) == 0 {
+		return errors.New("render paths cannot be empty")
+	}
+	for _, path := range r.Paths {
+		if path == "" {
+			return errors.New("render path cannot be empty")
+		}
+	}
+	return nil
+}
+
+func (r Render) command(path string) []string {
+	cmd := r.Command
+	if cmd == "" {
+		cmd = r.Kind
+	}
+	var subcommand string
+	switch r.Kind {
+	case discovery.HelmRenderer:
+		subcommand = "template"
+	case discovery.KustomizeRenderer:
+		subcommand = "build"
+	}
+	return append([]string{cmd, subcommand, path}, r.Args...)
+}
+
 func (p *Parser) initOptions() {
 	var names model.ValidationScheme
 	if p.getNames() == NamesLegacy {
@@ -90,13 +173,22 @@ func (p *Parser) initOptions() {
 	}
 
 	var schema parser.Schema
-	if p.getSchema() == SchemaThanos {
+	switch p.getSchema() {
+	case SchemaThanos:
 		schema = parser.ThanosSchema
+	case SchemaLoki:
+		schema = parser.LokiSchema
+	case SchemaMimir:
+		schema = parser.MimirSchema
 	}
 
 	p.opts = parser.Options{
-		Names:    names,
-		Schema:   schema,
-		IsStrict: false,
+		Loki:                 MustCompileRegexes(p.Loki...),
+		Kubernetes:           MustCompileRegexes(p.Kubernetes...),
+		KubernetesOwnerLabel: p.KubernetesOwnerLabel,
+		Names:                names,
+		Schema:               schema,
+		IsStrict:             false,
+		IsKubernetes:         false,
 	}
 }
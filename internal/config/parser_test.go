@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/prometheus/common/model"
@@ -44,6 +45,12 @@ func TestParserSettings(t *testing.T) {
 			},
 			err: errors.New("error parsing regexp: invalid nested repetition operator: `++`"),
 		},
+		{
+			conf: Parser{
+				Loki: []string{"(.+++)"},
+			},
+			err: errors.New("error parsing regexp: invalid nested repetition operator: `++`"),
+		},
 		{
 			conf: Parser{
 				Schema: SchemaPrometheus,
@@ -54,6 +61,16 @@ func TestParserSettings(t *testing.T) {
 				Schema: SchemaThanos,
 			},
 		},
+		{
+			conf: Parser{
+				Schema: SchemaLoki,
+			},
+		},
+		{
+			conf: Parser{
+				Schema: SchemaMimir,
+			},
+		},
 		{
 			conf: Parser{
 				Schema: "xxx",
@@ -76,6 +93,29 @@ func TestParserSettings(t *testing.T) {
 				Names: NamesUTF8,
 			},
 		},
+		{
+			conf: Parser{
+				Render: []Render{{Kind: "helm", Paths: []string{"charts/foo"}}},
+			},
+		},
+		{
+			conf: Parser{
+				Render: []Render{{Kind: "jsonnet", Paths: []string{"foo"}}},
+			},
+			err: errors.New("unsupported render kind: jsonnet"),
+		},
+		{
+			conf: Parser{
+				Render: []Render{{Kind: "kustomize"}},
+			},
+			err: errors.New("render paths cannot be empty"),
+		},
+		{
+			conf: Parser{
+				Render: []Render{{Kind: "kustomize", Paths: []string{""}}},
+			},
+			err: errors.New("render path cannot be empty"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -139,6 +179,49 @@ func TestParserInitOptions(t *testing.T) {
 				IsStrict: false,
 			},
 		},
+		{
+			// Loki schema setting produces LokiSchema.
+			description: "loki schema",
+			conf:        Parser{Schema: SchemaLoki},
+			expected: parser.Options{
+				Names:    model.UTF8Validation,
+				Schema:   parser.LokiSchema,
+				IsStrict: false,
+			},
+		},
+		{
+			// Mimir schema setting produces MimirSchema.
+			description: "mimir schema",
+			conf:        Parser{Schema: SchemaMimir},
+			expected: parser.Options{
+				Names:    model.UTF8Validation,
+				Schema:   parser.MimirSchema,
+				IsStrict: false,
+			},
+		},
+		{
+			// Kubernetes paths are compiled as anchored regexps.
+			description: "kubernetes paths",
+			conf:        Parser{Kubernetes: []string{"manifests/.*"}, KubernetesOwnerLabel: "team"},
+			expected: parser.Options{
+				Kubernetes:           []*regexp.Regexp{regexp.MustCompile("^manifests/.*$")},
+				KubernetesOwnerLabel: "team",
+				Names:                model.UTF8Validation,
+				Schema:               parser.PrometheusSchema,
+				IsStrict:             false,
+			},
+		},
+		{
+			// Loki paths are compiled as anchored regexps.
+			description: "loki paths",
+			conf:        Parser{Loki: []string{"loki/.*"}},
+			expected: parser.Options{
+				Loki:     []*regexp.Regexp{regexp.MustCompile("^loki/.*$")},
+				Names:    model.UTF8Validation,
+				Schema:   parser.PrometheusSchema,
+				IsStrict: false,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -4,13 +4,16 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go/parser"
 	"log/slog"
 	"net/url"
 	"os"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -92,6 +95,7 @@ type PrometheusConfig struct {
 	Tags        []string          `hcl:"tags,optional" json:"tags,omitempty"`
 	Concurrency int               `hcl:"concurrency,optional" json:"concurrency"`
 	RateLimit   int               `hcl:"rateLimit,optional" json:"rateLimit"`
+	BatchSize   int               `hcl:"batchSize,optional" json:"batchSize,omitempty"`
 	Required    bool              `hcl:"required,optional" json:"required"`
 }
 
@@ -135,6 +139,10 @@ func (pc PrometheusConfig) validate() error {
 		}
 	}
 
+	if pc.BatchSize < 0 {
+		return fmt.Errorf("prometheus batchSize must be >= 0, got %d", pc.BatchSize)
+	}
+
 	if pc.TLS != nil {
 		if err := pc.TLS.validate(); err != nil {
 			return err
@@ -168,9 +176,9 @@ func newFailoverGroup(prom PrometheusConfig) *promapi.FailoverGroup {
 	var tlsConf *tls.Config
 	tlsConf, _ = prom.TLS.toHTTPConfig()
 	upstreams := make([]*promapi.Prometheus, 0, len(prom.Failover)+1)
-	upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, prom.URI, prom.PublicURI, prom.Headers, timeout, prom.Concurrency, prom.RateLimit, tlsConf))
+	upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, prom.URI, prom.PublicURI, prom.Headers, timeout, prom.Concurrency, prom.RateLimit, prom.BatchSize, tlsConf))
 	for _, uri := range prom.Failover {
-		upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, uri, prom.PublicURI, prom.Headers, timeout, prom.Concurrency, prom.RateLimit, tlsConf))
+		upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, uri, prom.PublicURI, prom.Headers, timeout, prom.Concurrency, prom.RateLimit, prom.BatchSize, tlsConf))
 	}
 	include := make([]*regexp.Regexp, 0, len(prom.Include))
 	for _, path := range prom.Include {
@@ -182,7 +190,10 @@ func newFailoverGroup(prom PrometheusConfig) *promapi.FailoverGroup {
 	}
 	tags := make([]string, 0, len(prom.Tags))
 	tags = append(tags, prom.Tags...)
-	return promapi.NewFailoverGroup(prom.Name, prom.PublicURI, upstreams, prom.Required, prom.Uptime, include, exclude, tags)
+	group := promapi.NewFailoverGroup(prom.Name, prom.PublicURI, upstreams, prom.Required, prom.Uptime, include, exclude, tags)
+	src, _ := json.Marshal(prom)
+	group.SetSource(string(src))
+	return group
 }
 
 func NewPrometheusGenerator(cfg Config, metricsRegistry *prometheus.Registry) *PrometheusGenerator {
@@ -190,6 +201,8 @@ func NewPrometheusGenerator(cfg Config, metricsRegistry *prometheus.Registry) *P
 		metricsRegistry: metricsRegistry,
 		cfg:             cfg,
 		servers:         nil,
+		discovered:      nil,
+		static:          nil,
 	}
 }
 
@@ -197,6 +210,8 @@ type PrometheusGenerator struct {
 	cfg             Config
 	metricsRegistry *prometheus.Registry
 	servers         []*promapi.FailoverGroup
+	discovered      []*promapi.FailoverGroup
+	static          map[string]PrometheusConfig
 }
 
 func (pg *PrometheusGenerator) Servers() []*promapi.FailoverGroup {
@@ -212,6 +227,8 @@ func (pg *PrometheusGenerator) Stop() {
 		server.Close(pg.metricsRegistry)
 	}
 	pg.servers = nil
+	pg.discovered = nil
+	pg.static = nil
 }
 
 func (pg *PrometheusGenerator) ServersForPath(path string) []*promapi.FailoverGroup {
@@ -249,24 +266,108 @@ func (pg *PrometheusGenerator) addServer(server *promapi.FailoverGroup) {
 }
 
 func (pg *PrometheusGenerator) GenerateStatic() {
+	pg.static = make(map[string]PrometheusConfig, len(pg.cfg.Prometheus))
 	for _, pc := range pg.cfg.Prometheus {
 		pg.addServer(newFailoverGroup(pc))
+		pg.static[pc.Name] = pc
 	}
 }
 
+// Reload replaces the config used by the generator.
+// Servers with unchanged configuration are kept, together with all cached
+// responses, while servers that were modified or removed are stopped.
+// Discovered servers are kept until the next GenerateDynamic call, which will
+// only replace those that have changed, unless a static server with the same
+// name was added.
+func (pg *PrometheusGenerator) Reload(cfg Config) {
+	static := make(map[string]PrometheusConfig, len(cfg.Prometheus))
+	for _, pc := range cfg.Prometheus {
+		static[pc.Name] = pc
+	}
+
+	kept := make(map[string]*promapi.FailoverGroup, len(pg.servers))
+	discovered := make([]*promapi.FailoverGroup, 0, len(pg.discovered))
+	for _, server := range pg.servers {
+		pc, ok := static[server.Name()]
+		if slices.Contains(pg.discovered, server) {
+			if !ok && cfg.Discovery != nil {
+				discovered = append(discovered, server)
+				continue
+			}
+		} else if ok && reflect.DeepEqual(pg.static[server.Name()], pc) {
+			kept[server.Name()] = server
+			continue
+		}
+		slog.LogAttrs(context.Background(), slog.LevelInfo, "Removing Prometheus server", slog.String("name", server.Name()))
+		server.Close(pg.metricsRegistry)
+	}
+
+	pg.cfg = cfg
+	pg.servers = nil
+	for _, pc := range cfg.Prometheus {
+		if server, ok := kept[pc.Name]; ok {
+			slog.LogAttrs(context.Background(), slog.LevelDebug, "Prometheus server configuration is unchanged", slog.String("name", pc.Name))
+			pg.servers = append(pg.servers, server)
+			continue
+		}
+		pg.addServer(newFailoverGroup(pc))
+	}
+	pg.servers = append(pg.servers, discovered...)
+	pg.discovered = discovered
+	pg.static = static
+}
+
+// GenerateDynamic runs all discovery blocks and adds found servers.
+// It can be called repeatedly (by pint watch) to follow any changes in discovered
+// servers, servers found by a previous call are kept if their configuration
+// didn't change, all other previously found servers are removed.
 func (pg *PrometheusGenerator) GenerateDynamic(ctx context.Context) (err error) {
 	if pg.cfg.Discovery != nil {
 		servers, err := pg.cfg.Discovery.Discover(ctx)
 		if err != nil {
 			return err
 		}
+
+		names := make(map[string]struct{}, len(pg.servers)+len(servers))
+		for _, s := range pg.servers {
+			if !slices.Contains(pg.discovered, s) {
+				names[s.Name()] = struct{}{}
+			}
+		}
+		for _, server := range servers {
+			if _, ok := names[server.Name()]; ok {
+				return fmt.Errorf("Duplicated name for Prometheus server definition: %s", server.Name()) // nolint: staticcheck
+			}
+			names[server.Name()] = struct{}{}
+		}
+
+		previous := pg.discovered
+		pg.servers = slices.DeleteFunc(pg.servers, func(s *promapi.FailoverGroup) bool {
+			return slices.Contains(previous, s)
+		})
+		pg.discovered = nil
+
+		kept := make(map[*promapi.FailoverGroup]bool, len(previous))
+		for i, server := range servers {
+			if j := slices.IndexFunc(previous, server.IsSameAs); j >= 0 {
+				servers[i] = previous[j]
+				kept[previous[j]] = true
+				previous = slices.Delete(previous, j, j+1)
+			}
+		}
+		for _, server := range previous {
+			slog.LogAttrs(ctx, slog.LevelInfo, "Removing Prometheus server", slog.String("name", server.Name()))
+			server.Close(pg.metricsRegistry)
+		}
+
 		for _, server := range servers {
-			for _, s := range pg.servers {
-				if s.Name() == server.Name() {
-					return fmt.Errorf("Duplicated name for Prometheus server definition: %s", s.Name()) // nolint: staticcheck
-				}
+			if kept[server] {
+				slog.LogAttrs(ctx, slog.LevelDebug, "Discovered Prometheus server is unchanged", slog.String("name", server.Name()))
+				pg.servers = append(pg.servers, server)
+			} else {
+				pg.addServer(server)
 			}
-			pg.addServer(server)
+			pg.discovered = append(pg.discovered, server)
 		}
 	}
 	return nil
@@ -3,8 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
 
@@ -85,6 +87,14 @@ func TestPrometheusConfig(t *testing.T) {
 			},
 			err: errors.New(`prometheus tag "a b c" cannot contain " "`),
 		},
+		{
+			conf: PrometheusConfig{
+				Name:      "prom",
+				URI:       "http://localhost",
+				BatchSize: -1,
+			},
+			err: errors.New("prometheus batchSize must be >= 0, got -1"),
+		},
 		{
 			conf: PrometheusConfig{
 				Name: "prom",
@@ -225,3 +235,134 @@ func TestTLSConfigToHTTPConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestPrometheusGeneratorRediscovery(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	cfg := Config{
+		Prometheus: []PrometheusConfig{
+			{
+				Name: "static",
+				URI:  "http://static:9090",
+			},
+		},
+		Discovery: &Discovery{
+			FileSD: []FileSD{
+				{
+					Files: []string{"targets.json"},
+					Template: []PrometheusTemplate{
+						{
+							Name: "{{ $name }}",
+							URI:  "http://{{ $__address__ }}",
+						},
+					},
+				},
+			},
+		},
+	}
+	for i := range cfg.Prometheus {
+		cfg.Prometheus[i].applyDefaults()
+	}
+
+	names := func(gen *PrometheusGenerator) (names []string) {
+		for _, s := range gen.Servers() {
+			names = append(names, s.Name())
+		}
+		return names
+	}
+
+	gen := NewPrometheusGenerator(cfg, prometheus.NewRegistry())
+	defer gen.Stop()
+	gen.GenerateStatic()
+
+	require.NoError(t, os.WriteFile("targets.json", []byte(`[
+  {"targets": ["prom1:9090"], "labels": {"name": "prom1"}},
+  {"targets": ["prom2:9090"], "labels": {"name": "prom2"}}
+]`), 0o644))
+	require.NoError(t, gen.GenerateDynamic(t.Context()))
+	require.Equal(t, []string{"static", "prom1", "prom2"}, names(gen))
+	prom1 := gen.ServerWithName("prom1")
+	prom2 := gen.ServerWithName("prom2")
+
+	require.NoError(t, gen.GenerateDynamic(t.Context()))
+	require.Equal(t, []string{"static", "prom1", "prom2"}, names(gen))
+	require.Same(t, prom1, gen.ServerWithName("prom1"))
+	require.Same(t, prom2, gen.ServerWithName("prom2"))
+
+	require.NoError(t, os.WriteFile("targets.json", []byte(`[
+  {"targets": ["prom2:9090"], "labels": {"name": "prom2"}},
+  {"targets": ["prom3:9090"], "labels": {"name": "prom3"}}
+]`), 0o644))
+	require.NoError(t, gen.GenerateDynamic(t.Context()))
+	require.Equal(t, []string{"static", "prom2", "prom3"}, names(gen))
+	require.Same(t, prom2, gen.ServerWithName("prom2"))
+
+	require.NoError(t, os.WriteFile("targets.json", []byte(`[
+  {"targets": ["prom2:9091"], "labels": {"name": "prom2"}},
+  {"targets": ["prom3:9090"], "labels": {"name": "prom3"}}
+]`), 0o644))
+	prom3 := gen.ServerWithName("prom3")
+	require.NoError(t, gen.GenerateDynamic(t.Context()))
+	require.Equal(t, []string{"static", "prom2", "prom3"}, names(gen))
+	require.NotSame(t, prom2, gen.ServerWithName("prom2"))
+	require.Same(t, prom3, gen.ServerWithName("prom3"))
+
+	static := gen.ServerWithName("static")
+	gen.Reload(cfg)
+	require.Equal(t, []string{"static", "prom2", "prom3"}, names(gen))
+	require.Same(t, static, gen.ServerWithName("static"))
+	require.Same(t, prom3, gen.ServerWithName("prom3"))
+	require.NoError(t, gen.GenerateDynamic(t.Context()))
+	require.Same(t, prom3, gen.ServerWithName("prom3"))
+
+	require.NoError(t, os.WriteFile("targets.json", []byte(`[]`), 0o644))
+	require.NoError(t, gen.GenerateDynamic(t.Context()))
+	require.Equal(t, []string{"static"}, names(gen))
+
+	require.NoError(t, os.WriteFile("targets.json", []byte(`[
+  {"targets": ["prom1:9090"], "labels": {"name": "prom1"}}
+]`), 0o644))
+	require.NoError(t, gen.GenerateDynamic(t.Context()))
+	require.Equal(t, []string{"static", "prom1"}, names(gen))
+
+	gen.Reload(Config{Prometheus: cfg.Prometheus})
+	require.Equal(t, []string{"static"}, names(gen))
+}
+
+func TestPrometheusGeneratorReload(t *testing.T) {
+	newConfig := func(servers ...PrometheusConfig) Config {
+		for i := range servers {
+			servers[i].applyDefaults()
+		}
+		return Config{Prometheus: servers}
+	}
+
+	gen := NewPrometheusGenerator(newConfig(
+		PrometheusConfig{Name: "prom1", URI: "http://prom1:9090"},
+		PrometheusConfig{Name: "prom2", URI: "http://prom2:9090"},
+		PrometheusConfig{Name: "prom3", URI: "http://prom3:9090"},
+	), prometheus.NewRegistry())
+	defer gen.Stop()
+	gen.GenerateStatic()
+
+	prom1 := gen.ServerWithName("prom1")
+	prom2 := gen.ServerWithName("prom2")
+	require.NotNil(t, prom1)
+	require.NotNil(t, prom2)
+
+	gen.Reload(newConfig(
+		PrometheusConfig{Name: "prom4", URI: "http://prom4:9090"},
+		PrometheusConfig{Name: "prom1", URI: "http://prom1:9090"},
+		PrometheusConfig{Name: "prom2", URI: "http://prom2:9090", Tags: []string{"foo"}},
+	))
+
+	var names []string
+	for _, s := range gen.Servers() {
+		names = append(names, s.Name())
+	}
+	require.Equal(t, []string{"prom4", "prom1", "prom2"}, names)
+	require.Same(t, prom1, gen.ServerWithName("prom1"))
+	require.NotSame(t, prom2, gen.ServerWithName("prom2"))
+	require.Equal(t, []string{"foo"}, gen.ServerWithName("prom2").Tags())
+	require.Nil(t, gen.ServerWithName("prom3"))
+}
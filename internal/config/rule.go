@@ -23,9 +23,15 @@ type Rule struct {
 	Annotation    []AnnotationSettings       `hcl:"annotation,block" json:"annotation,omitempty"`
 	Label         []AnnotationSettings       `hcl:"label,block" json:"label,omitempty"`
 	Cost          *CostSettings              `hcl:"cost,block" json:"cost,omitempty"`
+	Cardinality   *CardinalitySettings       `hcl:"cardinality,block" json:"cardinality,omitempty"`
+	Custom        []CustomSettings           `hcl:"custom,block" json:"custom,omitempty"`
+	Division      *DivisionSettings          `hcl:"division,block" json:"division,omitempty"`
 	Alerts        *AlertsSettings            `hcl:"alerts,block" json:"alerts,omitempty"`
+	Flapping      *FlappingSettings          `hcl:"flapping,block" json:"flapping,omitempty"`
+	Threshold     *ThresholdSettings         `hcl:"threshold,block" json:"threshold,omitempty"`
 	For           *ForSettings               `hcl:"for,block" json:"for,omitempty"`
 	KeepFiringFor *ForSettings               `hcl:"keep_firing_for,block" json:"keep_firing_for,omitempty"`
+	Naming        *NamingSettings            `hcl:"naming,block" json:"naming,omitempty"`
 	RangeQuery    *RangeQuerySettings        `hcl:"range_query,block" json:"range_query,omitempty"`
 	Report        *ReportSettings            `hcl:"report,block" json:"report,omitempty"`
 	Reject        []RejectSettings           `hcl:"reject,block" json:"reject,omitempty"`
@@ -85,12 +91,30 @@ func (rule *Rule) validate() (err error) {
 		}
 	}
 
+	if rule.Cardinality != nil {
+		if err = rule.Cardinality.validate(); err != nil {
+			return err
+		}
+	}
+
 	if rule.Alerts != nil {
 		if err = rule.Alerts.validate(); err != nil {
 			return err
 		}
 	}
 
+	if rule.Flapping != nil {
+		if err = rule.Flapping.validate(); err != nil {
+			return err
+		}
+	}
+
+	if rule.Threshold != nil {
+		if err = rule.Threshold.validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, reject := range rule.Reject {
 		if err = reject.validate(); err != nil {
 			return err
@@ -121,6 +145,18 @@ func (rule *Rule) validate() (err error) {
 		}
 	}
 
+	if rule.Naming != nil {
+		if err = rule.Naming.validate(); err != nil {
+			return err
+		}
+	}
+
+	if rule.Division != nil {
+		if err = rule.Division.validate(); err != nil {
+			return err
+		}
+	}
+
 	if rule.RangeQuery != nil {
 		if err = rule.RangeQuery.validate(); err != nil {
 			return err
@@ -145,6 +181,12 @@ func (rule *Rule) validate() (err error) {
 		}
 	}
 
+	for _, custom := range rule.Custom {
+		if err = custom.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type ThresholdSettings struct {
+	Range    string  `hcl:"range,optional" json:"range,omitempty"`
+	Step     string  `hcl:"step,optional" json:"step,omitempty"`
+	Comment  string  `hcl:"comment,optional" json:"comment,omitempty"`
+	Severity string  `hcl:"severity,optional" json:"severity,omitempty"`
+	Factor   float64 `hcl:"factor,optional" json:"factor,omitempty"`
+}
+
+func (ts ThresholdSettings) validate() error {
+	if ts.Range != "" {
+		if _, err := parseDuration(ts.Range); err != nil {
+			return err
+		}
+	}
+	if ts.Step != "" {
+		if _, err := parseDuration(ts.Step); err != nil {
+			return err
+		}
+	}
+	if ts.Factor != 0 && ts.Factor <= 1 {
+		return fmt.Errorf("factor must be > 1, got %v", ts.Factor)
+	}
+	if ts.Severity != "" {
+		if _, err := checks.ParseSeverity(ts.Severity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts ThresholdSettings) getSeverity(fallback checks.Severity) checks.Severity {
+	if ts.Severity != "" {
+		sev, _ := checks.ParseSeverity(ts.Severity)
+		return sev
+	}
+	return fallback
+}
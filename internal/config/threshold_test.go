@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholdSettings(t *testing.T) {
+	type testCaseT struct {
+		err  error
+		conf ThresholdSettings
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: ThresholdSettings{
+				Range:  "7d",
+				Step:   "5m",
+				Factor: 100,
+			},
+		},
+		{
+			conf: ThresholdSettings{
+				Range: "foo",
+			},
+			err: errors.New(`not a valid duration string: "foo"`),
+		},
+		{
+			conf: ThresholdSettings{
+				Step: "foo",
+			},
+			err: errors.New(`not a valid duration string: "foo"`),
+		},
+		{
+			conf: ThresholdSettings{
+				Factor: 0.5,
+			},
+			err: errors.New("factor must be > 1, got 0.5"),
+		},
+		{
+			conf: ThresholdSettings{
+				Severity: "xxx",
+			},
+			err: errors.New("unknown severity: xxx"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				require.Equal(t, err, tc.err)
+			} else {
+				require.EqualError(t, err, tc.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package discovery
+
+import (
+	"slices"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+// DependentEntries returns all entries, other than the changed ones, that might
+// produce different check results after any of the changed entries was modified.
+// This includes rules with the same name, which are compared by rule/duplicate,
+// and rules using results of changed recording rules or ALERTS series of changed
+// alerting rules, which are validated by rule/dependency.
+func DependentEntries(entries, changed []*Entry) (dependents []*Entry) {
+	records := map[string]struct{}{}
+	alerts := map[string]struct{}{}
+	for _, entry := range changed {
+		if entry.Rule.RecordingRule != nil {
+			records[entry.Rule.RecordingRule.Record.Value] = struct{}{}
+		}
+		if entry.Rule.AlertingRule != nil {
+			alerts[entry.Rule.AlertingRule.Alert.Value] = struct{}{}
+		}
+	}
+	if len(records) == 0 && len(alerts) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		if slices.Contains(changed, entry) {
+			continue
+		}
+		if isDependentEntry(entry, records, alerts) {
+			dependents = append(dependents, entry)
+		}
+	}
+	return dependents
+}
+
+func isDependentEntry(entry *Entry, records, alerts map[string]struct{}) bool {
+	if entry.Rule.RecordingRule == nil && entry.Rule.AlertingRule == nil {
+		return false
+	}
+	if entry.Rule.RecordingRule != nil {
+		if _, ok := records[entry.Rule.RecordingRule.Record.Value]; ok {
+			return true
+		}
+	}
+	if entry.Rule.AlertingRule != nil {
+		if _, ok := alerts[entry.Rule.AlertingRule.Alert.Value]; ok {
+			return true
+		}
+	}
+
+	expr := entry.Rule.Expr()
+	if expr.Value == nil || expr.SyntaxError() != nil {
+		return false
+	}
+	query := expr.Query()
+	if query == nil || query.Expr == nil {
+		return false
+	}
+
+	var found bool
+	promParser.Inspect(query.Expr, func(node promParser.Node, _ []promParser.Node) error {
+		vs, ok := node.(*promParser.VectorSelector)
+		if !ok || found {
+			return nil
+		}
+		if _, ok = records[vs.Name]; ok {
+			found = true
+			return nil
+		}
+		if (vs.Name == "ALERTS" || vs.Name == "ALERTS_FOR_STATE") && len(alerts) > 0 {
+			found = usesAlert(vs, alerts)
+		}
+		return nil
+	})
+	return found
+}
+
+// usesAlert returns true if an ALERTS selector can match any of given alert names.
+// Selectors without an alertname equality matcher are assumed to match all alerts.
+func usesAlert(vs *promParser.VectorSelector, alerts map[string]struct{}) bool {
+	for _, lm := range vs.LabelMatchers {
+		if lm.Name == "alertname" && lm.Type == labels.MatchEqual {
+			_, ok := alerts[lm.Value]
+			return ok
+		}
+	}
+	return true
+}
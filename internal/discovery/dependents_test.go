@@ -0,0 +1,143 @@
+package discovery_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestDependentEntries(t *testing.T) {
+	type testCaseT struct {
+		description string
+		changed     string
+		others      string
+		dependents  []string
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no rules in changed file",
+			changed:     "groups: []\n",
+			others: `
+groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(bar)
+`,
+		},
+		{
+			description: "unrelated rules",
+			changed: `
+groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(bar)
+  - alert: Foo
+    expr: foo > 0
+`,
+			others: `
+groups:
+- name: bar
+  rules:
+  - record: bar
+    expr: sum(up)
+  - alert: Bar
+    expr: up == 0
+  - alert: Alerts
+    expr: ALERTS{alertname="Bar"}
+  - record: broken
+    expr: sum(foo
+- name: invalid
+  rules:
+  - foo: bar
+`,
+		},
+		{
+			description: "rules using changed rules",
+			changed: `
+groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(bar)
+  - alert: Foo
+    expr: foo > 0
+`,
+			others: `
+groups:
+- name: bar
+  rules:
+  - record: uses:foo
+    expr: rate(foo[5m])
+  - alert: UsesFoo
+    expr: ALERTS_FOR_STATE{alertname="Foo"}
+  - alert: AnyAlert
+    expr: ALERTS{severity="critical"}
+  - alert: Bar
+    expr: up == 0
+`,
+			dependents: []string{"uses:foo", "UsesFoo", "AnyAlert"},
+		},
+		{
+			description: "rules with the same name",
+			changed: `
+groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(bar)
+  - alert: Foo
+    expr: up == 0
+`,
+			others: `
+groups:
+- name: bar
+  rules:
+  - record: foo
+    expr: sum(bar) without(job)
+  - alert: Foo
+    expr: up == 1
+  - alert: Bar
+    expr: up == 0
+`,
+			dependents: []string{"foo", "Foo"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			require.NoError(t, os.WriteFile("changed.yml", []byte(tc.changed), 0o644))
+			require.NoError(t, os.Mkdir("rules", 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join("rules", "others.yml"), []byte(tc.others), 0o644))
+
+			find := func(path string) []*discovery.Entry {
+				entries, err := discovery.NewGlobFinder(
+					[]string{path},
+					git.NewPathFilter(nil, nil, nil),
+					parser.DefaultOptions,
+					nil,
+				).Find()
+				require.NoError(t, err)
+				return entries
+			}
+
+			changed := find("changed.yml")
+			entries := append(find("rules"), changed...)
+
+			var names []string
+			for _, entry := range discovery.DependentEntries(entries, changed) {
+				names = append(names, entry.Rule.Name())
+			}
+			require.Equal(t, tc.dependents, names)
+		})
+	}
+}
@@ -87,9 +87,12 @@ type Changes struct {
 }
 
 type Entry struct {
-	PathError      error
-	File           *parser.File  `json:"-"`
-	Group          *parser.Group `json:"-"`
+	PathError error
+	File      *parser.File  `json:"-"`
+	Group     *parser.Group `json:"-"`
+	// Metadata of the PrometheusRule object this rule was defined in, only set for Kubernetes manifests.
+	Kubernetes     *parser.KubernetesObject `json:"-"`
+	Before         *parser.Rule             `json:"-"` // Version of this rule on the base branch, only set for modified rules.
 	Path           Path
 	Owner          string
 	Changes        *Changes
@@ -230,6 +233,9 @@ func readRules(reportedPath, sourcePath string, r io.Reader, p parser.Parser, al
 		}
 		for _, rule := range group.Rules {
 			ruleOwner := fileOwner
+			if group.Kubernetes != nil && group.Kubernetes.Owner != "" {
+				ruleOwner = group.Kubernetes.Owner
+			}
 			for _, owner := range comments.Only[comments.Owner](rule.Comments, comments.RuleOwnerType) {
 				ruleOwner = owner.Name
 			}
@@ -241,6 +247,7 @@ func readRules(reportedPath, sourcePath string, r io.Reader, p parser.Parser, al
 				},
 				File:           &file,
 				Group:          &group,
+				Kubernetes:     group.Kubernetes,
 				Rule:           rule,
 				Changes:        changes,
 				Owner:          ruleOwner,
@@ -61,10 +61,10 @@ func (f GitBranchFinder) Find(ctx context.Context, allEntries []*Entry) (entries
 
 	for _, change := range changes {
 		beforeParser := parser.NewParser(
-			f.opts.WithStrict(!f.filter.IsRelaxed(change.Path.Before.Name)),
+			f.opts.WithStrict(!f.filter.IsRelaxed(change.Path.Before.Name)).ForPath(change.Path.Before.Name),
 		)
 		afterParser := parser.NewParser(
-			f.opts.WithStrict(!f.filter.IsRelaxed(change.Path.After.Name)),
+			f.opts.WithStrict(!f.filter.IsRelaxed(change.Path.After.Name)).ForPath(change.Path.After.Name),
 		)
 		var oldPath string
 		if change.Path.Before.Name != change.Path.After.Name {
@@ -143,6 +143,7 @@ func (f GitBranchFinder) Find(ctx context.Context, allEntries []*Entry) (entries
 					)
 				default:
 					me.after.State = Modified
+					me.after.Before = &me.before.Rule
 					slog.LogAttrs(
 						context.Background(), slog.LevelDebug,
 						"Rule modified on HEAD branch",
@@ -193,6 +194,7 @@ func (f GitBranchFinder) Find(ctx context.Context, allEntries []*Entry) (entries
 			if entry.Path.Name == globEntry.Path.Name && entry.Rule.IsSame(globEntry.Rule) {
 				allEntries[i].State = entry.State
 				allEntries[i].Changes = entry.Changes
+				allEntries[i].Before = entry.Before
 				found = true
 				break
 			}
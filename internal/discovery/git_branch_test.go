@@ -2084,6 +2084,14 @@ groups:
 					t.Errorf("tc.finder.Find() returned wrong output (-want +got):\n%s", diff)
 					return
 				}
+				for _, e := range entries {
+					if e.State == discovery.Modified && e.PathError == nil {
+						require.NotNil(t, e.Before, "modified entry without the base branch rule: %s", e.Rule.Name())
+					}
+					if e.State != discovery.Modified {
+						require.Nil(t, e.Before, "%s entry with the base branch rule: %s", e.State, e.Rule.Name())
+					}
+				}
 			}
 		})
 	}
@@ -96,7 +96,7 @@ func (f GlobFinder) Find() (entries []*Entry, err error) {
 			})
 			continue
 		}
-		p := parser.NewParser(f.opts.WithStrict(!f.filter.IsRelaxed(fp.target)))
+		p := parser.NewParser(f.opts.WithStrict(!f.filter.IsRelaxed(fp.target)).ForPath(fp.target))
 		entries = append(entries, readRules(fp.target, fp.path, fd, p, f.allowedOwners, nil)...)
 		fd.Close()
 	}
@@ -0,0 +1,574 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	HelmRenderer      = "helm"
+	KustomizeRenderer = "kustomize"
+
+	helmSourcePrefix          = "# Source: "
+	kustomizeOriginAnnotation = "config.kubernetes.io/origin"
+
+	// Maximum number of rendered and template line pairs we're willing to compare
+	// when mapping rendered output back to the template file.
+	// This only limits the time spent on it, see lcsTable for memory usage.
+	maxMappingCost = 25_000_000
+)
+
+// Renderer describes a single chart or overlay directory that needs to be
+// rendered before rules can be extracted from it.
+type Renderer struct {
+	Kind    string   // Either HelmRenderer or KustomizeRenderer.
+	Path    string   // Chart or overlay directory.
+	Command []string // Command and all arguments to run to render given path.
+}
+
+type RenderCommandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+func RunRenderCommand(ctx context.Context, name string, args ...string) (content []byte, err error) {
+	slog.LogAttrs(ctx, slog.LevelDebug, "Running render command", slog.String("command", name), slog.Any("args", args))
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		if stderr.Len() > 0 {
+			return nil, errors.New(strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+func NewRenderFinder(renderers []Renderer, cmd RenderCommandRunner, opts parser.Options, allowedOwners []*regexp.Regexp) RenderFinder {
+	return RenderFinder{
+		renderers:     renderers,
+		cmd:           cmd,
+		opts:          opts,
+		allowedOwners: allowedOwners,
+	}
+}
+
+// RenderFinder runs helm or kustomize and extracts rules from all PrometheusRule
+// objects found in the rendered manifests.
+// When a rendered document can be mapped back to the template it was generated from
+// then all entries will point at the template file, so that positions of all reported
+// problems match lines in files that people edit.
+// Otherwise entries will point at the rendered output.
+type RenderFinder struct {
+	cmd           RenderCommandRunner
+	renderers     []Renderer
+	allowedOwners []*regexp.Regexp
+	opts          parser.Options
+}
+
+func (f RenderFinder) Find(ctx context.Context) (entries []*Entry, err error) {
+	if len(f.renderers) == 0 {
+		return nil, nil
+	}
+
+	for _, r := range f.renderers {
+		slog.LogAttrs(ctx, slog.LevelInfo, "Rendering rules", slog.String("kind", r.Kind), slog.String("path", r.Path))
+		out, err := f.cmd(ctx, r.Command[0], r.Command[1:]...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s using %s: %w", r.Path, r.Kind, err)
+		}
+		for _, src := range groupDocuments(r, splitDocuments(out)) {
+			entries = append(entries, f.readSource(ctx, r, src)...)
+		}
+	}
+	slog.LogAttrs(ctx, slog.LevelDebug, "Render finder completed", slog.Int("count", len(entries)))
+	return entries, nil
+}
+
+func (f RenderFinder) readSource(ctx context.Context, r Renderer, src renderedSource) []*Entry {
+	rendered := src.lines()
+	if !hasPrometheusRule(rendered) {
+		return nil
+	}
+
+	name := src.path
+	content, ok := mapToTemplate(src.path, rendered)
+	if !ok {
+		if name == "" {
+			name = r.Path
+		}
+		name += " (rendered)"
+		content = strings.Join(rendered, "\n")
+		slog.LogAttrs(
+			ctx, slog.LevelWarn,
+			"Cannot map rendered rules to the template file, problems will be reported using rendered line numbers",
+			slog.String("kind", r.Kind),
+			slog.String("path", r.Path),
+			slog.String("source", src.path),
+		)
+	}
+
+	p := parser.NewParser(f.opts.ForPath(name).WithStrict(false).WithKubernetes(true))
+	return readRules(name, name, strings.NewReader(content), p, f.allowedOwners, nil)
+}
+
+// MarkRenderedChanges sets the state of rendered rules that were modified on the current branch.
+// Rendered entries are only mapped to template files on HEAD, so there's no version of rules
+// from the base branch to compare against. Instead any rule with modified template lines is
+// treated as modified.
+func MarkRenderedChanges(ctx context.Context, gitCmd git.CommandRunner, baseBranch string, entries []*Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var include []*regexp.Regexp
+	seen := map[string]struct{}{}
+	for _, entry := range entries {
+		if _, ok := seen[entry.Path.Name]; ok {
+			continue
+		}
+		seen[entry.Path.Name] = struct{}{}
+		include = append(include, regexp.MustCompile("^"+regexp.QuoteMeta(entry.Path.Name)+"$"))
+	}
+
+	changes, err := git.Changes(ctx, gitCmd, baseBranch, git.NewPathFilter(include, nil, nil))
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		for _, entry := range entries {
+			if entry.Path.Name != change.Path.After.Name {
+				continue
+			}
+			entry.Changes = &Changes{Lines: change.Body.Lines} // nolint: exhaustruct
+			if entry.PathError != nil || isModified(entry.Rule.Lines.Expand(), change.Body.Lines) {
+				entry.State = Modified
+				slog.LogAttrs(
+					ctx, slog.LevelDebug,
+					"Rendered rule modified on HEAD branch",
+					slog.String("name", entry.Rule.Name()),
+					slog.String("path", entry.Path.Name),
+					slog.Any("ruleLines", entry.Rule.Lines),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isModified(lines []int, changed git.LineNumbers) bool {
+	for _, line := range lines {
+		if changed.HasAfter(line) {
+			return true
+		}
+	}
+	return false
+}
+
+type renderedDocument struct {
+	source string
+	lines  []string
+}
+
+// splitDocuments splits rendered output into individual YAML documents.
+// Helm adds a comment with the path of the template file to each document,
+// this comment is removed from the document and stored as its source.
+func splitDocuments(out []byte) (docs []renderedDocument) {
+	var doc renderedDocument
+	for line := range strings.SplitSeq(strings.TrimRight(string(out), "\n"), "\n") {
+		switch {
+		case line == "---" || strings.HasPrefix(line, "--- "):
+			docs = append(docs, doc)
+			doc = renderedDocument{} // nolint: exhaustruct
+		case doc.source == "" && strings.HasPrefix(line, helmSourcePrefix):
+			doc.source = strings.TrimSpace(strings.TrimPrefix(line, helmSourcePrefix))
+		default:
+			doc.lines = append(doc.lines, line)
+		}
+	}
+	docs = append(docs, doc)
+	return docs
+}
+
+type renderedSource struct {
+	path string
+	docs [][]string
+}
+
+// lines returns all documents rendered from this source separated with ---.
+func (rs renderedSource) lines() (lines []string) {
+	for i, doc := range rs.docs {
+		if i > 0 {
+			lines = append(lines, "---")
+		}
+		lines = append(lines, doc...)
+	}
+	return lines
+}
+
+// groupDocuments groups rendered documents by the path of the file they were rendered from.
+// Documents that cannot be attributed to any file are grouped together.
+func groupDocuments(r Renderer, docs []renderedDocument) (sources []renderedSource) {
+	index := map[string]int{}
+	for _, doc := range docs {
+		var path string
+		lines := doc.lines
+		switch r.Kind {
+		case HelmRenderer:
+			path = helmSourcePath(r.Path, doc.source)
+		case KustomizeRenderer:
+			path, lines = kustomizeSourcePath(r.Path, lines)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		i, ok := index[path]
+		if !ok {
+			i = len(sources)
+			index[path] = i
+			sources = append(sources, renderedSource{path: path, docs: nil})
+		}
+		sources[i].docs = append(sources[i].docs, lines)
+	}
+	return sources
+}
+
+// helmSourcePath converts template path from the Source comment into a file path.
+// Source comments start with the chart name rather than the chart directory.
+func helmSourcePath(chartPath, source string) string {
+	_, rel, ok := strings.Cut(source, "/")
+	if !ok {
+		return ""
+	}
+	path := filepath.Join(chartPath, filepath.FromSlash(rel))
+	if !isFile(path) {
+		return ""
+	}
+	return path
+}
+
+// kustomizeSourcePath finds the origin annotation added by kustomize when
+// originAnnotations build metadata option is enabled.
+// Origin annotation isn't present in the source file, so it's removed from
+// returned document lines.
+func kustomizeSourcePath(overlayPath string, lines []string) (string, []string) {
+	var obj struct {
+		Metadata struct {
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &obj); err != nil {
+		return "", lines
+	}
+
+	var origin struct {
+		Path string `yaml:"path"`
+	}
+	if err := yaml.Unmarshal([]byte(obj.Metadata.Annotations[kustomizeOriginAnnotation]), &origin); err != nil || origin.Path == "" {
+		return "", lines
+	}
+
+	lines = removeOriginAnnotation(lines)
+	path := filepath.Join(overlayPath, filepath.FromSlash(origin.Path))
+	if !isFile(path) {
+		return "", lines
+	}
+	return path, lines
+}
+
+func removeOriginAnnotation(lines []string) []string {
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), kustomizeOriginAnnotation+":") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return lines
+	}
+
+	end := start + 1
+	for end < len(lines) && indentation(lines[end]) > indentation(lines[start]) {
+		end++
+	}
+
+	// Remove the annotations key too if origin was the only annotation.
+	if start > 0 && strings.TrimSpace(lines[start-1]) == "annotations:" &&
+		(end == len(lines) || indentation(lines[end]) <= indentation(lines[start-1])) {
+		start--
+	}
+
+	return append(lines[:start:start], lines[end:]...)
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func hasPrometheusRule(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "kind: "+parser.KubernetesKind {
+			return true
+		}
+	}
+	return false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular()
+}
+
+// mapToTemplate places rendered lines on the lines of the template file they were rendered from.
+// Returned content has the same number of lines as the template, with all lines that
+// didn't produce any output left empty. This way line numbers of parsed rules match the template.
+func mapToTemplate(path string, rendered []string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	template := strings.Split(strings.TrimRight(string(body), "\n"), "\n")
+
+	rendered = collapseMetadata(rendered)
+	mapping, ok := mapLines(rendered, template)
+	if !ok {
+		return "", false
+	}
+
+	lines := make([]string, len(template))
+	for i, line := range mapping {
+		if line >= 0 {
+			lines[line] = rendered[i]
+		}
+	}
+	return strings.Join(lines, "\n"), true
+}
+
+// collapseMetadata rewrites the metadata of each object as a single line flow mapping.
+// Both helm and kustomize will often add extra names, labels or annotations to the
+// metadata section, which would otherwise push rules down and make it impossible
+// to place them on their template lines.
+func collapseMetadata(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " ") != "metadata:" {
+			out = append(out, lines[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && (strings.TrimSpace(lines[end]) == "" || indentation(lines[end]) > 0) {
+			end++
+		}
+
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[i:end], "\n")), &doc); err != nil ||
+			len(doc.Content) == 0 || len(doc.Content[0].Content) != 2 || doc.Content[0].Content[1].Kind != yaml.MappingNode {
+			out = append(out, lines[i])
+			continue
+		}
+		metadata := doc.Content[0].Content[1]
+		setFlowStyle(metadata)
+		body, err := yaml.Marshal(metadata)
+		if line := strings.TrimSpace(string(body)); err != nil || strings.Contains(line, "\n") {
+			out = append(out, lines[i])
+		} else {
+			out = append(out, "metadata: "+line)
+			i = end - 1
+		}
+	}
+	return out
+}
+
+func setFlowStyle(node *yaml.Node) {
+	node.HeadComment = ""
+	node.LineComment = ""
+	node.FootComment = ""
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		node.Style = yaml.FlowStyle
+	case yaml.ScalarNode:
+		if strings.Contains(node.Value, "\n") {
+			node.Style = yaml.DoubleQuotedStyle
+		} else if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			node.Style = 0
+		}
+	}
+	for _, child := range node.Content {
+		setFlowStyle(child)
+	}
+}
+
+// mapLines returns the index of the template line for each rendered line.
+// Lines identical in both are matched first, remaining rendered lines are placed
+// in between, preferring template lines with actions.
+// Empty rendered lines are not mapped and have an index of -1.
+// Returns false if there's not enough template lines to place all rendered lines
+// while preserving their order.
+func mapLines(rendered, template []string) ([]int, bool) {
+	n, m := len(rendered), len(template)
+	if n*m > maxMappingCost {
+		return nil, false
+	}
+
+	isEqual := func(i, j int) bool {
+		r := strings.TrimSpace(rendered[i])
+		return r != "" && r == strings.TrimSpace(template[j])
+	}
+
+	// Find the longest common subsequence of rendered and template lines.
+	lcs := newLCSTable(n, m, isEqual)
+
+	mapping := make([]int, n)
+	for i := range mapping {
+		mapping[i] = -1
+	}
+	var pending []int
+	var i, j, next int
+	for i < n && j < m {
+		cur, below := lcs.rows(i)
+		switch {
+		case isEqual(i, j) && cur[j] == below[j+1]+1:
+			if !fillGap(mapping, pending, template, next, j) {
+				return nil, false
+			}
+			pending = pending[:0]
+			mapping[i] = j
+			next = j + 1
+			i++
+			j++
+		case below[j] >= cur[j+1]:
+			if strings.TrimSpace(rendered[i]) != "" {
+				pending = append(pending, i)
+			}
+			i++
+		default:
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		if strings.TrimSpace(rendered[i]) != "" {
+			pending = append(pending, i)
+		}
+	}
+	if !fillGap(mapping, pending, template, next, m) {
+		return nil, false
+	}
+	return mapping, true
+}
+
+// lcsTable holds the length of the longest common subsequence of rendered[i:]
+// and template[j:] for every i and j.
+// Storing all rows would need memory proportional to the number of compared
+// line pairs, so only every step-th row is kept and rows in between are
+// recomputed, one block at a time, when requested.
+type lcsTable struct {
+	isEqual     func(i, j int) bool
+	checkpoints [][]int
+	block       [][]int
+	n, m        int
+	step        int
+	blockStart  int
+}
+
+func newLCSTable(n, m int, isEqual func(i, j int) bool) *lcsTable {
+	t := lcsTable{
+		isEqual:    isEqual,
+		n:          n,
+		m:          m,
+		step:       max(1, int(math.Sqrt(float64(n)))),
+		blockStart: -1,
+	}
+	t.checkpoints = make([][]int, n/t.step+1)
+	row, next := make([]int, m+1), make([]int, m+1)
+	for i := n - 1; i >= 0; i-- {
+		t.fill(i, next, row)
+		if i%t.step == 0 {
+			t.checkpoints[i/t.step] = slices.Clone(row)
+		}
+		row, next = next, row
+	}
+	return &t
+}
+
+func (t *lcsTable) fill(i int, next, row []int) {
+	row[t.m] = 0
+	for j := t.m - 1; j >= 0; j-- {
+		if t.isEqual(i, j) {
+			row[j] = next[j+1] + 1
+		} else {
+			row[j] = max(next[j], row[j+1])
+		}
+	}
+}
+
+// rows returns rows i and i+1 of the table, i must be lower than n.
+func (t *lcsTable) rows(i int) (cur, next []int) {
+	start := i - i%t.step
+	if start != t.blockStart {
+		end := min(start+t.step, t.n)
+		if t.block == nil {
+			t.block = make([][]int, t.step+1)
+			for k := range t.block {
+				t.block[k] = make([]int, t.m+1)
+			}
+		}
+		if end == t.n {
+			clear(t.block[end-start])
+		} else {
+			copy(t.block[end-start], t.checkpoints[end/t.step])
+		}
+		for k := end - 1; k >= start; k-- {
+			t.fill(k, t.block[k-start+1], t.block[k-start])
+		}
+		t.blockStart = start
+	}
+	return t.block[i-start], t.block[i-start+1]
+}
+
+// fillGap places pending rendered lines on template lines between first (inclusive) and last (exclusive).
+func fillGap(mapping, pending []int, template []string, first, last int) bool {
+	if len(pending) > last-first {
+		return false
+	}
+
+	var actions []int
+	for j := first; j < last; j++ {
+		if strings.Contains(template[j], "{{") {
+			actions = append(actions, j)
+		}
+	}
+
+	for k, i := range pending {
+		if len(actions) >= len(pending) {
+			mapping[i] = actions[k]
+		} else {
+			mapping[i] = first + k
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/diags"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestRenderFinder(t *testing.T) {
+	type resultT struct {
+		path  string
+		name  string
+		lines diags.LineRange
+		err   string
+	}
+
+	type testCaseT struct {
+		files     map[string]string
+		renderer  discovery.Renderer
+		output    string
+		outputErr error
+		err       string
+		results   []resultT
+	}
+
+	helmTemplate := `{{- if .Values.rules.enabled }}
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: {{ .Release.Name }}-rules
+  # Team owning this chart.
+  labels:
+    {{- include "chart.labels" . | nindent 4 }}
+spec:
+  groups:
+  - name: example
+    rules:
+    - record: job:up:sum
+      expr: sum(up) by (job)
+    - alert: {{ .Values.rules.alertName }}
+      expr: up{job="{{ .Values.job }}"} == 0
+      for: {{ .Values.rules.for }}
+{{- end }}
+`
+
+	testCases := []testCaseT{
+		{
+			renderer:  discovery.Renderer{Kind: discovery.HelmRenderer, Path: "chart", Command: []string{"helm", "template", "chart"}},
+			outputErr: errors.New("Error: Chart.yaml file is missing"),
+			err:       "failed to render chart using helm: Error: Chart.yaml file is missing",
+		},
+		{
+			files: map[string]string{
+				"chart/templates/rules.yaml": helmTemplate,
+			},
+			renderer: discovery.Renderer{Kind: discovery.HelmRenderer, Path: "chart", Command: []string{"helm", "template", "chart"}},
+			output: `---
+# Source: mychart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+---
+# Source: mychart/templates/rules.yaml
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: release-name-rules
+  labels:
+    app: foo
+    team: bar
+spec:
+  groups:
+  - name: example
+    rules:
+    - record: job:up:sum
+      expr: sum(up) by (job)
+    - alert: TargetDown
+      expr: up{job="foo"} == 0
+      for: 5m
+`,
+			results: []resultT{
+				{path: "chart/templates/rules.yaml", name: "job:up:sum", lines: diags.LineRange{First: 13, Last: 14}},
+				{path: "chart/templates/rules.yaml", name: "TargetDown", lines: diags.LineRange{First: 15, Last: 17}},
+			},
+		},
+		{
+			files: map[string]string{
+				"chart/templates/rules.yaml": `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: rules
+spec:
+  groups:
+  - name: example
+    rules:
+{{ .Values.rules | toYaml | indent 4 }}
+`,
+			},
+			renderer: discovery.Renderer{Kind: discovery.HelmRenderer, Path: "chart", Command: []string{"helm", "template", "chart"}},
+			output: `---
+# Source: mychart/templates/rules.yaml
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: rules
+spec:
+  groups:
+  - name: example
+    rules:
+    - record: job:up:sum
+      expr: sum(up) by (job)
+`,
+			results: []resultT{
+				{path: "chart/templates/rules.yaml (rendered)", name: "job:up:sum", lines: diags.LineRange{First: 9, Last: 10}},
+			},
+		},
+		{
+			renderer: discovery.Renderer{Kind: discovery.HelmRenderer, Path: "chart", Command: []string{"helm", "template", "chart"}},
+			output: `---
+# Source: mychart/charts/sub/templates/rules.yaml
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: rules
+spec:
+  groups:
+  - name: example
+    rules:
+    - record: job:up:sum
+      expr: sum(up) by (job)
+`,
+			results: []resultT{
+				{path: "chart (rendered)", name: "job:up:sum", lines: diags.LineRange{First: 9, Last: 10}},
+			},
+		},
+		{
+			files: map[string]string{
+				"base/rules.yaml": `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: rules
+spec:
+  groups:
+  - name: example
+    rules:
+    - alert: TargetDown
+      expr: up == 0
+      for: 1m
+`,
+			},
+			renderer: discovery.Renderer{Kind: discovery.KustomizeRenderer, Path: "overlay", Command: []string{"kustomize", "build", "overlay"}},
+			output: `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  annotations:
+    config.kubernetes.io/origin: |
+      path: ../base/rules.yaml
+  name: prod-rules
+  namespace: prod
+spec:
+  groups:
+  - name: example
+    rules:
+    - alert: TargetDown
+      expr: up == 0
+      for: 5m
+`,
+			results: []resultT{
+				{path: "base/rules.yaml", name: "TargetDown", lines: diags.LineRange{First: 9, Last: 11}},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			require.NoError(t, os.Mkdir("overlay", 0o755))
+			for path, content := range tc.files {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+			}
+
+			cmd := func(_ context.Context, name string, args ...string) ([]byte, error) {
+				require.Equal(t, tc.renderer.Command, append([]string{name}, args...))
+				return []byte(tc.output), tc.outputErr
+			}
+			entries, err := discovery.NewRenderFinder(
+				[]discovery.Renderer{tc.renderer},
+				cmd,
+				parser.DefaultOptions,
+				nil,
+			).Find(t.Context())
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			results := make([]resultT, 0, len(entries))
+			for _, entry := range entries {
+				require.Equal(t, discovery.Noop, entry.State)
+				require.Equal(t, entry.Path.Name, entry.Path.SymlinkTarget)
+				r := resultT{
+					path:  entry.Path.Name,
+					name:  entry.Rule.Name(),
+					lines: entry.Rule.Lines,
+				}
+				if entry.PathError != nil {
+					r = resultT{path: entry.Path.Name, err: entry.PathError.Error()}
+				}
+				results = append(results, r)
+			}
+			require.Equal(t, tc.results, results)
+		})
+	}
+}
@@ -82,6 +82,7 @@ func addSymlinkedEntries(entries []*Entry) []*Entry {
 					},
 					Changes:        entry.Changes,
 					Rule:           entry.Rule,
+					Before:         entry.Before,
 					Owner:          entry.Owner,
 					DisabledChecks: entry.DisabledChecks,
 				})
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/cloudflare/pint/internal/diags"
+)
+
+const (
+	KubernetesAPIVersion = "monitoring.coreos.com/v1"
+	KubernetesKind       = "PrometheusRule"
+)
+
+// KubernetesObject holds metadata of the PrometheusRule object a group was defined in.
+type KubernetesObject struct {
+	Labels    *YamlMap
+	Namespace *YamlNode
+	Name      YamlNode
+	Owner     string // Value of the owner label, only set if KubernetesOwnerLabel option is set.
+}
+
+// Label returns the value of a metadata label with given name.
+func (ko *KubernetesObject) Label(name string) (string, bool) {
+	if ko == nil || ko.Labels == nil {
+		return "", false
+	}
+	if val := ko.Labels.GetValue(name); val != nil {
+		return val.Value, true
+	}
+	return "", false
+}
+
+// parseKubernetesObject parses a single YAML document with a PrometheusRule object.
+// Each object is its own rule namespace, so group names only need to be unique within it.
+// Documents with other kinds of objects are ignored.
+func (p Parser) parseKubernetesObject(doc *yaml.Node, contentLines []string) (groups []Group, _ ParseError) {
+	var apiVersion, kind, metadata, spec *yamlMap
+
+	for _, node := range unpackNodes(doc) {
+		if !isTag(node.ShortTag(), mapTag) {
+			return nil, ParseError{
+				Line: node.Line,
+				Err:  fmt.Errorf("kubernetes object must be a %s, got %s", describeTag(mapTag), describeTag(node.ShortTag())),
+			}
+		}
+		for _, entry := range mappingNodes(node) {
+			switch entry.key.Value {
+			case "apiVersion":
+				apiVersion = &entry
+			case "kind":
+				kind = &entry
+			case "metadata":
+				metadata = &entry
+			case "spec":
+				spec = &entry
+			}
+		}
+	}
+
+	if apiVersion == nil && kind == nil && metadata == nil && spec == nil {
+		// Empty document, usually a result of a trailing --- separator.
+		return nil, ParseError{}
+	}
+
+	if kind == nil {
+		return nil, ParseError{
+			Line: doc.Line,
+			Err:  errors.New("kubernetes object is missing kind"),
+		}
+	}
+	if nodeValue(kind.val) != KubernetesKind {
+		return nil, ParseError{}
+	}
+
+	if apiVersion == nil {
+		return nil, ParseError{
+			Line: kind.key.Line,
+			Err:  fmt.Errorf("%s object is missing apiVersion", KubernetesKind),
+		}
+	}
+	if val := nodeValue(apiVersion.val); val != KubernetesAPIVersion {
+		return nil, ParseError{
+			Line: apiVersion.key.Line,
+			Err:  fmt.Errorf("unsupported %s apiVersion %s, expected %s", KubernetesKind, val, KubernetesAPIVersion),
+		}
+	}
+
+	if metadata == nil {
+		return nil, ParseError{
+			Line: kind.key.Line,
+			Err:  fmt.Errorf("%s object is missing metadata", KubernetesKind),
+		}
+	}
+	obj, err := p.parseKubernetesMetadata(*metadata, contentLines)
+	if err.Err != nil {
+		return nil, err
+	}
+
+	if spec == nil {
+		return nil, ParseError{
+			Line: kind.key.Line,
+			Err:  fmt.Errorf("%s object is missing spec", KubernetesKind),
+		}
+	}
+	if !isTag(resolveNode(spec.val).ShortTag(), mapTag) {
+		return nil, ParseError{
+			Line: spec.key.Line,
+			Err:  fmt.Errorf("spec must be a %s, got %s", describeTag(mapTag), describeTag(resolveNode(spec.val).ShortTag())),
+		}
+	}
+
+	names := map[string]struct{}{}
+	for _, entry := range mappingNodes(resolveNode(spec.val)) {
+		if entry.key.Value != "groups" {
+			return nil, ParseError{
+				Line: entry.key.Line,
+				Err:  fmt.Errorf("unexpected spec key %s", entry.key.Value),
+			}
+		}
+		g, err := p.parseGroupList(entry, names, 0, 0, contentLines)
+		if err.Err != nil {
+			return nil, err
+		}
+		groups = append(groups, g...)
+	}
+
+	for i := range groups {
+		groups[i].Kubernetes = obj
+	}
+	return groups, ParseError{}
+}
+
+func (p Parser) parseKubernetesMetadata(metadata yamlMap, contentLines []string) (*KubernetesObject, ParseError) {
+	node := resolveNode(metadata.val)
+	if !isTag(node.ShortTag(), mapTag) {
+		return nil, ParseError{
+			Line: metadata.key.Line,
+			Err:  fmt.Errorf("metadata must be a %s, got %s", describeTag(mapTag), describeTag(node.ShortTag())),
+		}
+	}
+
+	var obj KubernetesObject
+	for _, entry := range mappingNodes(node) {
+		switch entry.key.Value {
+		case "name", "namespace":
+			resolved := resolveNode(entry.val)
+			if resolved.Kind != yaml.ScalarNode || resolved.ShortTag() != strTag {
+				return nil, ParseError{
+					Line: entry.key.Line,
+					Err:  fmt.Errorf("metadata %s must be a %s, got %s", entry.key.Value, describeTag(strTag), describeTag(resolved.ShortTag())),
+				}
+			}
+			yn := YamlNode{
+				Value: nodeValue(entry.val),
+				Pos:   diags.NewPositionRange(contentLines, entry.val, 1),
+			}
+			if entry.key.Value == "name" {
+				obj.Name = yn
+			} else {
+				obj.Namespace = &yn
+			}
+		case "labels":
+			resolved := resolveNode(entry.val)
+			if resolved.ShortTag() != mapTag {
+				return nil, ParseError{
+					Line: entry.key.Line,
+					Err:  fmt.Errorf("metadata labels must be a %s, got %s", describeTag(mapTag), describeTag(resolved.ShortTag())),
+				}
+			}
+			nodes := mappingNodes(resolved)
+			if ok, err, _ := validateStringMap(
+				"metadata labels",
+				nodes,
+				0,
+				diags.LineRange{First: entry.key.Line, Last: rangeFromYamlMaps(nodes).Last},
+			); !ok {
+				return nil, err
+			}
+			obj.Labels = newYamlMap(entry.key, resolved, 0, 0, contentLines)
+		}
+	}
+
+	if obj.Name.Value == "" {
+		return nil, ParseError{
+			Line: metadata.key.Line,
+			Err:  errors.New("metadata name is required and cannot be empty"),
+		}
+	}
+
+	if p.opts.KubernetesOwnerLabel != "" {
+		obj.Owner, _ = obj.Label(p.opts.KubernetesOwnerLabel)
+	}
+
+	return &obj, ParseError{}
+}
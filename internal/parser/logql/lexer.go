@@ -0,0 +1,353 @@
+package logql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType uint8
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenDuration
+	tokenBytes
+	tokenFlag
+	tokenLeftBrace
+	tokenRightBrace
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBracket
+	tokenRightBracket
+	tokenComma
+	tokenDot
+	tokenEq
+	tokenNeq
+	tokenRegexpEq
+	tokenRegexpNeq
+	tokenPipe
+	tokenPipeExact
+	tokenPipeMatch
+	tokenPipePattern
+	tokenNotPattern
+	tokenCmpEq
+	tokenGtr
+	tokenGte
+	tokenLss
+	tokenLte
+	tokenAdd
+	tokenSub
+	tokenMul
+	tokenDiv
+	tokenMod
+	tokenPow
+)
+
+var tokenNames = map[tokenType]string{
+	tokenEOF:          "end of input",
+	tokenIdentifier:   "identifier",
+	tokenString:       "string",
+	tokenNumber:       "number",
+	tokenDuration:     "duration",
+	tokenBytes:        "bytes",
+	tokenFlag:         "flag",
+	tokenLeftBrace:    `"{"`,
+	tokenRightBrace:   `"}"`,
+	tokenLeftParen:    `"("`,
+	tokenRightParen:   `")"`,
+	tokenLeftBracket:  `"["`,
+	tokenRightBracket: `"]"`,
+	tokenComma:        `","`,
+	tokenDot:          `"."`,
+	tokenEq:           `"="`,
+	tokenNeq:          `"!="`,
+	tokenRegexpEq:     `"=~"`,
+	tokenRegexpNeq:    `"!~"`,
+	tokenPipe:         `"|"`,
+	tokenPipeExact:    `"|="`,
+	tokenPipeMatch:    `"|~"`,
+	tokenPipePattern:  `"|>"`,
+	tokenNotPattern:   `"!>"`,
+	tokenCmpEq:        `"=="`,
+	tokenGtr:          `">"`,
+	tokenGte:          `">="`,
+	tokenLss:          `"<"`,
+	tokenLte:          `"<="`,
+	tokenAdd:          `"+"`,
+	tokenSub:          `"-"`,
+	tokenMul:          `"*"`,
+	tokenDiv:          `"/"`,
+	tokenMod:          `"%"`,
+	tokenPow:          `"^"`,
+}
+
+func (tt tokenType) String() string {
+	return tokenNames[tt]
+}
+
+type token struct {
+	val   string
+	start int
+	end   int
+	typ   tokenType
+}
+
+func (t token) String() string {
+	switch t.typ {
+	case tokenEOF:
+		return t.typ.String()
+	case tokenIdentifier, tokenNumber, tokenDuration, tokenBytes, tokenFlag:
+		return fmt.Sprintf("%s %q", t.typ, t.val)
+	case tokenString:
+		return fmt.Sprintf("%s %s", t.typ, t.val)
+	default:
+		return t.typ.String()
+	}
+}
+
+// lexError is returned when the input contains something we cannot turn into a token.
+type lexError struct {
+	msg   string
+	start int
+	end   int
+}
+
+func (le lexError) Error() string {
+	return le.msg
+}
+
+// lex splits LogQL query into a list of tokens, the last token is always tokenEOF.
+func lex(input string) (tokens []token, err error) {
+	var pos int
+	for {
+		for pos < len(input) {
+			r, size := utf8.DecodeRuneInString(input[pos:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			pos += size
+		}
+		if pos >= len(input) {
+			tokens = append(tokens, token{typ: tokenEOF, start: pos, end: pos})
+			return tokens, nil
+		}
+
+		if input[pos] == '#' {
+			for pos < len(input) && input[pos] != '\n' {
+				pos++
+			}
+			continue
+		}
+
+		var tok token
+		if tok, err = lexToken(input, pos); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		pos = tok.end
+	}
+}
+
+var operators = []struct {
+	val string
+	typ tokenType
+}{
+	// Longer operators must come first.
+	{val: "|=", typ: tokenPipeExact},
+	{val: "|~", typ: tokenPipeMatch},
+	{val: "|>", typ: tokenPipePattern},
+	{val: "!=", typ: tokenNeq},
+	{val: "!~", typ: tokenRegexpNeq},
+	{val: "!>", typ: tokenNotPattern},
+	{val: "=~", typ: tokenRegexpEq},
+	{val: "==", typ: tokenCmpEq},
+	{val: ">=", typ: tokenGte},
+	{val: "<=", typ: tokenLte},
+	{val: "{", typ: tokenLeftBrace},
+	{val: "}", typ: tokenRightBrace},
+	{val: "(", typ: tokenLeftParen},
+	{val: ")", typ: tokenRightParen},
+	{val: "[", typ: tokenLeftBracket},
+	{val: "]", typ: tokenRightBracket},
+	{val: ",", typ: tokenComma},
+	{val: ".", typ: tokenDot},
+	{val: "=", typ: tokenEq},
+	{val: "|", typ: tokenPipe},
+	{val: ">", typ: tokenGtr},
+	{val: "<", typ: tokenLss},
+	{val: "+", typ: tokenAdd},
+	{val: "-", typ: tokenSub},
+	{val: "*", typ: tokenMul},
+	{val: "/", typ: tokenDiv},
+	{val: "%", typ: tokenMod},
+	{val: "^", typ: tokenPow},
+}
+
+func lexToken(input string, pos int) (token, error) {
+	c := input[pos]
+	switch {
+	case c == '"' || c == '`':
+		return lexString(input, pos)
+	case isDigit(c) || (c == '.' && pos+1 < len(input) && isDigit(input[pos+1])):
+		return lexNumber(input, pos)
+	case c == '-' && strings.HasPrefix(input[pos:], "--") && pos+2 < len(input) && isIdentStart(input[pos+2]):
+		end := pos + 2
+		for end < len(input) && (isIdentChar(input[end]) || input[end] == '-') {
+			end++
+		}
+		return token{typ: tokenFlag, val: input[pos:end], start: pos, end: end}, nil
+	case isIdentStart(c):
+		end := pos
+		for end < len(input) && isIdentChar(input[end]) {
+			end++
+		}
+		return token{typ: tokenIdentifier, val: input[pos:end], start: pos, end: end}, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(input[pos:], op.val) {
+			return token{typ: op.typ, val: op.val, start: pos, end: pos + len(op.val)}, nil
+		}
+	}
+
+	r, size := utf8.DecodeRuneInString(input[pos:])
+	return token{}, lexError{
+		msg:   fmt.Sprintf("unexpected character: %q", r),
+		start: pos,
+		end:   pos + size,
+	}
+}
+
+func lexString(input string, pos int) (token, error) {
+	quote := input[pos]
+	end := pos + 1
+	for end < len(input) {
+		switch input[end] {
+		case '\\':
+			if quote == '"' {
+				end++
+			}
+		case quote:
+			end++
+			tok := token{typ: tokenString, val: input[pos:end], start: pos, end: end}
+			if _, err := strconv.Unquote(tok.val); err != nil {
+				return token{}, lexError{
+					msg:   fmt.Sprintf("invalid string %s: %s", tok.val, err),
+					start: pos,
+					end:   end,
+				}
+			}
+			return tok, nil
+		case '\n':
+			if quote == '"' {
+				return token{}, lexError{msg: "unterminated quoted string", start: pos, end: end}
+			}
+		}
+		end++
+	}
+	return token{}, lexError{msg: "unterminated quoted string", start: pos, end: len(input)}
+}
+
+// lexNumber will consume a number, a duration like 5m or 1h30m, or a bytes
+// value like 10KB.
+func lexNumber(input string, pos int) (token, error) {
+	end := pos
+	for end < len(input) && (isDigit(input[end]) || input[end] == '.') {
+		end++
+	}
+	if end < len(input) && (input[end] == 'e' || input[end] == 'E') &&
+		end+1 < len(input) && (isDigit(input[end+1]) || input[end+1] == '+' || input[end+1] == '-') {
+		end += 2
+		for end < len(input) && isDigit(input[end]) {
+			end++
+		}
+	}
+	if end >= len(input) || !isIdentStart(input[end]) {
+		if _, err := strconv.ParseFloat(input[pos:end], 64); err != nil {
+			return token{}, lexError{msg: fmt.Sprintf("bad number: %q", input[pos:end]), start: pos, end: end}
+		}
+		return token{typ: tokenNumber, val: input[pos:end], start: pos, end: end}, nil
+	}
+
+	// This is a number with a unit.
+	for end < len(input) && (isIdentChar(input[end]) || input[end] == '.') {
+		end++
+	}
+	val := input[pos:end]
+	if isDuration(val) {
+		return token{typ: tokenDuration, val: val, start: pos, end: end}, nil
+	}
+	if isBytes(val) {
+		return token{typ: tokenBytes, val: val, start: pos, end: end}, nil
+	}
+	return token{}, lexError{msg: fmt.Sprintf("bad number or duration: %q", val), start: pos, end: end}
+}
+
+var durationUnits = []string{"ns", "us", "µs", "ms", "s", "m", "h", "d", "w", "y"}
+
+func isDuration(s string) bool {
+	if s == "" {
+		return false
+	}
+	for s != "" {
+		var i int
+		for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+			i++
+		}
+		if i == 0 {
+			return false
+		}
+		s = s[i:]
+		var found bool
+		for _, unit := range durationUnits {
+			if strings.HasPrefix(s, unit) && (len(s) == len(unit) || isDigit(s[len(unit)])) {
+				s = s[len(unit):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+var bytesUnits = []string{
+	"b", "kib", "kb", "mib", "mb", "gib", "gb", "tib", "tb", "pib", "pb", "eib", "eb",
+	"ki", "k", "mi", "m", "gi", "g", "ti", "t", "pi", "p", "ei", "e",
+}
+
+func isBytes(s string) bool {
+	i := strings.IndexFunc(s, func(r rune) bool { return !isDigit(byte(r)) && r != '.' })
+	if i <= 0 {
+		return false
+	}
+	if _, err := strconv.ParseFloat(s[:i], 64); err != nil {
+		return false
+	}
+	unit := strings.ToLower(s[i:])
+	for _, u := range bytesUnits {
+		if unit == u {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
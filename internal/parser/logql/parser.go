@@ -0,0 +1,681 @@
+// Package logql implements a LogQL syntax validator used for checking
+// Loki ruler rules.
+// It doesn't build any AST, it only tells if a query is valid and, if it's not,
+// which part of it is wrong, using the same error type as the PromQL parser.
+package logql
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/promql/parser/posrange"
+)
+
+type exprKind uint8
+
+const (
+	sampleExpr exprKind = iota
+	logExpr
+)
+
+var (
+	rangeAggregations = []string{
+		"absent_over_time",
+		"avg_over_time",
+		"bytes_over_time",
+		"bytes_rate",
+		"count_over_time",
+		"first_over_time",
+		"last_over_time",
+		"max_over_time",
+		"min_over_time",
+		"quantile_over_time",
+		"rate",
+		"rate_counter",
+		"stddev_over_time",
+		"stdvar_over_time",
+		"sum_over_time",
+	}
+	// Range aggregations that require the unwrap stage.
+	unwrapAggregations = []string{
+		"avg_over_time",
+		"first_over_time",
+		"last_over_time",
+		"max_over_time",
+		"min_over_time",
+		"quantile_over_time",
+		"rate_counter",
+		"stddev_over_time",
+		"stdvar_over_time",
+		"sum_over_time",
+	}
+	// Range aggregations that cannot be used with the unwrap stage.
+	logAggregations = []string{
+		"absent_over_time",
+		"bytes_over_time",
+		"bytes_rate",
+		"count_over_time",
+	}
+	vectorAggregations = []string{
+		"approx_topk",
+		"avg",
+		"bottomk",
+		"count",
+		"max",
+		"min",
+		"sort",
+		"sort_desc",
+		"stddev",
+		"stdvar",
+		"sum",
+		"topk",
+	}
+	unwrapConversions = []string{"bytes", "duration", "duration_seconds"}
+	binaryOperators   = map[tokenType]int{
+		tokenCmpEq: 3,
+		tokenNeq:   3,
+		tokenGtr:   3,
+		tokenGte:   3,
+		tokenLss:   3,
+		tokenLte:   3,
+		tokenAdd:   4,
+		tokenSub:   4,
+		tokenMul:   5,
+		tokenDiv:   5,
+		tokenMod:   5,
+		tokenPow:   6,
+	}
+	setOperators = map[string]int{
+		"or":     1,
+		"and":    2,
+		"unless": 2,
+	}
+)
+
+// Validate checks if given query is a valid LogQL metric query that can be
+// used in Loki ruler rules.
+// Returned error, if any, is always promParser.ParseErrors.
+func Validate(expr string) (err error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		le, _ := errors.AsType[lexError](err)
+		return newParseErrors(expr, le.start, le.end, le.msg)
+	}
+
+	p := parser{tokens: tokens}
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(parseError)
+			if !ok {
+				panic(r)
+			}
+			err = newParseErrors(expr, perr.start, perr.end, perr.msg)
+		}
+	}()
+
+	start := p.peek()
+	kind := p.parseExpr(0)
+	if tok := p.peek(); tok.typ != tokenEOF {
+		p.unexpected(tok, "")
+	}
+	if kind == logExpr {
+		p.fail(start.start, p.peek().start, "log queries cannot be used in rules, only metric queries are allowed")
+	}
+	return nil
+}
+
+func newParseErrors(expr string, start, end int, msg string) promParser.ParseErrors {
+	return promParser.ParseErrors{
+		{
+			PositionRange: posrange.PositionRange{
+				Start: posrange.Pos(start),
+				End:   posrange.Pos(end),
+			},
+			Err:   errors.New(msg),
+			Query: expr,
+		},
+	}
+}
+
+type parseError struct {
+	msg   string
+	start int
+	end   int
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.typ != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isIdentifier(names ...string) bool {
+	tok := p.peek()
+	return tok.typ == tokenIdentifier && slices.Contains(names, tok.val)
+}
+
+func (p *parser) fail(start, end int, format string, args ...any) {
+	panic(parseError{msg: fmt.Sprintf(format, args...), start: start, end: end})
+}
+
+func (p *parser) unexpected(tok token, expected string) {
+	msg := "unexpected " + tok.String()
+	if expected != "" {
+		msg += ", expected " + expected
+	}
+	p.fail(tok.start, max(tok.end, tok.start+1), "%s", msg)
+}
+
+func (p *parser) expect(typ tokenType, expected string) token {
+	tok := p.next()
+	if tok.typ != typ {
+		p.unexpected(tok, expected)
+	}
+	return tok
+}
+
+func (p *parser) expectSample(kind exprKind, start, end int, context string) {
+	if kind != sampleExpr {
+		p.fail(start, end, "%s can only be used with metric queries, got a log query", context)
+	}
+}
+
+// parseExpr parses binary expressions using precedence climbing.
+func (p *parser) parseExpr(minPrec int) exprKind {
+	start := p.peek().start
+	kind := p.parseUnary()
+	for {
+		tok := p.peek()
+		prec, ok := binaryOperators[tok.typ]
+		isSet := false
+		if !ok && tok.typ == tokenIdentifier {
+			prec, isSet = setOperators[tok.val]
+			ok = isSet
+		}
+		if !ok || prec < minPrec {
+			return kind
+		}
+		p.expectSample(kind, start, tok.start, "binary operators")
+		p.next()
+		p.parseBinaryModifiers(tok, isSet)
+
+		nextPrec := prec + 1
+		if tok.typ == tokenPow {
+			// ^ is right associative.
+			nextPrec = prec
+		}
+		rhsStart := p.peek().start
+		rhs := p.parseExpr(nextPrec)
+		p.expectSample(rhs, rhsStart, p.peek().start, "binary operators")
+		kind = sampleExpr
+	}
+}
+
+func (p *parser) parseBinaryModifiers(op token, isSet bool) {
+	if p.isIdentifier("bool") {
+		tok := p.next()
+		if binaryOperators[op.typ] != 3 {
+			p.fail(tok.start, tok.end, "bool modifier can only be used on comparison operators")
+		}
+	}
+	if p.isIdentifier("on", "ignoring") {
+		p.next()
+		p.parseLabelList()
+		if p.isIdentifier("group_left", "group_right") {
+			tok := p.next()
+			if isSet {
+				p.fail(tok.start, tok.end, "no grouping allowed for %q operation", op.val)
+			}
+			if p.peek().typ == tokenLeftParen {
+				p.parseLabelList()
+			}
+		}
+	}
+}
+
+func (p *parser) parseUnary() exprKind {
+	if tok := p.peek(); tok.typ == tokenSub || tok.typ == tokenAdd {
+		p.next()
+		start := p.peek().start
+		kind := p.parseUnary()
+		p.expectSample(kind, start, p.peek().start, "unary expressions")
+		return kind
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() exprKind {
+	tok := p.peek()
+	switch tok.typ {
+	case tokenLeftParen:
+		p.next()
+		kind := p.parseExpr(0)
+		p.expect(tokenRightParen, `")"`)
+		return kind
+	case tokenNumber:
+		p.next()
+		return sampleExpr
+	case tokenLeftBrace:
+		p.parseSelector()
+		p.parsePipeline()
+		return logExpr
+	case tokenIdentifier:
+		switch {
+		case slices.Contains(rangeAggregations, tok.val):
+			p.parseRangeAggregation()
+			return sampleExpr
+		case slices.Contains(vectorAggregations, tok.val):
+			p.parseVectorAggregation()
+			return sampleExpr
+		case tok.val == "vector":
+			p.next()
+			p.expect(tokenLeftParen, `"("`)
+			p.parseNumber()
+			p.expect(tokenRightParen, `")"`)
+			return sampleExpr
+		case tok.val == "label_replace":
+			p.parseLabelReplace()
+			return sampleExpr
+		}
+		p.fail(tok.start, tok.end, "unknown function or aggregation: %s", tok.val)
+	}
+	p.unexpected(tok, "metric query")
+	return sampleExpr
+}
+
+func (p *parser) parseNumber() {
+	if p.peek().typ == tokenSub {
+		p.next()
+	}
+	p.expect(tokenNumber, "number")
+}
+
+func (p *parser) parseLabelReplace() {
+	p.next()
+	p.expect(tokenLeftParen, `"("`)
+	start := p.peek().start
+	kind := p.parseExpr(0)
+	p.expectSample(kind, start, p.peek().start, "label_replace")
+	for range 4 {
+		p.expect(tokenComma, `","`)
+		p.expect(tokenString, "string")
+	}
+	p.expect(tokenRightParen, `")"`)
+}
+
+func (p *parser) parseVectorAggregation() {
+	name := p.next()
+	hasGrouping := p.parseGrouping()
+	p.expect(tokenLeftParen, `"("`)
+	if slices.Contains([]string{"topk", "bottomk", "approx_topk"}, name.val) {
+		p.parseNumber()
+		p.expect(tokenComma, `","`)
+	}
+	start := p.peek().start
+	kind := p.parseExpr(0)
+	p.expectSample(kind, start, p.peek().start, name.val+"()")
+	p.expect(tokenRightParen, `")"`)
+	if p.isIdentifier("by", "without") {
+		if hasGrouping {
+			tok := p.peek()
+			p.fail(tok.start, tok.end, "%s() cannot have both prefix and postfix grouping", name.val)
+		}
+		p.parseGrouping()
+	}
+}
+
+func (p *parser) parseRangeAggregation() {
+	name := p.next()
+	p.expect(tokenLeftParen, `"("`)
+	if name.val == "quantile_over_time" {
+		p.parseNumber()
+		p.expect(tokenComma, `","`)
+	}
+
+	start := p.peek().start
+	var hasRange, hasUnwrap bool
+	if p.peek().typ == tokenLeftParen {
+		p.next()
+		p.parseSelector()
+		hasUnwrap = p.parsePipeline()
+		p.expect(tokenRightParen, `")"`)
+	} else {
+		p.parseSelector()
+		if p.peek().typ == tokenLeftBracket {
+			p.parseRange()
+			hasRange = true
+		}
+		hasUnwrap = p.parsePipeline()
+	}
+	if !hasRange {
+		if p.peek().typ != tokenLeftBracket {
+			p.unexpected(p.peek(), "range")
+		}
+		p.parseRange()
+	}
+	end := p.peek().start
+
+	switch {
+	case hasUnwrap && slices.Contains(logAggregations, name.val):
+		p.fail(start, end, "%s() cannot be used with the unwrap stage", name.val)
+	case !hasUnwrap && slices.Contains(unwrapAggregations, name.val):
+		p.fail(start, end, "%s() requires the unwrap stage", name.val)
+	}
+
+	p.expect(tokenRightParen, `")"`)
+	if p.isIdentifier("by", "without") {
+		tok := p.peek()
+		if !hasUnwrap {
+			p.fail(tok.start, tok.end, "grouping can only be used with %s() when unwrap stage is present", name.val)
+		}
+		p.parseGrouping()
+	}
+}
+
+func (p *parser) parseRange() {
+	p.expect(tokenLeftBracket, `"["`)
+	p.expect(tokenDuration, "duration")
+	p.expect(tokenRightBracket, `"]"`)
+	if p.isIdentifier("offset") {
+		p.next()
+		p.expect(tokenDuration, "duration")
+	}
+}
+
+func (p *parser) parseGrouping() bool {
+	if !p.isIdentifier("by", "without") {
+		return false
+	}
+	p.next()
+	p.parseLabelList()
+	return true
+}
+
+func (p *parser) parseLabelList() {
+	p.expect(tokenLeftParen, `"("`)
+	if p.peek().typ == tokenRightParen {
+		p.next()
+		return
+	}
+	for {
+		p.expect(tokenIdentifier, "label name")
+		tok := p.next()
+		switch tok.typ {
+		case tokenRightParen:
+			return
+		case tokenComma:
+			continue
+		default:
+			p.unexpected(tok, `"," or ")"`)
+		}
+	}
+}
+
+func (p *parser) parseSelector() {
+	open := p.expect(tokenLeftBrace, "stream selector")
+	var hasNonEmpty bool
+	for {
+		if p.peek().typ == tokenRightBrace {
+			break
+		}
+		name := p.expect(tokenIdentifier, "label name")
+		op := p.next()
+		var mt labels.MatchType
+		switch op.typ {
+		case tokenEq:
+			mt = labels.MatchEqual
+		case tokenNeq:
+			mt = labels.MatchNotEqual
+		case tokenRegexpEq:
+			mt = labels.MatchRegexp
+		case tokenRegexpNeq:
+			mt = labels.MatchNotRegexp
+		default:
+			p.unexpected(op, "label matching operator")
+		}
+		val := p.expect(tokenString, "string")
+		m, err := labels.NewMatcher(mt, name.val, p.unquote(val))
+		if err != nil {
+			p.fail(name.start, val.end, "invalid matcher: %s", err)
+		}
+		if (mt == labels.MatchEqual || mt == labels.MatchRegexp) && !m.Matches("") {
+			hasNonEmpty = true
+		}
+		if p.peek().typ == tokenComma {
+			p.next()
+			continue
+		}
+		break
+	}
+	closing := p.expect(tokenRightBrace, `"," or "}"`)
+	if !hasNonEmpty {
+		p.fail(open.start, closing.end, "stream selector must contain at least one equality or regexp matcher that doesn't match empty value")
+	}
+}
+
+// parsePipeline parses all pipeline stages and returns true if it had unwrap stage.
+func (p *parser) parsePipeline() (hasUnwrap bool) {
+	for {
+		switch p.peek().typ {
+		case tokenPipeExact, tokenNeq, tokenPipeMatch, tokenRegexpNeq, tokenPipePattern, tokenNotPattern:
+			p.parseLineFilter()
+		case tokenPipe:
+			p.next()
+			if p.parseStage() {
+				hasUnwrap = true
+			}
+		default:
+			return hasUnwrap
+		}
+	}
+}
+
+func (p *parser) parseLineFilter() {
+	op := p.next()
+	for {
+		p.parseLineFilterValue(op)
+		if !p.isIdentifier("or") {
+			return
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseLineFilterValue(op token) {
+	if p.isIdentifier("ip") {
+		if op.typ != tokenPipeExact && op.typ != tokenNeq {
+			tok := p.peek()
+			p.fail(tok.start, tok.end, "ip() can only be used with %q or %q line filters", "|=", "!=")
+		}
+		p.parseIP()
+		return
+	}
+	val := p.expect(tokenString, "string")
+	if op.typ == tokenPipeMatch || op.typ == tokenRegexpNeq {
+		p.checkRegexp(val)
+	}
+}
+
+func (p *parser) parseIP() {
+	p.next()
+	p.expect(tokenLeftParen, `"("`)
+	p.expect(tokenString, "string")
+	p.expect(tokenRightParen, `")"`)
+}
+
+// parseStage parses a single pipeline stage that follows the "|" token
+// and returns true if it was unwrap stage.
+func (p *parser) parseStage() bool {
+	tok := p.peek()
+	if tok.typ == tokenLeftParen {
+		p.parseLabelFilter()
+		return false
+	}
+	if tok.typ != tokenIdentifier {
+		p.unexpected(tok, "pipeline stage")
+	}
+
+	switch tok.val {
+	case "json", "logfmt":
+		p.next()
+		for p.peek().typ == tokenFlag {
+			flag := p.next()
+			if tok.val != "logfmt" || (flag.val != "--strict" && flag.val != "--keep-empty") {
+				p.fail(flag.start, flag.end, "unsupported %s flag: %s", tok.val, flag.val)
+			}
+		}
+		if p.peek().typ == tokenIdentifier {
+			p.parseLabelExtractions()
+		}
+	case "regexp":
+		p.next()
+		p.checkRegexp(p.expect(tokenString, "string"))
+	case "pattern", "line_format":
+		p.next()
+		p.expect(tokenString, "string")
+	case "unpack", "decolorize":
+		p.next()
+	case "label_format":
+		p.next()
+		for {
+			p.expect(tokenIdentifier, "label name")
+			p.expect(tokenEq, `"="`)
+			if val := p.next(); val.typ != tokenIdentifier && val.typ != tokenString {
+				p.unexpected(val, "label name or template string")
+			}
+			if p.peek().typ != tokenComma {
+				break
+			}
+			p.next()
+		}
+	case "drop", "keep":
+		p.next()
+		for {
+			p.expect(tokenIdentifier, "label name")
+			switch p.peek().typ {
+			case tokenEq, tokenNeq, tokenRegexpEq, tokenRegexpNeq:
+				op := p.next()
+				val := p.expect(tokenString, "string")
+				if op.typ == tokenRegexpEq || op.typ == tokenRegexpNeq {
+					p.checkRegexp(val)
+				}
+			}
+			if p.peek().typ != tokenComma {
+				break
+			}
+			p.next()
+		}
+	case "unwrap":
+		p.next()
+		if p.isIdentifier(unwrapConversions...) && p.tokens[p.pos+1].typ == tokenLeftParen {
+			p.next()
+			p.next()
+			p.expect(tokenIdentifier, "label name")
+			p.expect(tokenRightParen, `")"`)
+		} else {
+			p.expect(tokenIdentifier, "label name")
+		}
+		return true
+	default:
+		p.parseLabelFilter()
+	}
+	return false
+}
+
+func (p *parser) parseLabelExtractions() {
+	for {
+		p.expect(tokenIdentifier, "label name")
+		if p.peek().typ == tokenEq {
+			p.next()
+			p.expect(tokenString, "string")
+		}
+		if p.peek().typ != tokenComma {
+			return
+		}
+		p.next()
+	}
+}
+
+// parseLabelFilter parses label filter expressions like `status >= 500 and method="GET"`.
+// Filters can be joined with "and", "or", "," or whitespace, which also means "and".
+func (p *parser) parseLabelFilter() {
+	p.parseLabelFilterTerm()
+	for {
+		switch {
+		case p.isIdentifier("and", "or"), p.peek().typ == tokenComma:
+			p.next()
+			p.parseLabelFilterTerm()
+		case p.peek().typ == tokenLeftParen,
+			p.peek().typ == tokenIdentifier && !p.isIdentifier("by", "without", "offset", "unless", "bool", "on", "ignoring"):
+			p.parseLabelFilterTerm()
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) parseLabelFilterTerm() {
+	if p.peek().typ == tokenLeftParen {
+		p.next()
+		p.parseLabelFilter()
+		p.expect(tokenRightParen, `")"`)
+		return
+	}
+
+	p.expect(tokenIdentifier, "label name")
+	op := p.next()
+	switch op.typ {
+	case tokenEq, tokenNeq:
+		if p.isIdentifier("ip") {
+			p.parseIP()
+			return
+		}
+		fallthrough
+	case tokenCmpEq, tokenGtr, tokenGte, tokenLss, tokenLte:
+		if p.peek().typ == tokenSub {
+			p.next()
+		}
+		val := p.next()
+		switch val.typ {
+		case tokenString, tokenNumber, tokenDuration, tokenBytes:
+		default:
+			p.unexpected(val, "string, number, duration or bytes value")
+		}
+		if val.typ == tokenString && op.typ != tokenEq && op.typ != tokenNeq {
+			p.fail(val.start, val.end, "%s operator cannot be used with string values", op.val)
+		}
+	case tokenRegexpEq, tokenRegexpNeq:
+		p.checkRegexp(p.expect(tokenString, "string"))
+	default:
+		p.unexpected(op, "label filter operator")
+	}
+}
+
+func (p *parser) unquote(tok token) string {
+	s, err := strconv.Unquote(tok.val)
+	if err != nil {
+		p.fail(tok.start, tok.end, "invalid string: %s", err)
+	}
+	return s
+}
+
+func (p *parser) checkRegexp(tok token) {
+	if _, err := labels.NewFastRegexMatcher(p.unquote(tok)); err != nil {
+		p.fail(tok.start, tok.end, "invalid regular expression: %s", err)
+	}
+}
@@ -0,0 +1,197 @@
+package logql_test
+
+import (
+	"errors"
+	"testing"
+
+	promParser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/parser/logql"
+)
+
+func TestValidate(t *testing.T) {
+	type testCaseT struct {
+		expr  string
+		err   string
+		start int
+		end   int
+	}
+
+	testCases := []testCaseT{
+		{expr: `count_over_time({app="foo"}[5m])`},
+		{expr: `sum(rate({app="foo", env=~"prod|staging"} |= "error" [5m])) by (env) > 10`},
+		{expr: `sum by (env) (rate({app="foo"}[5m] |= "error"))`},
+		{expr: `count_over_time({app="foo"} |= "a" or "b" != "c" |~ "d.+" !~ "e" |> "<_> f" !> "g" [1h] offset 5m)`},
+		{expr: `count_over_time({app="foo"} |= ip("192.168.0.0/16") [5m])`},
+		{expr: `sum(count_over_time({app="foo"} | json | level="error" [5m]))`},
+		{expr: `sum(count_over_time({app="foo"} | logfmt --strict --keep-empty level, msg="message" | level=~"err.*" [5m]))`},
+		{expr: `sum(count_over_time({app="foo"} | json first="servers[0]", ua="request.headers[\"User-Agent\"]" [5m]))`},
+		{expr: `sum(count_over_time({app="foo"} | regexp "(?P<method>\\w+) (?P<path>[\\w|/]+)" | method="GET" [5m]))`},
+		{expr: "sum(count_over_time({app=\"foo\"} | pattern `<ip> - - <_> \"<method> <uri> <_>\"` [5m]))"},
+		{expr: `sum(count_over_time({app="foo"} | unpack | decolorize | line_format "{{.msg}}" | label_format dst=src, tpl="{{.a}}" [5m]))`},
+		{expr: `sum(count_over_time({app="foo"} | drop level, method="GET" | keep path [5m]))`},
+		{expr: `sum(count_over_time({app="foo"} | logfmt | status >= 500 and duration > 1.5s or size <= 20KB, (method="GET" path!="/") [5m]))`},
+		{expr: `sum(count_over_time({app="foo"} | logfmt | status >= 500 method="GET" [5m]))`},
+		{expr: `sum(count_over_time({app="foo"} | logfmt | addr = ip("10.0.0.0/8") [5m]))`},
+		{expr: `quantile_over_time(0.99, {app="foo"} | logfmt | unwrap duration(latency) | __error__="" [5m]) by (path)`},
+		{expr: `sum_over_time({app="foo"} | logfmt | unwrap bytes [5m])`},
+		{expr: `avg_over_time(({app="foo"} | logfmt | unwrap latency)[5m])`},
+		{expr: `topk(10, sum(rate({app="foo"}[5m])) by (path))`},
+		{expr: `sum(rate({app="foo"}[5m])) / on (env) group_left (team) sum(rate({app="bar"}[5m])) > bool 0.5`},
+		{expr: `sum(rate({app="foo"}[5m])) or vector(0)`},
+		{expr: `-sum(rate({app="foo"}[5m])) + 2 ^ 2 ^ 3 * 4 % 5 - 1e3`},
+		{expr: `label_replace(rate({app="foo"}[5m]), "dst", "$1", "src", "(.*)")`},
+		{expr: `absent_over_time({app="foo"}[5m]) == 1`},
+		{expr: "sum(rate({app=\"foo\"} # comment\n |= \"x\" [5m]))"},
+		{
+			expr:  `{app="foo"} |= "error"`,
+			err:   "log queries cannot be used in rules, only metric queries are allowed",
+			start: 0,
+			end:   22,
+		},
+		{
+			expr:  `sum(rate({app="foo"}[5m])) or {app="bar"}`,
+			err:   "binary operators can only be used with metric queries, got a log query",
+			start: 30,
+			end:   41,
+		},
+		{
+			expr:  `count_over_time({app=~".*"}[5m])`,
+			err:   "stream selector must contain at least one equality or regexp matcher that doesn't match empty value",
+			start: 16,
+			end:   27,
+		},
+		{
+			expr:  `count_over_time({}[5m])`,
+			err:   "stream selector must contain at least one equality or regexp matcher that doesn't match empty value",
+			start: 16,
+			end:   18,
+		},
+		{
+			expr:  `count_over_time({app="foo"})`,
+			err:   `unexpected ")", expected range`,
+			start: 27,
+			end:   28,
+		},
+		{
+			expr:  `count_over_time({app="foo"}[5])`,
+			err:   `unexpected number "5", expected duration`,
+			start: 28,
+			end:   29,
+		},
+		{
+			expr:  `sum_over_time({app="foo"}[5m])`,
+			err:   "sum_over_time() requires the unwrap stage",
+			start: 14,
+			end:   29,
+		},
+		{
+			expr:  `count_over_time({app="foo"} | unwrap x [5m])`,
+			err:   "count_over_time() cannot be used with the unwrap stage",
+			start: 16,
+			end:   43,
+		},
+		{
+			expr:  `rate({app="foo"}[5m]) by (app)`,
+			err:   "grouping can only be used with rate() when unwrap stage is present",
+			start: 22,
+			end:   24,
+		},
+		{
+			expr:  `sum by (a) (rate({app="foo"}[5m])) by (b)`,
+			err:   "sum() cannot have both prefix and postfix grouping",
+			start: 35,
+			end:   37,
+		},
+		{
+			expr:  `rat({app="foo"}[5m])`,
+			err:   "unknown function or aggregation: rat",
+			start: 0,
+			end:   3,
+		},
+		{
+			expr:  `rate({app="foo"} |~ "(" [5m])`,
+			err:   "invalid regular expression: error parsing regexp: missing closing ): `(`",
+			start: 20,
+			end:   23,
+		},
+		{
+			expr:  `rate({app="foo"} | logfmt | level > "error" [5m])`,
+			err:   "> operator cannot be used with string values",
+			start: 36,
+			end:   43,
+		},
+		{
+			expr:  `rate({app="foo"} | json --strict [5m])`,
+			err:   "unsupported json flag: --strict",
+			start: 24,
+			end:   32,
+		},
+		{
+			expr:  `rate({app="foo"} | [5m])`,
+			err:   `unexpected "[", expected pipeline stage`,
+			start: 19,
+			end:   20,
+		},
+		{
+			expr:  `rate({app="foo"} |~ ip("1.2.3.4") [5m])`,
+			err:   `ip() can only be used with "|=" or "!=" line filters`,
+			start: 20,
+			end:   22,
+		},
+		{
+			expr:  `rate({app="foo} [5m])`,
+			err:   "unterminated quoted string",
+			start: 10,
+			end:   21,
+		},
+		{
+			expr:  `rate({app="foo"}[5m]) > bool`,
+			err:   `unexpected end of input, expected metric query`,
+			start: 28,
+			end:   29,
+		},
+		{
+			expr:  `rate({app="foo"}[5m]) or bool rate({app="bar"}[5m])`,
+			err:   `bool modifier can only be used on comparison operators`,
+			start: 25,
+			end:   29,
+		},
+		{
+			expr:  `rate({app="foo"}[5m]) and on (a) group_left rate({app="bar"}[5m])`,
+			err:   `no grouping allowed for "and" operation`,
+			start: 33,
+			end:   43,
+		},
+		{
+			expr:  `rate({app="foo"}[5m]) )`,
+			err:   `unexpected ")"`,
+			start: 22,
+			end:   23,
+		},
+		{
+			expr:  `rate({app="foo"}[5m]) @ 5`,
+			err:   `unexpected character: '@'`,
+			start: 22,
+			end:   23,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			err := logql.Validate(tc.expr)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			perrs, ok := errors.AsType[promParser.ParseErrors](err)
+			require.True(t, ok, "error must be promParser.ParseErrors")
+			require.Len(t, perrs, 1)
+			require.Equal(t, tc.err, perrs[0].Err.Error())
+			require.Equal(t, tc.start, int(perrs[0].PositionRange.Start), "wrong start position")
+			require.Equal(t, tc.end, int(perrs[0].PositionRange.End), "wrong end position")
+		})
+	}
+}
@@ -123,6 +123,13 @@ type YamlInt struct {
 	Value      int
 }
 
+type YamlBool struct {
+	ParseError error
+	Raw        string
+	Pos        diags.PositionRanges
+	Value      bool
+}
+
 func newYamlDuration(node *yaml.Node, offsetLine, offsetColumn int, contentLines []string, minColumn int) *YamlDuration {
 	pos := diags.NewPositionRange(contentLines, node, minColumn)
 	pos.AddOffset(offsetLine, offsetColumn)
@@ -159,6 +166,36 @@ func newYamlInt(node *yaml.Node, offsetLine, offsetColumn int, contentLines []st
 	return &yi
 }
 
+func newYamlBool(node *yaml.Node, offsetLine, offsetColumn int, contentLines []string, minColumn int) *YamlBool {
+	pos := diags.NewPositionRange(contentLines, node, minColumn)
+	pos.AddOffset(offsetLine, offsetColumn)
+	yb := YamlBool{
+		ParseError: nil,
+		Raw:        nodeValue(node),
+		Pos:        pos,
+		Value:      false,
+	}
+	val, err := strconv.ParseBool(nodeValue(node))
+	if err != nil {
+		yb.ParseError = err
+	} else {
+		yb.Value = val
+	}
+	return &yb
+}
+
+func newYamlNodeList(node *yaml.Node, offsetLine, offsetColumn int, contentLines []string) (nodes []YamlNode) {
+	for _, n := range unpackNodes(node) {
+		pos := diags.NewPositionRange(contentLines, n, 1)
+		pos.AddOffset(offsetLine, offsetColumn)
+		nodes = append(nodes, YamlNode{
+			Value: nodeValue(n),
+			Pos:   pos,
+		})
+	}
+	return nodes
+}
+
 type YamlKeyValue struct {
 	Key   *YamlNode
 	Value *YamlNode
@@ -257,6 +294,7 @@ func newPromQLExpr(node *yaml.Node, offsetLine, offsetColumn int, contentLines [
 		mu:          &sync.Mutex{},
 		source:      nil,
 		hasSource:   false,
+		isLogQL:     false,
 	}
 }
 
@@ -335,6 +373,7 @@ func (pe ParseError) Error() string {
 }
 
 type File struct {
+	Namespace   *YamlNode // Only set when using Mimir schema.
 	Diagnostics []diags.Diagnostic
 	Comments    []comments.Comment
 	Groups      []Group
@@ -349,9 +388,15 @@ type Group struct {
 	Interval    *YamlDuration
 	QueryOffset *YamlDuration
 	Limit       *YamlInt
-	Name        YamlNode
-	Rules       []Rule
-	Error       ParseError
+	// Fields below are only set when using Mimir schema.
+	EvaluationDelay               *YamlDuration
+	AlignEvaluationTimeOnInterval *YamlBool
+	// Only set when parsing PrometheusRule manifests.
+	Kubernetes    *KubernetesObject
+	Name          YamlNode
+	SourceTenants []YamlNode
+	Rules         []Rule
+	Error         ParseError
 }
 
 type Rule struct {
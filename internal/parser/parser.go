@@ -33,18 +33,28 @@ type Schema uint8
 const (
 	PrometheusSchema Schema = iota
 	ThanosSchema
+	LokiSchema
+	MimirSchema
 )
 
 type Options struct {
-	Names    model.ValidationScheme
-	Schema   Schema
-	IsStrict bool
+	Loki                 []*regexp.Regexp
+	Kubernetes           []*regexp.Regexp
+	KubernetesOwnerLabel string
+	Names                model.ValidationScheme
+	Schema               Schema
+	IsStrict             bool
+	IsKubernetes         bool
 }
 
 var DefaultOptions = Options{
-	Names:    model.UTF8Validation,
-	Schema:   PrometheusSchema,
-	IsStrict: false,
+	Loki:                 nil,
+	Kubernetes:           nil,
+	KubernetesOwnerLabel: "",
+	Names:                model.UTF8Validation,
+	Schema:               PrometheusSchema,
+	IsStrict:             false,
+	IsKubernetes:         false,
 }
 
 func (o Options) WithStrict(strict bool) Options {
@@ -52,6 +62,30 @@ func (o Options) WithStrict(strict bool) Options {
 	return o
 }
 
+func (o Options) WithKubernetes(kubernetes bool) Options {
+	o.IsKubernetes = kubernetes
+	return o
+}
+
+// ForPath returns options to use when parsing given file.
+// Files matching any of the Loki patterns are parsed using the Loki schema.
+// Files matching any of the Kubernetes patterns are parsed as PrometheusRule manifests.
+func (o Options) ForPath(path string) Options {
+	for _, re := range o.Loki {
+		if re.MatchString(path) {
+			o.Schema = LokiSchema
+			break
+		}
+	}
+	for _, re := range o.Kubernetes {
+		if re.MatchString(path) {
+			o.IsKubernetes = true
+			break
+		}
+	}
+	return o
+}
+
 func NewParser(opts Options) Parser {
 	if opts.Names == model.UnsetValidation {
 		opts.Names = model.LegacyValidation
@@ -73,7 +107,7 @@ func (p Parser) Parse(src io.Reader) (f File) {
 		f.TotalLines = cr.lineno
 	}()
 
-	f.IsRelaxed = !p.opts.IsStrict
+	f.IsRelaxed = !p.opts.IsStrict && !p.opts.IsKubernetes
 
 	var index int
 	var g []Group
@@ -90,8 +124,18 @@ func (p Parser) Parse(src io.Reader) (f File) {
 		}
 		index++
 
+		if p.opts.IsKubernetes {
+			// Each document is a separate object, so multi-document files are fine here.
+			g, f.Error = p.parseKubernetesObject(&doc, cr.lines)
+			if f.Error.Err != nil {
+				return f
+			}
+			f.Groups = append(f.Groups, g...)
+			continue
+		}
+
 		if p.opts.IsStrict {
-			g, f.Error = p.parseGroups(&doc, 0, 0, cr.lines)
+			g, f.Namespace, f.Error = p.parseGroups(&doc, 0, 0, cr.lines)
 			if f.Error.Err != nil {
 				return f
 			}
@@ -194,6 +238,14 @@ func tryParseGroup(node *yaml.Node, offsetLine, offsetColumn int, contentLines [
 			g.Limit = newYamlInt(e.val, offsetLine, offsetColumn, contentLines, 1)
 		case "query_offset":
 			g.QueryOffset = newYamlDuration(e.val, offsetLine, offsetColumn, contentLines, 1)
+		case "evaluation_delay":
+			g.EvaluationDelay = newYamlDuration(e.val, offsetLine, offsetColumn, contentLines, 1)
+		case "align_evaluation_time_on_interval":
+			g.AlignEvaluationTimeOnInterval = newYamlBool(e.val, offsetLine, offsetColumn, contentLines, 1)
+		case "source_tenants":
+			if e.val.Kind == yaml.SequenceNode {
+				g.SourceTenants = newYamlNodeList(e.val, offsetLine, offsetColumn, contentLines)
+			}
 		case "labels":
 			g.Labels = newYamlMap(e.key, e.val, offsetLine, offsetColumn, contentLines)
 		case "rules":
@@ -280,6 +332,7 @@ func (p Parser) parseRule(node *yaml.Node, offsetLine, offsetColumn int, content
 				}
 				exprNode = part
 				exprPart = newPromQLExpr(part, offsetLine, offsetColumn, contentLines, key.Column+2)
+				exprPart.isLogQL = p.opts.Schema == LokiSchema
 				lines.Last = max(lines.Last, exprPart.Value.Pos.Lines().Last)
 			case forKey:
 				if forPart != nil {
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"errors"
 	"os"
+	"regexp"
 	"strconv"
 	"testing"
 	"time"
@@ -7530,6 +7531,333 @@ data:
 				},
 			},
 		},
+		{
+			input: []byte(`
+namespace: myns
+groups:
+- name: mygroup
+  source_tenants: [team-a, team-b]
+  evaluation_delay: 1m
+  align_evaluation_time_on_interval: true
+  rules:
+  - record: up:count
+    expr: count(up)
+`),
+			strict: true,
+			schema: parser.MimirSchema,
+			output: parser.File{
+				Namespace: &parser.YamlNode{
+					Value: "myns",
+					Pos: diags.PositionRanges{
+						{Line: 2, FirstColumn: 12, LastColumn: 15},
+					},
+				},
+				Groups: []parser.Group{
+					{
+						Name: parser.YamlNode{
+							Value: "mygroup",
+							Pos: diags.PositionRanges{
+								{Line: 4, FirstColumn: 9, LastColumn: 15},
+							},
+						},
+						SourceTenants: []parser.YamlNode{
+							{
+								Value: "team-a",
+								Pos: diags.PositionRanges{
+									{Line: 5, FirstColumn: 20, LastColumn: 25},
+								},
+							},
+							{
+								Value: "team-b",
+								Pos: diags.PositionRanges{
+									{Line: 5, FirstColumn: 28, LastColumn: 33},
+								},
+							},
+						},
+						EvaluationDelay: &parser.YamlDuration{
+							Raw:   "1m",
+							Value: time.Minute,
+							Pos: diags.PositionRanges{
+								{Line: 6, FirstColumn: 21, LastColumn: 22},
+							},
+						},
+						AlignEvaluationTimeOnInterval: &parser.YamlBool{
+							Raw:   "true",
+							Value: true,
+							Pos: diags.PositionRanges{
+								{Line: 7, FirstColumn: 38, LastColumn: 41},
+							},
+						},
+						Rules: []parser.Rule{
+							{
+								Lines: diags.LineRange{First: 9, Last: 10},
+								RecordingRule: &parser.RecordingRule{
+									Record: parser.YamlNode{
+										Value: "up:count",
+										Pos: diags.PositionRanges{
+											{Line: 9, FirstColumn: 13, LastColumn: 20},
+										},
+									},
+									Expr: parser.PromQLExpr{
+										Value: &parser.YamlNode{
+											Value: "count(up)",
+											Pos: diags.PositionRanges{
+												{Line: 10, FirstColumn: 11, LastColumn: 19},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			input: []byte(`
+namespace: myns
+groups: []
+`),
+			strict: true,
+			output: parser.File{
+				Error: parser.ParseError{
+					Err:  errors.New("unexpected key namespace"),
+					Line: 2,
+				},
+			},
+		},
+		{
+			input: []byte(`
+namespace: ""
+groups: []
+`),
+			strict: true,
+			schema: parser.MimirSchema,
+			output: parser.File{
+				Error: parser.ParseError{
+					Err:  errors.New("namespace cannot be empty"),
+					Line: 2,
+				},
+			},
+		},
+		{
+			input: []byte(`
+groups:
+- name: mygroup
+  source_tenants: [team-a]
+  rules: []
+`),
+			strict: true,
+			output: parser.File{
+				Groups: []parser.Group{
+					{
+						Name: parser.YamlNode{
+							Value: "mygroup",
+							Pos: diags.PositionRanges{
+								{Line: 3, FirstColumn: 9, LastColumn: 15},
+							},
+						},
+						Error: parser.ParseError{
+							Err:  errors.New("source_tenants is only valid when parser is configured to use the Mimir rule schema"),
+							Line: 4,
+						},
+					},
+				},
+			},
+		},
+		{
+			input: []byte(`
+groups:
+- name: mygroup
+  source_tenants: team-a
+  rules: []
+`),
+			strict: true,
+			schema: parser.MimirSchema,
+			output: parser.File{
+				Groups: []parser.Group{
+					{
+						Name: parser.YamlNode{
+							Value: "mygroup",
+							Pos: diags.PositionRanges{
+								{Line: 3, FirstColumn: 9, LastColumn: 15},
+							},
+						},
+						Error: parser.ParseError{
+							Err:  errors.New("source_tenants must be a list, got string"),
+							Line: 4,
+						},
+					},
+				},
+			},
+		},
+		{
+			input: []byte(`
+groups:
+- name: mygroup
+  source_tenants:
+  - team-a
+  - ""
+  rules: []
+`),
+			strict: true,
+			schema: parser.MimirSchema,
+			output: parser.File{
+				Groups: []parser.Group{
+					{
+						Name: parser.YamlNode{
+							Value: "mygroup",
+							Pos: diags.PositionRanges{
+								{Line: 3, FirstColumn: 9, LastColumn: 15},
+							},
+						},
+						Error: parser.ParseError{
+							Err:  errors.New("source_tenants entry cannot be empty"),
+							Line: 6,
+						},
+					},
+				},
+			},
+		},
+		{
+			input: []byte(`
+groups:
+- name: mygroup
+  evaluation_delay: 1m
+  rules: []
+`),
+			strict: true,
+			schema: parser.ThanosSchema,
+			output: parser.File{
+				Groups: []parser.Group{
+					{
+						Name: parser.YamlNode{
+							Value: "mygroup",
+							Pos: diags.PositionRanges{
+								{Line: 3, FirstColumn: 9, LastColumn: 15},
+							},
+						},
+						Error: parser.ParseError{
+							Err:  errors.New("evaluation_delay is only valid when parser is configured to use the Mimir rule schema"),
+							Line: 4,
+						},
+					},
+				},
+			},
+		},
+		{
+			input: []byte(`
+groups:
+- name: mygroup
+  evaluation_delay: 1x
+  rules: []
+`),
+			strict: true,
+			schema: parser.MimirSchema,
+			output: parser.File{
+				Groups: []parser.Group{
+					{
+						Name: parser.YamlNode{
+							Value: "mygroup",
+							Pos: diags.PositionRanges{
+								{Line: 3, FirstColumn: 9, LastColumn: 15},
+							},
+						},
+						EvaluationDelay: &parser.YamlDuration{
+							Raw:        "1x",
+							ParseError: errors.New(`unknown unit "x" in duration "1x"`),
+							Pos: diags.PositionRanges{
+								{Line: 4, FirstColumn: 21, LastColumn: 22},
+							},
+						},
+						Error: parser.ParseError{
+							Err:  errors.New(`invalid evaluation_delay value: unknown unit "x" in duration "1x"`),
+							Line: 4,
+						},
+					},
+				},
+			},
+		},
+		{
+			input: []byte(`
+groups:
+- name: mygroup
+  align_evaluation_time_on_interval: "yes"
+  rules: []
+`),
+			strict: true,
+			schema: parser.MimirSchema,
+			output: parser.File{
+				Groups: []parser.Group{
+					{
+						Name: parser.YamlNode{
+							Value: "mygroup",
+							Pos: diags.PositionRanges{
+								{Line: 3, FirstColumn: 9, LastColumn: 15},
+							},
+						},
+						Error: parser.ParseError{
+							Err:  errors.New("group align_evaluation_time_on_interval must be a bool, got string"),
+							Line: 4,
+						},
+					},
+				},
+			},
+		},
+		{
+			input: []byte(`
+namespace: myns
+groups:
+- name: mygroup
+  source_tenants: [team-a]
+  rules:
+  - record: up:count
+    expr: count(up)
+`),
+			strict: false,
+			output: parser.File{
+				IsRelaxed: true,
+				Groups: []parser.Group{
+					{
+						Name: parser.YamlNode{
+							Value: "mygroup",
+							Pos: diags.PositionRanges{
+								{Line: 4, FirstColumn: 9, LastColumn: 15},
+							},
+						},
+						SourceTenants: []parser.YamlNode{
+							{
+								Value: "team-a",
+								Pos: diags.PositionRanges{
+									{Line: 5, FirstColumn: 20, LastColumn: 25},
+								},
+							},
+						},
+						Rules: []parser.Rule{
+							{
+								Lines: diags.LineRange{First: 7, Last: 8},
+								RecordingRule: &parser.RecordingRule{
+									Record: parser.YamlNode{
+										Value: "up:count",
+										Pos: diags.PositionRanges{
+											{Line: 7, FirstColumn: 13, LastColumn: 20},
+										},
+									},
+									Expr: parser.PromQLExpr{
+										Value: &parser.YamlNode{
+											Value: "count(up)",
+											Pos: diags.PositionRanges{
+												{Line: 8, FirstColumn: 11, LastColumn: 19},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	alwaysEqual := cmp.Comparer(func(_, _ any) bool { return true })
@@ -7755,6 +8083,32 @@ groups:
 			schema:        parser.ThanosSchema,
 			names:         model.UTF8Validation,
 		},
+		{
+			name: "PromQL query with Loki schema",
+			input: []byte(`
+groups:
+- name: foo
+  rules:
+  - record: foo
+    expr: sum(rate(errors_total[5m]))
+`),
+			expectedError: `1:10: parse error: unexpected identifier "errors_total", expected stream selector`,
+			schema:        parser.LokiSchema,
+			names:         model.UTF8Validation,
+		},
+		{
+			name: "log query with Loki schema",
+			input: []byte(`
+groups:
+- name: foo
+  rules:
+  - alert: foo
+    expr: '{app="foo"} |= "error"'
+`),
+			expectedError: `1:1: parse error: log queries cannot be used in rules, only metric queries are allowed`,
+			schema:        parser.LokiSchema,
+			names:         model.UTF8Validation,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -7778,6 +8132,267 @@ groups:
 	}
 }
 
+func TestLogQLExpr(t *testing.T) {
+	input := []byte(`
+groups:
+- name: foo
+  rules:
+  - alert: foo
+    expr: sum(count_over_time({app="foo"} |= "error" [5m])) by (env) > 10
+`)
+
+	p := parser.NewParser(parser.Options{Schema: parser.LokiSchema})
+	file := p.Parse(bytes.NewReader(input))
+	require.NoError(t, file.Error.Err)
+	require.Len(t, file.Groups, 1)
+	require.Len(t, file.Groups[0].Rules, 1)
+
+	expr := file.Groups[0].Rules[0].Expr()
+	require.True(t, expr.IsLogQL())
+	require.NoError(t, expr.SyntaxError())
+	require.Nil(t, expr.Query().Expr)
+	require.Empty(t, expr.Source())
+}
+
+func TestOptionsForPath(t *testing.T) {
+	opts := parser.Options{
+		Loki:   []*regexp.Regexp{regexp.MustCompile("^loki/.*$")},
+		Schema: parser.ThanosSchema,
+	}
+	require.Equal(t, parser.ThanosSchema, opts.ForPath("rules/foo.yml").Schema)
+	require.Equal(t, parser.LokiSchema, opts.ForPath("loki/foo.yml").Schema)
+	require.Equal(t, parser.ThanosSchema, opts.Schema)
+
+	opts = parser.Options{
+		Kubernetes: []*regexp.Regexp{regexp.MustCompile("^manifests/.*$")},
+	}
+	require.False(t, opts.ForPath("rules/foo.yml").IsKubernetes)
+	require.True(t, opts.ForPath("manifests/foo.yml").IsKubernetes)
+}
+
+func TestParseKubernetes(t *testing.T) {
+	type objectT struct {
+		group     string
+		name      string
+		namespace string
+		owner     string
+		rules     int
+	}
+
+	type testCaseT struct {
+		title   string
+		input   string
+		err     string
+		objects []objectT
+		line    int
+	}
+
+	testCases := []testCaseT{
+		{
+			title: "multiple objects",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: team-a
+  namespace: monitoring
+  labels:
+    team: a
+spec:
+  groups:
+  - name: example
+    rules:
+    - record: foo:sum
+      expr: sum(foo)
+    - alert: Foo
+      expr: foo > 0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  foo: bar
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: team-b
+spec:
+  groups:
+  - name: example
+    rules:
+    - record: bar:sum
+      expr: sum(bar)
+---
+`,
+			objects: []objectT{
+				{group: "example", name: "team-a", namespace: "monitoring", owner: "a", rules: 2},
+				{group: "example", name: "team-b", rules: 1},
+			},
+		},
+		{
+			title: "duplicated group in the same object",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: team-a
+spec:
+  groups:
+  - name: example
+    rules: []
+  - name: example
+    rules: []
+`,
+			err:  "duplicated group name",
+			line: 10,
+		},
+		{
+			title: "missing kind",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+metadata:
+  name: team-a
+`,
+			err:  "kubernetes object is missing kind",
+			line: 2,
+		},
+		{
+			title: "invalid apiVersion",
+			input: `
+apiVersion: monitoring.coreos.com/v2
+kind: PrometheusRule
+metadata:
+  name: team-a
+spec:
+  groups: []
+`,
+			err:  "unsupported PrometheusRule apiVersion monitoring.coreos.com/v2, expected monitoring.coreos.com/v1",
+			line: 2,
+		},
+		{
+			title: "missing apiVersion",
+			input: `
+kind: PrometheusRule
+metadata:
+  name: team-a
+spec:
+  groups: []
+`,
+			err:  "PrometheusRule object is missing apiVersion",
+			line: 2,
+		},
+		{
+			title: "missing metadata",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+spec:
+  groups: []
+`,
+			err:  "PrometheusRule object is missing metadata",
+			line: 3,
+		},
+		{
+			title: "missing name",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  namespace: foo
+spec:
+  groups: []
+`,
+			err:  "metadata name is required and cannot be empty",
+			line: 4,
+		},
+		{
+			title: "invalid labels",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+  labels:
+    team: [a]
+spec:
+  groups: []
+`,
+			err:  "metadata labels team value must be a string, got list instead",
+			line: 7,
+		},
+		{
+			title: "missing spec",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+`,
+			err:  "PrometheusRule object is missing spec",
+			line: 3,
+		},
+		{
+			title: "invalid spec key",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+spec:
+  rules: []
+`,
+			err:  "unexpected spec key rules",
+			line: 7,
+		},
+		{
+			title: "groups must be a list",
+			input: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+spec:
+  groups: {}
+`,
+			err:  "groups value must be a list, got mapping",
+			line: 7,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			p := parser.NewParser(parser.Options{IsKubernetes: true, KubernetesOwnerLabel: "team"})
+			file := p.Parse(bytes.NewReader([]byte(tc.input)))
+			require.False(t, file.IsRelaxed)
+			if tc.err != "" {
+				require.EqualError(t, file.Error.Err, tc.err)
+				require.Equal(t, tc.line, file.Error.Line)
+				return
+			}
+			require.NoError(t, file.Error.Err)
+
+			objects := make([]objectT, 0, len(file.Groups))
+			for _, group := range file.Groups {
+				require.NoError(t, group.Error.Err)
+				require.NotNil(t, group.Kubernetes)
+				obj := objectT{
+					group: group.Name.Value,
+					name:  group.Kubernetes.Name.Value,
+					owner: group.Kubernetes.Owner,
+					rules: len(group.Rules),
+				}
+				if group.Kubernetes.Namespace != nil {
+					obj.namespace = group.Kubernetes.Namespace.Value
+				}
+				objects = append(objects, obj)
+			}
+			require.Equal(t, tc.objects, objects)
+		})
+	}
+}
+
 func BenchmarkParse(b *testing.B) {
 	data, err := os.ReadFile("testrules.yml")
 	require.NoError(b, err)
@@ -7,6 +7,7 @@ import (
 
 	promParser "github.com/prometheus/prometheus/promql/parser"
 
+	"github.com/cloudflare/pint/internal/parser/logql"
 	"github.com/cloudflare/pint/internal/parser/source"
 )
 
@@ -26,12 +27,25 @@ type PromQLExpr struct {
 	mu          *sync.Mutex
 	source      []*source.Source
 	hasSource   bool
+	isLogQL     bool
+}
+
+// IsLogQL returns true if this is a LogQL query from a Loki ruler rule.
+func (pn *PromQLExpr) IsLogQL() bool {
+	return pn.isLogQL
 }
 
 func (pn *PromQLExpr) parse() {
-	if pn.query == nil {
-		pn.query, pn.syntaxError = DecodeExpr(pn.Value.Value)
+	if pn.query != nil {
+		return
 	}
+	if pn.isLogQL {
+		// There's no PromQL tree for LogQL queries, only syntax validation.
+		pn.query = &PromQLNode{Parent: nil, Expr: nil, Children: nil}
+		pn.syntaxError = logql.Validate(pn.Value.Value)
+		return
+	}
+	pn.query, pn.syntaxError = DecodeExpr(pn.Value.Value)
 }
 
 func (pn *PromQLExpr) Source() []*source.Source {
@@ -39,7 +53,9 @@ func (pn *PromQLExpr) Source() []*source.Source {
 	defer pn.mu.Unlock()
 	pn.parse()
 	if !pn.hasSource {
-		pn.source = source.LabelsSource(pn.Value.Value, pn.query.Expr)
+		if !pn.isLogQL {
+			pn.source = source.LabelsSource(pn.Value.Value, pn.query.Expr)
+		}
 		pn.hasSource = true
 	}
 	return pn.source
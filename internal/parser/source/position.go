@@ -173,10 +173,10 @@ func findArgumentPosition(expr string, within posrange.PositionRange, name strin
 	}
 }
 
-// findBinOpsOperatorPosition returns the position of the op operator between the
+// FindBinOpsOperatorPosition returns the position of the op operator between the
 // two sides of a binary expression, searching only the gap between them so a
 // matching op inside either side is ignored.
-func findBinOpsOperatorPosition(expr string, n *promParser.BinaryExpr, op string) posrange.PositionRange {
+func FindBinOpsOperatorPosition(expr string, n *promParser.BinaryExpr, op string) posrange.PositionRange {
 	within := posrange.PositionRange{
 		Start: n.LHS.PositionRange().End + 1,
 		End:   n.RHS.PositionRange().Start,
@@ -70,6 +70,21 @@ func (rsm RangeSelectorMode) MarshalYAML() (any, error) {
 	return "default", nil
 }
 
+type HistogramType uint8
+
+const (
+	NoHistogram      HistogramType = iota // Not a histogram or we can't tell.
+	ClassicHistogram                      // Classic histogram buckets, one time series per bucket with the le label.
+)
+
+// Used for test snapshots.
+func (ht HistogramType) MarshalYAML() (any, error) {
+	if ht == ClassicHistogram {
+		return "classic", nil
+	}
+	return "", nil
+}
+
 type LabelPromiseType uint8
 
 const (
@@ -279,6 +294,8 @@ type Source struct {
 	// Labels are fixed and only allowed labels can be present.
 	FixedLabels       bool              `yaml:"fixedLabels,omitempty"`
 	RangeSelectorMode RangeSelectorMode `yaml:"rangeSelectorMode,omitempty"`
+	// Kind of histogram data returned by this source.
+	Histogram HistogramType `yaml:"histogram,omitempty"`
 }
 
 // requireFeature is called for every PromQL function, aggregation, and modifier.
@@ -541,7 +558,7 @@ func (s *Source) findLabelTransform(name string) *LabelTransform {
 }
 
 func (s *Source) checkJoinedLabels(expr string, n *promParser.BinaryExpr, dst *Source) (dead []DeadLabel) {
-	pos := findBinOpsOperatorPosition(expr, n, promParser.ItemTypeStr[n.Op])
+	pos := FindBinOpsOperatorPosition(expr, n, promParser.ItemTypeStr[n.Op])
 	for _, j := range s.Joins {
 		for _, name := range j.AddedLabels {
 			if slices.Contains(n.VectorMatching.Include, name) {
@@ -731,6 +748,9 @@ func walkNode(expr string, node promParser.Node) (src []*Source) {
 				name,
 			)
 		}
+		if strings.HasSuffix(n.Name, "_bucket") {
+			s.Histogram = ClassicHistogram
+		}
 		s.Position = n.PosRange
 		src = append(src, s)
 	}
@@ -920,6 +940,7 @@ func parsePromQLFunc(s *Source, expr string, n *promParser.Call) *Source {
 
 	case "absent", "absent_over_time":
 		s.Returns = promParser.ValueTypeVector
+		s.Histogram = NoHistogram
 		vs, _ := MostOuterOperation[*promParser.VectorSelector](s)
 		names := labelsFromSelectors([]labels.MatchType{labels.MatchEqual}, vs)
 		funcNamePos := FindFuncNamePosition(expr, n.PosRange, n.Func.Name)
@@ -980,16 +1001,28 @@ If you're hoping to get instance specific labels this way and alert when some ta
 		// No change to labels.
 		s.Returns = promParser.ValueTypeVector
 
+	case "histogram_fraction", "histogram_quantile":
+		s.Returns = promParser.ValueTypeVector
+		// Classic histogram buckets are merged into a single series, which removes the le label.
+		if s.Histogram == ClassicHistogram && s.CanHaveLabel(model.BucketLabel) {
+			s.excludeLabel(
+				fmt.Sprintf("Calling `%s()` on classic histogram buckets will remove the `%s` label from the results.",
+					n.Func.Name, model.BucketLabel),
+				FindFuncNamePosition(expr, n.PosRange, n.Func.Name),
+				model.BucketLabel,
+			)
+		}
+		s.Histogram = NoHistogram
+
 	case "histogram_avg",
 		"histogram_count",
-		"histogram_fraction",
-		"histogram_quantile",
 		"histogram_quantiles",
 		"histogram_stddev",
 		"histogram_stdvar",
 		"histogram_sum":
 		// No change to labels.
 		s.Returns = promParser.ValueTypeVector
+		s.Histogram = NoHistogram
 
 	case "double_exponential_smoothing", "holt_winters", "predict_linear":
 		// No change to labels.
@@ -1035,6 +1068,7 @@ If you're hoping to get instance specific labels this way and alert when some ta
 
 	case "min_of", "max_of", "scalar":
 		s.Returns = promParser.ValueTypeScalar
+		s.Histogram = NoHistogram
 		s.ReturnInfo.AlwaysReturns = true
 		funcPos := FindFuncPosition(expr, n.PositionRange(), n.Func.Name, nil)
 		s.excludeAllLabels(
@@ -1068,6 +1102,7 @@ If you're hoping to get instance specific labels this way and alert when some ta
 		"ts_of_min_over_time":
 		// No change to labels.
 		s.Returns = promParser.ValueTypeVector
+		s.Histogram = NoHistogram
 
 	case "info":
 		// info() joins labels from an info-series onto the input vector.
@@ -1334,7 +1369,7 @@ func parseBinOps(expr string, n *promParser.BinaryExpr) (src []*Source) {
 				})
 				if fill != nil {
 					name := promParser.ItemTypeStr[promParser.FILL]
-					ls.requireFeature(name, findBinOpsOperatorPosition(expr, n, name))
+					ls.requireFeature(name, FindBinOpsOperatorPosition(expr, n, name))
 				}
 			}
 			ls.DeadLabels = append(ls.DeadLabels, ls.checkJoinedLabels(expr, n, ls)...)
@@ -1409,7 +1444,7 @@ func parseBinOps(expr string, n *promParser.BinaryExpr) (src []*Source) {
 				})
 				if fill != nil {
 					name := promParser.ItemTypeStr[promParser.FILL]
-					rs.requireFeature(name, findBinOpsOperatorPosition(expr, n, name))
+					rs.requireFeature(name, FindBinOpsOperatorPosition(expr, n, name))
 				}
 			}
 			rs.excludeLabel("Binary operation between two vectors removes metric names.", pos, model.MetricNameLabel)
@@ -1480,7 +1515,7 @@ func parseBinOps(expr string, n *promParser.BinaryExpr) (src []*Source) {
 				})
 				if fill != nil {
 					name := promParser.ItemTypeStr[promParser.FILL]
-					ls.requireFeature(name, findBinOpsOperatorPosition(expr, n, name))
+					ls.requireFeature(name, FindBinOpsOperatorPosition(expr, n, name))
 				}
 			}
 			ls.excludeLabel("Binary operation between two vectors removes metric names.", pos, model.MetricNameLabel)
@@ -482,6 +482,11 @@ group by (cluster, namespace, workload, workload_type, pod) (
 	`ln(sum(foo{job="bar"}))`,
 	`histogram_quantile(0.9, sum(foo{job="bar"}))`,
 	`timestamp(sum(foo{job="bar"}))`,
+	`foo_bucket`,
+	`histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(le, job))`,
+	`histogram_quantile(0.9, sum(rate(foo_bucket[5m])) by(job))`,
+	`histogram_fraction(0, 0.5, rate(foo_bucket[5m]))`,
+	`histogram_count(rate(foo[5m]))`,
 }
 
 func TestLabelsSource(t *testing.T) {
@@ -13,9 +13,9 @@ import (
 // https://github.com/go-yaml/yaml/blob/v3.0.1/resolve.go#L70-L81
 const (
 	nullTag = "!!null"
-	// boolTag      = "!!bool" unused.
-	strTag = "!!str"
-	intTag = "!!int"
+	boolTag = "!!bool"
+	strTag  = "!!str"
+	intTag  = "!!int"
 	// floatTag     = "!!float" unused.
 	// timestampTag = "!!timestamp" unused.
 	seqTag    = "!!seq"
@@ -41,12 +41,12 @@ func describeTag(tag string) string {
 	}
 }
 
-func (p Parser) parseGroups(doc *yaml.Node, offsetLine, offsetColumn int, contentLines []string) (groups []Group, _ ParseError) {
+func (p Parser) parseGroups(doc *yaml.Node, offsetLine, offsetColumn int, contentLines []string) (groups []Group, namespace *YamlNode, _ ParseError) {
 	names := map[string]struct{}{}
 
 	for _, node := range unpackNodes(doc) {
 		if !isTag(node.ShortTag(), mapTag) {
-			return nil, ParseError{
+			return nil, nil, ParseError{
 				Line: node.Line,
 				Err:  fmt.Errorf("top level field must be a groups key, got %s", describeTag(node.ShortTag())),
 			}
@@ -54,45 +54,76 @@ func (p Parser) parseGroups(doc *yaml.Node, offsetLine, offsetColumn int, conten
 
 		for _, entry := range mappingNodes(node) {
 			if entry.key.ShortTag() != strTag {
-				return nil, ParseError{
+				return nil, nil, ParseError{
 					Line: entry.key.Line,
 					Err:  fmt.Errorf("groups key must be a %s, got a %s", describeTag(strTag), describeTag(entry.key.ShortTag())),
 				}
 			}
+			if entry.key.Value == "namespace" && p.opts.Schema == MimirSchema {
+				resolved := resolveNode(entry.val)
+				if resolved.Kind != yaml.ScalarNode || resolved.ShortTag() != strTag {
+					return nil, nil, ParseError{
+						Line: entry.key.Line,
+						Err:  fmt.Errorf("namespace must be a %s, got %s", describeTag(strTag), describeTag(resolved.ShortTag())),
+					}
+				}
+				if nodeValue(entry.val) == "" {
+					return nil, nil, ParseError{
+						Line: entry.key.Line,
+						Err:  errors.New("namespace cannot be empty"),
+					}
+				}
+				namespace = &YamlNode{
+					Value: nodeValue(entry.val),
+					Pos:   diags.NewPositionRange(contentLines, entry.val, 1),
+				}
+				continue
+			}
 			if entry.key.Value != "groups" {
-				return nil, ParseError{
+				return nil, nil, ParseError{
 					Line: entry.key.Line,
 					Err:  fmt.Errorf("unexpected key %s", entry.key.Value),
 				}
 			}
-			if !isTag(entry.val.ShortTag(), seqTag) {
-				return nil, ParseError{
-					Line: entry.key.Line,
-					Err:  fmt.Errorf("groups value must be a %s, got %s", describeTag(seqTag), describeTag(entry.val.ShortTag())),
-				}
+			g, err := p.parseGroupList(entry, names, offsetLine, offsetColumn, contentLines)
+			if err.Err != nil {
+				return nil, nil, err
 			}
-			for _, group := range unpackNodes(entry.val) {
-				g := p.parseGroup(group, offsetLine, offsetColumn, contentLines)
-				if _, ok := names[g.Name.Value]; ok {
-					return nil, ParseError{
-						Line: group.Line,
-						Err:  errors.New("duplicated group name"),
-						Diagnostics: []diags.Diagnostic{
-							{
-								Message:     "duplicated group name",
-								Pos:         g.Name.Pos,
-								Expr:        nil,
-								FirstColumn: 1,
-								LastColumn:  g.Name.Pos.Len(),
-								Kind:        diags.Issue,
-							},
-						},
-					}
-				}
-				names[g.Name.Value] = struct{}{}
-				groups = append(groups, g)
+			groups = append(groups, g...)
+		}
+	}
+	return groups, namespace, ParseError{}
+}
+
+// parseGroupList parses the value of the groups key.
+// Names of all parsed groups are added to names, so we can detect duplicates.
+func (p Parser) parseGroupList(entry yamlMap, names map[string]struct{}, offsetLine, offsetColumn int, contentLines []string) (groups []Group, _ ParseError) {
+	if !isTag(entry.val.ShortTag(), seqTag) {
+		return nil, ParseError{
+			Line: entry.key.Line,
+			Err:  fmt.Errorf("groups value must be a %s, got %s", describeTag(seqTag), describeTag(entry.val.ShortTag())),
+		}
+	}
+	for _, group := range unpackNodes(entry.val) {
+		g := p.parseGroup(group, offsetLine, offsetColumn, contentLines)
+		if _, ok := names[g.Name.Value]; ok {
+			return nil, ParseError{
+				Line: group.Line,
+				Err:  errors.New("duplicated group name"),
+				Diagnostics: []diags.Diagnostic{
+					{
+						Message:     "duplicated group name",
+						Pos:         g.Name.Pos,
+						Expr:        nil,
+						FirstColumn: 1,
+						LastColumn:  g.Name.Pos.Len(),
+						Kind:        diags.Issue,
+					},
+				},
 			}
 		}
+		names[g.Name.Value] = struct{}{}
+		groups = append(groups, g)
 	}
 	return groups, ParseError{}
 }
@@ -206,6 +237,81 @@ func (p Parser) parseGroup(node *yaml.Node, offsetLine, offsetColumn int, conten
 			for _, rule := range unpackNodes(entry.val) {
 				group.Rules = append(group.Rules, p.parseRuleStrict(rule, contentLines))
 			}
+		case "source_tenants":
+			if p.opts.Schema != MimirSchema {
+				group.Error = ParseError{
+					Line: entry.key.Line,
+					Err:  errors.New("source_tenants is only valid when parser is configured to use the Mimir rule schema"),
+				}
+				return group
+			}
+			resolved := resolveNode(entry.val)
+			if !isTag(resolved.ShortTag(), seqTag) {
+				group.Error = ParseError{
+					Line: entry.key.Line,
+					Err:  fmt.Errorf("source_tenants must be a %s, got %s", describeTag(seqTag), describeTag(resolved.ShortTag())),
+				}
+				return group
+			}
+			for _, tenant := range unpackNodes(resolved) {
+				tr := resolveNode(tenant)
+				if tr.Kind != yaml.ScalarNode || tr.ShortTag() != strTag {
+					group.Error = ParseError{
+						Line: tenant.Line,
+						Err:  fmt.Errorf("source_tenants entry must be a %s, got %s", describeTag(strTag), describeTag(tr.ShortTag())),
+					}
+					return group
+				}
+				if nodeValue(tenant) == "" {
+					group.Error = ParseError{
+						Line: tenant.Line,
+						Err:  errors.New("source_tenants entry cannot be empty"),
+					}
+					return group
+				}
+			}
+			group.SourceTenants = newYamlNodeList(resolved, offsetLine, offsetColumn, contentLines)
+		case "evaluation_delay":
+			if p.opts.Schema != MimirSchema {
+				group.Error = ParseError{
+					Line: entry.key.Line,
+					Err:  errors.New("evaluation_delay is only valid when parser is configured to use the Mimir rule schema"),
+				}
+				return group
+			}
+			resolved := resolveNode(entry.val)
+			if resolved.Kind != yaml.ScalarNode || resolved.ShortTag() != strTag {
+				group.Error = ParseError{
+					Line: entry.key.Line,
+					Err:  fmt.Errorf("group %s must be a %s, got %s", entry.key.Value, describeTag(strTag), describeTag(resolved.ShortTag())),
+				}
+				return group
+			}
+			group.EvaluationDelay = newYamlDuration(entry.val, offsetLine, offsetColumn, contentLines, 1)
+			if group.EvaluationDelay.ParseError != nil {
+				group.Error = ParseError{
+					Line: entry.key.Line,
+					Err:  fmt.Errorf("invalid %s value: %w", entry.key.Value, group.EvaluationDelay.ParseError),
+				}
+				return group
+			}
+		case "align_evaluation_time_on_interval":
+			if p.opts.Schema != MimirSchema {
+				group.Error = ParseError{
+					Line: entry.key.Line,
+					Err:  errors.New("align_evaluation_time_on_interval is only valid when parser is configured to use the Mimir rule schema"),
+				}
+				return group
+			}
+			resolved := resolveNode(entry.val)
+			if resolved.Kind != yaml.ScalarNode || resolved.ShortTag() != boolTag {
+				group.Error = ParseError{
+					Line: entry.key.Line,
+					Err:  fmt.Errorf("group %s must be a %s, got %s", entry.key.Value, describeTag(boolTag), describeTag(resolved.ShortTag())),
+				}
+				return group
+			}
+			group.AlignEvaluationTimeOnInterval = newYamlBool(entry.val, offsetLine, offsetColumn, contentLines, 1)
 		case "partial_response_strategy":
 			if p.opts.Schema != ThanosSchema {
 				group.Error = ParseError{
@@ -0,0 +1,284 @@
+package promapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+// batchLabel is the label used to tag results of each query merged into a batch,
+// so we can tell which series belongs to which query.
+const batchLabel = "__pint_batch__"
+
+// errBatchFailed is sent to all callers waiting for queries from a failed batch,
+// telling them to re-run their query on its own.
+var errBatchFailed = errors.New("batched query failed")
+
+type batchedQuery struct {
+	ctx    context.Context
+	result chan queryResult
+	expr   string
+}
+
+type queryBatcher struct {
+	pending []*batchedQuery
+	mtx     sync.Mutex
+}
+
+func (qb *queryBatcher) add(q *batchedQuery) {
+	qb.mtx.Lock()
+	qb.pending = append(qb.pending, q)
+	qb.mtx.Unlock()
+}
+
+func (qb *queryBatcher) remove(q *batchedQuery) bool {
+	qb.mtx.Lock()
+	defer qb.mtx.Unlock()
+	idx := slices.Index(qb.pending, q)
+	if idx < 0 {
+		return false
+	}
+	qb.pending = slices.Delete(qb.pending, idx, idx+1)
+	return true
+}
+
+func (qb *queryBatcher) isPending(q *batchedQuery) bool {
+	qb.mtx.Lock()
+	defer qb.mtx.Unlock()
+	return slices.Contains(qb.pending, q)
+}
+
+func (qb *queryBatcher) take(limit int) []*batchedQuery {
+	qb.mtx.Lock()
+	defer qb.mtx.Unlock()
+	n := min(limit, len(qb.pending))
+	batch := slices.Clone(qb.pending[:n])
+	qb.pending = slices.Delete(qb.pending, 0, n)
+	return batch
+}
+
+// isBatchable returns true if given query can be merged with other queries.
+// We only merge aggregations that always return an instant vector without
+// any metric name. Aggregations that select some of the input series, like
+// topk(), keep all input labels, including the metric name, so these are
+// never merged.
+func isBatchable(expr string) bool {
+	if strings.Contains(expr, batchLabel) {
+		return false
+	}
+	node, err := parser.PromQLParser.ParseExpr(expr)
+	if err != nil {
+		return false
+	}
+	for {
+		pe, ok := node.(*promParser.ParenExpr)
+		if !ok {
+			break
+		}
+		node = pe.Expr
+	}
+	aggr, ok := node.(*promParser.AggregateExpr)
+	if !ok {
+		return false
+	}
+	switch aggr.Op {
+	case promParser.TOPK, promParser.BOTTOMK, promParser.LIMITK, promParser.LIMIT_RATIO:
+		return false
+	}
+	return true
+}
+
+func mergeBatch(batch []*batchedQuery) string {
+	parts := make([]string, 0, len(batch))
+	for i, q := range batch {
+		parts = append(parts, fmt.Sprintf(`label_replace(%s, %q, "%d", "", "")`, q.expr, batchLabel, i))
+	}
+	return strings.Join(parts, " or ")
+}
+
+func splitBatch(size int, samples []Sample) [][]Sample {
+	results := make([][]Sample, size)
+	for i := range results {
+		results[i] = []Sample{}
+	}
+	for _, s := range samples {
+		idx, err := strconv.Atoi(s.Labels.Get(batchLabel))
+		if err != nil || idx < 0 || idx >= size {
+			continue
+		}
+		results[idx] = append(results[idx], Sample{
+			Labels: labels.NewBuilder(s.Labels).Del(batchLabel).Labels(),
+			Value:  s.Value,
+		})
+	}
+	return results
+}
+
+func (prom *Prometheus) batchCacheKey(expr string) uint64 {
+	return hash(prom.unsafeURI, APIPathQuery, batchLabel, expr)
+}
+
+// runBatch runs all given queries as a single request.
+// Every caller waiting for a query from this batch will get the result from here,
+// so the merged query runs with a detached context, request timeout is still
+// applied to it. Queries that were cancelled while waiting are skipped.
+// If the merged query fails then all queries are sent back to their callers
+// to be re-run on their own.
+func (prom *Prometheus) runBatch(batch []*batchedQuery) {
+	active := make([]*batchedQuery, 0, len(batch))
+	for _, q := range batch {
+		if err := q.ctx.Err(); err != nil {
+			q.result <- queryResult{err: err} // nolint: exhaustruct
+			continue
+		}
+		active = append(active, q)
+	}
+
+	switch len(active) {
+	case 0:
+		return
+	case 1:
+		active[0].result <- processJob(prom, instantQuery{
+			prom:      prom,
+			ctx:       active[0].ctx,
+			expr:      active[0].expr,
+			timestamp: time.Now(),
+		})
+		return
+	}
+
+	ctx := context.WithoutCancel(active[0].ctx)
+	slog.LogAttrs(
+		ctx, slog.LevelDebug,
+		"Running batched prometheus queries",
+		slog.String("uri", prom.safeURI),
+		slog.Int("queries", len(active)),
+	)
+	prometheusBatchedQueriesTotal.WithLabelValues(prom.name).Add(float64(len(active)))
+
+	result := processJob(prom, instantQuery{
+		prom:      prom,
+		ctx:       ctx,
+		expr:      mergeBatch(active),
+		timestamp: time.Now(),
+	})
+	if result.err != nil {
+		// A single broken query will fail the whole batch, so re-run each
+		// query on its own to give every caller the correct result or error.
+		// Each caller will wait for a free concurrency slot using its own context.
+		for _, q := range active {
+			q.result <- queryResult{err: errBatchFailed} // nolint: exhaustruct
+		}
+		return
+	}
+
+	for i, series := range splitBatch(len(active), result.value.([]Sample)) {
+		qr := queryResult{value: series} // nolint: exhaustruct
+		if prom.cache != nil {
+			prom.cache.set(prom.batchCacheKey(active[i].expr), qr, instantQuery{}.CacheTTL()) // nolint: exhaustruct
+		}
+		active[i].result <- qr
+	}
+}
+
+func (prom *Prometheus) cachedBatchQuery(expr string) (queryResult, bool) {
+	if prom.cache == nil {
+		return queryResult{}, false
+	}
+	for _, key := range []uint64{
+		prom.batchCacheKey(expr),
+		instantQuery{prom: prom, expr: expr}.CacheKey(), // nolint: exhaustruct
+	} {
+		if cached, ok := prom.cache.get(key, APIPathQuery); ok {
+			return cached.(queryResult), true
+		}
+	}
+	return queryResult{}, false
+}
+
+// BatchQuery works just like Query but allows pint to merge this query with other
+// pending queries into a single request.
+// Queries are only merged when all concurrency slots are busy, so batching never delays
+// any query, it only reduces the number of requests waiting for a free slot.
+// Results of batched queries have no query stats.
+func (prom *Prometheus) BatchQuery(ctx context.Context, expr string) (*QueryResult, error) {
+	// Queries for different tenants cannot be merged together.
+	if prom.batchSize < 2 || tenantsFromContext(ctx) != "" || !isBatchable(expr) {
+		return prom.Query(ctx, expr)
+	}
+
+	slog.LogAttrs(ctx, slog.LevelDebug, "Scheduling batched prometheus query", slog.String("uri", prom.safeURI), slog.String("query", expr))
+
+	key := APIPathQuery + expr
+	prom.locker.lock(key)
+	defer prom.locker.unlock(key)
+
+	result, ok := prom.cachedBatchQuery(expr)
+	if !ok {
+		result = prom.waitForBatch(ctx, expr)
+	}
+	if errors.Is(result.err, errBatchFailed) {
+		result = prom.runUnbatched(ctx, expr)
+	}
+	if result.err != nil {
+		return nil, QueryError{err: result.err, msg: decodeError(result.err)}
+	}
+
+	qr := QueryResult{
+		URI:    prom.publicURI,
+		Series: result.value.([]Sample),
+		Stats:  result.stats,
+	}
+	slog.LogAttrs(ctx, slog.LevelDebug, "Parsed response", slog.String("uri", prom.safeURI), slog.String("query", expr), slog.Int("series", len(qr.Series)))
+
+	return &qr, nil
+}
+
+func (prom *Prometheus) waitForBatch(ctx context.Context, expr string) queryResult {
+	q := &batchedQuery{ctx: ctx, expr: expr, result: make(chan queryResult, 1)}
+	prom.batcher.add(q)
+
+	for {
+		select {
+		case <-ctx.Done():
+			if prom.batcher.remove(q) {
+				return queryResult{err: ctx.Err()} // nolint: exhaustruct
+			}
+			// Our query is already running as part of some batch.
+			return <-q.result
+		case result := <-q.result:
+			return result
+		case prom.concurrencyLimit <- struct{}{}:
+			prom.runBatch(prom.batcher.take(prom.batchSize))
+			<-prom.concurrencyLimit
+			if !prom.batcher.isPending(q) {
+				// Our query was picked up by this or some other batch.
+				return <-q.result
+			}
+		}
+	}
+}
+
+func (prom *Prometheus) runUnbatched(ctx context.Context, expr string) queryResult {
+	result, err := prom.runQuery(ctx, instantQuery{
+		prom:      prom,
+		ctx:       ctx,
+		expr:      expr,
+		timestamp: time.Now(),
+	})
+	if err != nil {
+		result.err = err
+	}
+	return result
+}
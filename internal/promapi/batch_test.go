@@ -0,0 +1,284 @@
+package promapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+var batchPartRe = regexp.MustCompile(`label_replace\(count\((\w+)\), "__pint_batch__", "(\d+)", "", ""\)`)
+
+func batchTestServer(t *testing.T, started, release chan struct{}, onBatch func()) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		require.NoError(t, r.ParseForm())
+		query := r.Form.Get("query")
+
+		if query == "count(slow)" {
+			close(started)
+			<-release
+		}
+
+		if onBatch != nil && strings.Contains(query, "__pint_batch__") {
+			onBatch()
+		}
+
+		if strings.Contains(query, "broken") {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"broken query"}`))
+			return
+		}
+
+		results := []string{}
+		if parts := batchPartRe.FindAllStringSubmatch(query, -1); len(parts) > 0 {
+			for _, part := range parts {
+				results = append(results, fmt.Sprintf(
+					`{"metric":{"__pint_batch__":%q,"name":%q},"value":[1614859502.068,"%d"]}`,
+					part[2], part[1], len(part[1]),
+				))
+			}
+		} else {
+			name := strings.TrimSuffix(strings.TrimPrefix(query, "count("), ")")
+			results = append(results, fmt.Sprintf(
+				`{"metric":{"name":%q},"value":[1614859502.068,"%d"]}`,
+				name, len(name),
+			))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` + strings.Join(results, ",") + `]}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestBatchQuery(t *testing.T) {
+	type testCaseT struct {
+		errors    map[string]string
+		name      string
+		queries   []string
+		batchSize int
+		requests  int32
+	}
+
+	testCases := []testCaseT{
+		{
+			name:      "batching disabled",
+			batchSize: 0,
+			queries:   []string{"a", "bb", "ccc"},
+			requests:  4,
+		},
+		{
+			name:      "all queries in a single batch",
+			batchSize: 10,
+			queries:   []string{"a", "bb", "ccc", "dddd"},
+			requests:  2,
+		},
+		{
+			name:      "batch size limit",
+			batchSize: 2,
+			queries:   []string{"a", "bb", "ccc", "dddd"},
+			requests:  3,
+		},
+		{
+			name:      "broken query in a batch",
+			batchSize: 10,
+			queries:   []string{"a", "broken", "ccc"},
+			requests:  5,
+			errors: map[string]string{
+				"broken": "bad_data: broken query",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			started := make(chan struct{})
+			release := make(chan struct{})
+			srv, requests := batchTestServer(t, started, release, nil)
+
+			fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
+				promapi.NewPrometheus("test", srv.URL, "", nil, time.Second*5, 1, 100, tc.batchSize, nil),
+			}, true, "up", nil, nil, nil)
+			reg := prometheus.NewRegistry()
+			fg.StartWorkers(reg)
+			defer fg.Close(reg)
+
+			// Block the only concurrency slot so all other queries have to wait.
+			slow := fg.BatchQuery(t.Context(), "count(slow)")
+			<-started
+
+			var wg sync.WaitGroup
+			pending := make([]*promapi.Request[*promapi.QueryResult], len(tc.queries))
+			for i, name := range tc.queries {
+				wg.Go(func() {
+					pending[i] = fg.BatchQuery(t.Context(), "count("+name+")")
+				})
+			}
+			wg.Wait()
+			time.Sleep(time.Millisecond * 200)
+			close(release)
+
+			_, err := slow.Wait()
+			require.NoError(t, err)
+
+			for i, name := range tc.queries {
+				qr, err := pending[i].Wait()
+				if msg, ok := tc.errors[name]; ok {
+					require.EqualError(t, err, msg, name)
+					continue
+				}
+				require.NoError(t, err, name)
+				require.Equal(t, []promapi.Sample{
+					{Labels: labels.FromStrings("name", name), Value: float64(len(name))},
+				}, qr.Series, name)
+			}
+			require.Equal(t, tc.requests, requests.Load())
+		})
+	}
+}
+
+func TestBatchQueryNotBatchable(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv, requests := batchTestServer(t, started, release, nil)
+	close(release)
+
+	fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
+		promapi.NewPrometheus("test", srv.URL, "", nil, time.Second*5, 1, 100, 10, nil),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	defer fg.Close(reg)
+
+	qr, err := fg.BatchQuery(t.Context(), "count(foo").Wait()
+	require.NoError(t, err)
+	require.Equal(t, []promapi.Sample{
+		{Labels: labels.FromStrings("name", "foo"), Value: 3},
+	}, qr.Series)
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestBatchQuerySelectingAggregations(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv, requests := batchTestServer(t, started, release, func() {
+		t.Error("queries selecting input series must not be batched")
+	})
+
+	fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
+		promapi.NewPrometheus("test", srv.URL, "", nil, time.Second*5, 1, 100, 10, nil),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	defer fg.Close(reg)
+
+	// Block the only concurrency slot so all other queries have to wait.
+	slow := fg.BatchQuery(t.Context(), "count(slow)")
+	<-started
+
+	first := fg.BatchQuery(t.Context(), "topk(1, foo)")
+	second := fg.BatchQuery(t.Context(), "topk(1, bar)")
+	time.Sleep(time.Millisecond * 200)
+	close(release)
+
+	_, err := slow.Wait()
+	require.NoError(t, err)
+	_, err = first.Wait()
+	require.NoError(t, err)
+	_, err = second.Wait()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestBatchQueryCancelled(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	batchStarted := make(chan struct{})
+	batchRelease := make(chan struct{})
+	srv, _ := batchTestServer(t, started, release, func() {
+		close(batchStarted)
+		<-batchRelease
+	})
+
+	fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
+		promapi.NewPrometheus("test", srv.URL, "", nil, time.Second*5, 1, 100, 20, nil),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	defer fg.Close(reg)
+
+	// Block the only concurrency slot so all other queries have to wait.
+	slow := fg.BatchQuery(t.Context(), "count(slow)")
+	<-started
+
+	// Most queries use a context that will be cancelled while the batch is running,
+	// so the batch is most likely started by a caller with a cancelled context.
+	ctx, cancel := context.WithCancel(t.Context())
+	live := fg.BatchQuery(t.Context(), "count(live)")
+	cancelled := make([]*promapi.Request[*promapi.QueryResult], 10)
+	for i := range cancelled {
+		cancelled[i] = fg.BatchQuery(ctx, fmt.Sprintf("count(c%d)", i))
+	}
+	time.Sleep(time.Millisecond * 200)
+	close(release)
+
+	<-batchStarted
+	cancel()
+	close(batchRelease)
+
+	_, err := slow.Wait()
+	require.NoError(t, err)
+
+	qr, err := live.Wait()
+	require.NoError(t, err)
+	require.Equal(t, []promapi.Sample{
+		{Labels: labels.FromStrings("name", "live"), Value: 4},
+	}, qr.Series)
+
+	for _, req := range cancelled {
+		_, _ = req.Wait()
+	}
+}
+
+func TestBatchQueryCancelledBeforeRun(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv, requests := batchTestServer(t, started, release, nil)
+
+	fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
+		promapi.NewPrometheus("test", srv.URL, "", nil, time.Second*5, 1, 100, 20, nil),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	defer fg.Close(reg)
+
+	slow := fg.BatchQuery(t.Context(), "count(slow)")
+	<-started
+
+	ctx, cancel := context.WithCancel(t.Context())
+	req := fg.BatchQuery(ctx, "count(foo)")
+	time.Sleep(time.Millisecond * 100)
+	cancel()
+
+	_, err := req.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	_, err = slow.Wait()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load())
+}
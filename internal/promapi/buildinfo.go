@@ -69,7 +69,7 @@ func (q buildInfoQuery) String() string {
 }
 
 func (q buildInfoQuery) CacheKey() uint64 {
-	return hash(q.prom.unsafeURI, q.Endpoint())
+	return hash(q.prom.unsafeURI, q.Endpoint(), tenantsFromContext(q.ctx))
 }
 
 func (q buildInfoQuery) CacheTTL() time.Duration {
@@ -8,10 +8,15 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
 	"time"
 
 	"github.com/go-json-experiment/json"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
 	"go.yaml.in/yaml/v3"
 )
 
@@ -24,11 +29,100 @@ type ConfigSectionGlobal struct {
 	ScrapeInterval     time.Duration     `yaml:"scrape_interval"`
 	ScrapeTimeout      time.Duration     `yaml:"scrape_timeout"`
 	EvaluationInterval time.Duration     `yaml:"evaluation_interval"`
+	RuleQueryOffset    time.Duration     `yaml:"rule_query_offset"`
+}
+
+type ConfigSectionRelabel struct {
+	TargetLabel string `yaml:"target_label"`
+	Action      string `yaml:"action"`
+}
+
+type ConfigSectionScrape struct {
+	Params               map[string][]string    `yaml:"params"`
+	JobName              string                 `yaml:"job_name"`
+	MetricsPath          string                 `yaml:"metrics_path"`
+	Scheme               string                 `yaml:"scheme"`
+	RelabelConfigs       []ConfigSectionRelabel `yaml:"relabel_configs"`
+	MetricRelabelConfigs []ConfigSectionRelabel `yaml:"metric_relabel_configs"`
+	ScrapeInterval       time.Duration          `yaml:"scrape_interval"`
+	ScrapeTimeout        time.Duration          `yaml:"scrape_timeout"`
+	SampleLimit          int                    `yaml:"sample_limit"`
+	HonorLabels          bool                   `yaml:"honor_labels"`
+	HonorTimestamps      *bool                  `yaml:"honor_timestamps"`
+}
+
+// CanSetJobLabel returns true if this scrape job can produce series with
+// a job label value different from its job_name.
+func (sc ConfigSectionScrape) CanSetJobLabel() bool {
+	if sc.HonorLabels {
+		return true
+	}
+	for _, rc := range slices.Concat(sc.RelabelConfigs, sc.MetricRelabelConfigs) {
+		if rc.TargetLabel == "job" || rc.Action == "labelmap" {
+			return true
+		}
+	}
+	return false
+}
+
+type ConfigSectionAlerting struct {
+	AlertRelabelConfigs []*relabel.Config `yaml:"alert_relabel_configs"`
 }
 
 type PrometheusConfig struct {
-	RuleFiles []string            `yaml:"rule_files"`
-	Global    ConfigSectionGlobal `yaml:"global"`
+	RuleFiles         []string              `yaml:"rule_files"`
+	ScrapeConfigFiles []string              `yaml:"scrape_config_files"`
+	ScrapeConfigs     []ConfigSectionScrape `yaml:"scrape_configs"`
+	Alerting          ConfigSectionAlerting `yaml:"alerting"`
+	Global            ConfigSectionGlobal   `yaml:"global"`
+}
+
+// AlertLabels applies alert_relabel_configs to given labels, the result is the
+// set of labels Alertmanager will receive.
+// It returns false if alerts with these labels are dropped and never sent.
+func (cfg PrometheusConfig) AlertLabels(lset labels.Labels) (labels.Labels, bool) {
+	rcs := make([]*relabel.Config, 0, len(cfg.Alerting.AlertRelabelConfigs))
+	for _, rc := range cfg.Alerting.AlertRelabelConfigs {
+		if rc.NameValidationScheme == model.UnsetValidation {
+			rc = &relabel.Config{
+				SourceLabels:         rc.SourceLabels,
+				Separator:            rc.Separator,
+				Regex:                rc.Regex,
+				Modulus:              rc.Modulus,
+				TargetLabel:          rc.TargetLabel,
+				Replacement:          rc.Replacement,
+				Action:               rc.Action,
+				NameValidationScheme: model.UTF8Validation,
+			}
+		}
+		rcs = append(rcs, rc)
+	}
+	lb := labels.NewBuilder(lset)
+	if !relabel.ProcessBuilder(lb, rcs...) {
+		return labels.EmptyLabels(), false
+	}
+	return lb.Labels(), true
+}
+
+// HasCustomScrapeIntervals returns true if any scrape job is using a different
+// scrape_interval than the global one.
+func (cfg PrometheusConfig) HasCustomScrapeIntervals() bool {
+	for _, sc := range cfg.ScrapeConfigs {
+		if sc.ScrapeInterval != cfg.Global.ScrapeInterval {
+			return true
+		}
+	}
+	return false
+}
+
+// ScrapeJob returns the scrape configuration for the job with given name.
+func (cfg PrometheusConfig) ScrapeJob(name string) (ConfigSectionScrape, bool) {
+	for _, sc := range cfg.ScrapeConfigs {
+		if sc.JobName == name {
+			return sc, true
+		}
+	}
+	return ConfigSectionScrape{}, false
 }
 
 type PrometheusConfigResponse struct {
@@ -38,6 +132,7 @@ type PrometheusConfigResponse struct {
 
 type ConfigResult struct {
 	URI    string
+	Path   string // Path of the local configuration file, empty if configuration was read from Prometheus API.
 	Config PrometheusConfig
 }
 
@@ -86,7 +181,7 @@ func (q configQuery) String() string {
 }
 
 func (q configQuery) CacheKey() uint64 {
-	return hash(q.prom.unsafeURI, q.Endpoint())
+	return hash(q.prom.unsafeURI, q.Endpoint(), tenantsFromContext(q.ctx))
 }
 
 func (q configQuery) CacheTTL() time.Duration {
@@ -147,7 +242,25 @@ func parseConfig(r io.Reader) (cfg PrometheusConfig, err error) {
 	if err = yaml.Unmarshal([]byte(data.Data.YAML), &cfg); err != nil {
 		return cfg, err
 	}
+	cfg.applyDefaults()
+
+	return cfg, nil
+}
+
+// LoadConfig reads Prometheus configuration from a local file.
+func LoadConfig(path string) (cfg PrometheusConfig, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err = yaml.Unmarshal(content, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	cfg.applyDefaults()
+	return cfg, nil
+}
 
+func (cfg *PrometheusConfig) applyDefaults() {
 	if cfg.Global.ScrapeInterval == 0 {
 		cfg.Global.ScrapeInterval = time.Minute
 	}
@@ -157,6 +270,18 @@ func parseConfig(r io.Reader) (cfg PrometheusConfig, err error) {
 	if cfg.Global.EvaluationInterval == 0 {
 		cfg.Global.EvaluationInterval = time.Minute
 	}
-
-	return cfg, nil
+	for i := range cfg.ScrapeConfigs {
+		if cfg.ScrapeConfigs[i].ScrapeInterval == 0 {
+			cfg.ScrapeConfigs[i].ScrapeInterval = cfg.Global.ScrapeInterval
+		}
+		if cfg.ScrapeConfigs[i].ScrapeTimeout == 0 {
+			cfg.ScrapeConfigs[i].ScrapeTimeout = min(cfg.Global.ScrapeTimeout, cfg.ScrapeConfigs[i].ScrapeInterval)
+		}
+		if cfg.ScrapeConfigs[i].MetricsPath == "" {
+			cfg.ScrapeConfigs[i].MetricsPath = "/metrics"
+		}
+		if cfg.ScrapeConfigs[i].Scheme == "" {
+			cfg.ScrapeConfigs[i].Scheme = "http"
+		}
+	}
 }
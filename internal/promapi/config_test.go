@@ -4,11 +4,15 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.nhat.io/httpmock"
@@ -77,6 +81,44 @@ func TestConfig(t *testing.T) {
 					UnlimitedTimes()
 			}),
 		},
+		{
+			name:    "scrape_configs",
+			timeout: time.Second,
+			cfg: promapi.PrometheusConfig{
+				Global: promapi.ConfigSectionGlobal{
+					ScrapeInterval:     time.Second * 30,
+					ScrapeTimeout:      time.Second * 10,
+					EvaluationInterval: time.Minute,
+					ExternalLabels:     nil,
+				},
+				ScrapeConfigs: []promapi.ConfigSectionScrape{
+					{
+						JobName:        "foo",
+						MetricsPath:    "/metrics",
+						Scheme:         "http",
+						ScrapeInterval: time.Second * 30,
+						ScrapeTimeout:  time.Second * 10,
+					},
+					{
+						JobName:        "bar",
+						MetricsPath:    "/federate",
+						Scheme:         "https",
+						Params:         map[string][]string{"match[]": {"up"}},
+						ScrapeInterval: time.Second * 5,
+						ScrapeTimeout:  time.Second * 5,
+						SampleLimit:    1000,
+						HonorLabels:    true,
+						RelabelConfigs: []promapi.ConfigSectionRelabel{{TargetLabel: "job", Action: "replace"}},
+					},
+				},
+			},
+			mock: httpmock.New(func(s *httpmock.Server) {
+				s.ExpectGet(promapi.APIPathConfig).
+					ReturnHeader("Content-Type", "application/json").
+					Return(`{"status":"success","data":{"yaml":"global:\n  scrape_interval: 30s\nscrape_configs:\n- job_name: foo\n- job_name: bar\n  scrape_interval: 5s\n  metrics_path: /federate\n  scheme: https\n  params:\n    match[]: [up]\n  sample_limit: 1000\n  honor_labels: true\n  relabel_configs:\n  - target_label: job\n    action: replace\n"}}`).
+					UnlimitedTimes()
+			}),
+		},
 		{
 			name:    "connection timeout",
 			timeout: time.Millisecond * 10,
@@ -199,7 +241,7 @@ func TestConfig(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			srv := tc.mock(t)
 
-			prom := promapi.NewPrometheus("test", srv.URL(), "", nil, tc.timeout, 1, 100, nil)
+			prom := promapi.NewPrometheus("test", srv.URL(), "", nil, tc.timeout, 1, 100, 0, nil)
 
 			var cfg *promapi.ConfigResult
 			var err error
@@ -270,7 +312,7 @@ func TestConfigHeaders(t *testing.T) {
 			t.Cleanup(srv.Close)
 
 			fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
-				promapi.NewPrometheus("test", srv.URL, "", tc.config, time.Second, 1, 100, nil),
+				promapi.NewPrometheus("test", srv.URL, "", tc.config, time.Second, 1, 100, 0, nil),
 			}, true, "up", nil, nil, nil)
 
 			reg := prometheus.NewRegistry()
@@ -282,3 +324,82 @@ func TestConfigHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigAlertLabels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prometheus.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+global:
+  external_labels:
+    cluster: a
+    replica: "1"
+alerting:
+  alert_relabel_configs:
+  - action: labeldrop
+    regex: replica
+  - action: drop
+    source_labels: [cluster]
+    regex: b
+`), 0o644))
+
+	cfg, err := promapi.LoadConfig(path)
+	require.NoError(t, err)
+	require.Len(t, cfg.Alerting.AlertRelabelConfigs, 2)
+
+	lset, ok := cfg.AlertLabels(labels.FromMap(cfg.Global.ExternalLabels))
+	require.True(t, ok)
+	require.Equal(t, labels.FromStrings("cluster", "a"), lset)
+
+	_, ok = cfg.AlertLabels(labels.FromStrings("cluster", "b", "replica", "2"))
+	require.False(t, ok)
+
+	lset, ok = promapi.PrometheusConfig{}.AlertLabels(labels.FromStrings("cluster", "b"))
+	require.True(t, ok)
+	require.Equal(t, labels.FromStrings("cluster", "b"), lset)
+}
+
+func TestCacheKeyTenants(t *testing.T) {
+	var lock sync.Mutex
+	requests := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests[r.URL.Path+" "+r.Header.Get(promapi.TenantHeader)]++
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Path {
+		case promapi.APIPathConfig:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"yaml":"global:\n  scrape_interval: 30s\n"}}`))
+		case promapi.APIPathFlags:
+			_, _ = w.Write([]byte(`{"status":"success","data":{}}`))
+		case promapi.APIPathTSDB:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"headStats":{}}}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	fg := promapi.NewFailoverGroup("test", srv.URL, []*promapi.Prometheus{
+		promapi.NewPrometheus("test", srv.URL, "", nil, time.Second, 1, 100, 0, nil),
+	}, true, "up", nil, nil, nil)
+	reg := prometheus.NewRegistry()
+	fg.StartWorkers(reg)
+	defer fg.Close(reg)
+
+	for _, tenants := range [][]string{{"team-a"}, {"team-b", "team-a"}, {"team-a"}, {"team-a", "team-b"}} {
+		ctx := promapi.WithTenants(t.Context(), tenants)
+		_, err := fg.Config(ctx, time.Minute).Wait()
+		require.NoError(t, err)
+		_, err = fg.Flags(ctx).Wait()
+		require.NoError(t, err)
+		_, err = fg.TSDB(ctx).Wait()
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, map[string]int{
+		promapi.APIPathConfig + " team-a":        1,
+		promapi.APIPathConfig + " team-a|team-b": 1,
+		promapi.APIPathFlags + " team-a":         1,
+		promapi.APIPathFlags + " team-a|team-b":  1,
+		promapi.APIPathTSDB + " team-a":          1,
+		promapi.APIPathTSDB + " team-a|team-b":   1,
+	}, requests)
+}
@@ -133,6 +133,8 @@ func tryDecodingAPIError(resp *http.Response) error {
 				apiPath = APIPathMetadata
 			case strings.HasSuffix(resp.Request.URL.Path, APIPathBuildInfo):
 				apiPath = APIPathBuildInfo
+			case strings.HasSuffix(resp.Request.URL.Path, APIPathTSDB):
+				apiPath = APIPathTSDB
 			}
 			msg = "`" + apiPath + "` API endpoint"
 		}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"maps"
+	"reflect"
 	"regexp"
 	"slices"
 	"sync"
@@ -66,7 +68,7 @@ func (dc *disabledChecks) disable(api, check string) {
 func (dc *disabledChecks) read() map[string][]string {
 	dc.mtx.Lock()
 	defer dc.mtx.Unlock()
-	return dc.apis
+	return maps.Clone(dc.apis)
 }
 
 type FailoverGroup struct {
@@ -76,8 +78,10 @@ type FailoverGroup struct {
 	uri            string
 	servers        []*Prometheus
 	uptimeMetric   string
+	source         string
 	cacheCollector *cacheCollector
 	quitChan       chan bool
+	localConfig    *ConfigResult
 
 	pathsInclude []*regexp.Regexp
 	pathsExclude []*regexp.Regexp
@@ -140,11 +144,45 @@ func (fg *FailoverGroup) UptimeMetric() string {
 	return fg.uptimeMetric
 }
 
+// Upstreams returns URIs of all Prometheus servers in this group, with any credentials removed.
+func (fg *FailoverGroup) Upstreams() []string {
+	uris := make([]string, 0, len(fg.servers))
+	for _, prom := range fg.servers {
+		uris = append(uris, prom.SafeURI())
+	}
+	return uris
+}
+
 func (fg *FailoverGroup) ServerCount() int {
 	return len(fg.servers)
 }
 
+// SetLocalConfig sets the configuration read from a local file,
+// it will be returned by Config() instead of querying Prometheus API.
+func (fg *FailoverGroup) SetLocalConfig(cfg *ConfigResult) {
+	fg.localConfig = cfg
+}
+
+func (fg *FailoverGroup) HasLocalConfig() bool {
+	return fg.localConfig != nil
+}
+
+// SetSource records the configuration this group was created from.
+func (fg *FailoverGroup) SetSource(src string) {
+	fg.source = src
+}
+
+// IsSameAs returns true if both groups were created from the same configuration,
+// so one can be used instead of the other.
+func (fg *FailoverGroup) IsSameAs(other *FailoverGroup) bool {
+	return fg.source != "" &&
+		fg.name == other.name &&
+		fg.source == other.source &&
+		reflect.DeepEqual(fg.localConfig, other.localConfig)
+}
+
 func (fg *FailoverGroup) MergeUpstreams(src *FailoverGroup) {
+	fg.source += "\n" + src.source
 	for _, ns := range src.servers {
 		var present bool
 		for _, ol := range fg.servers {
@@ -241,6 +279,10 @@ func (fg *FailoverGroup) Config(
 	cacheTTL time.Duration,
 ) *Request[*ConfigResult] {
 	return newRequest(func() (*ConfigResult, error) {
+		if fg.localConfig != nil {
+			return fg.localConfig, nil
+		}
+
 		var cfg *ConfigResult
 		var uri string
 		var err error
@@ -263,32 +305,52 @@ func (fg *FailoverGroup) Query(
 	expr string,
 ) *Request[*QueryResult] {
 	return newRequest(func() (*QueryResult, error) {
-		var qr *QueryResult
-		var uri string
-		var err error
-		for try, prom := range fg.servers {
-			if try > 0 {
-				slog.LogAttrs(
-					ctx, slog.LevelDebug,
-					"Using failover URI",
-					slog.String("name", fg.name),
-					slog.Int("retry", try),
-					slog.String("uri", prom.safeURI),
-				)
-			}
-			uri = prom.safeURI
-			qr, err = prom.Query(ctx, expr)
-			if err == nil {
-				return qr, nil
-			}
-			if !IsUnavailableError(err) {
-				return qr, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
-			}
-		}
-		return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		return fg.query(ctx, expr, (*Prometheus).Query)
+	})
+}
+
+// BatchQuery is like Query but it allows merging this query with other
+// queries sent at the same time, see Prometheus.BatchQuery.
+// Use it only if you don't need query stats.
+func (fg *FailoverGroup) BatchQuery(
+	ctx context.Context,
+	expr string,
+) *Request[*QueryResult] {
+	return newRequest(func() (*QueryResult, error) {
+		return fg.query(ctx, expr, (*Prometheus).BatchQuery)
 	})
 }
 
+func (fg *FailoverGroup) query(
+	ctx context.Context,
+	expr string,
+	fn func(*Prometheus, context.Context, string) (*QueryResult, error),
+) (*QueryResult, error) {
+	var qr *QueryResult
+	var uri string
+	var err error
+	for try, prom := range fg.servers {
+		if try > 0 {
+			slog.LogAttrs(
+				ctx, slog.LevelDebug,
+				"Using failover URI",
+				slog.String("name", fg.name),
+				slog.Int("retry", try),
+				slog.String("uri", prom.safeURI),
+			)
+		}
+		uri = prom.safeURI
+		qr, err = fn(prom, ctx, expr)
+		if err == nil {
+			return qr, nil
+		}
+		if !IsUnavailableError(err) {
+			return qr, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		}
+	}
+	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+}
+
 func (fg *FailoverGroup) RangeQuery(
 	ctx context.Context,
 	expr string,
@@ -355,6 +417,27 @@ func (fg *FailoverGroup) Flags(
 	})
 }
 
+func (fg *FailoverGroup) TSDB(
+	ctx context.Context,
+) *Request[*TSDBResult] {
+	return newRequest(func() (*TSDBResult, error) {
+		var stats *TSDBResult
+		var uri string
+		var err error
+		for _, prom := range fg.servers {
+			uri = prom.safeURI
+			stats, err = prom.TSDB(ctx)
+			if err == nil {
+				return stats, nil
+			}
+			if !IsUnavailableError(err) && !errors.Is(err, ErrUnsupported) {
+				return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+			}
+		}
+		return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+	})
+}
+
 func (fg *FailoverGroup) BuildInfo(
 	ctx context.Context,
 ) *Request[*BuildInfoResult] {
@@ -69,7 +69,7 @@ func (q flagsQuery) String() string {
 }
 
 func (q flagsQuery) CacheKey() uint64 {
-	return hash(q.prom.unsafeURI, q.Endpoint())
+	return hash(q.prom.unsafeURI, q.Endpoint(), tenantsFromContext(q.ctx))
 }
 
 func (q flagsQuery) CacheTTL() time.Duration {
@@ -76,7 +76,7 @@ func (q metadataQuery) String() string {
 }
 
 func (q metadataQuery) CacheKey() uint64 {
-	return hash(q.prom.unsafeURI, q.Endpoint(), q.metric)
+	return hash(q.prom.unsafeURI, q.Endpoint(), q.metric, tenantsFromContext(q.ctx))
 }
 
 func (q metadataQuery) CacheTTL() time.Duration {
@@ -188,7 +188,7 @@ func TestMetadata(t *testing.T) {
 			srv := tc.mock(t)
 
 			fg := promapi.NewFailoverGroup("test", srv.URL(), []*promapi.Prometheus{
-				promapi.NewPrometheus("test", srv.URL(), "", nil, tc.timeout, 1, 100, nil),
+				promapi.NewPrometheus("test", srv.URL(), "", nil, tc.timeout, 1, 100, 0, nil),
 			}, true, "up", nil, nil, nil)
 			reg := prometheus.NewRegistry()
 			fg.StartWorkers(reg)
@@ -22,6 +22,13 @@ var (
 		},
 		[]string{"name", "endpoint"},
 	)
+	prometheusBatchedQueriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pint_prometheus_batched_queries_total",
+			Help: "Total number of prometheus queries that were merged with other queries into a single request.",
+		},
+		[]string{"name"},
+	)
 	prometheusQueryErrorsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "pint_prometheus_query_errors_total",
@@ -35,6 +42,7 @@ func RegisterMetrics(reg *prometheus.Registry) {
 	reg.MustRegister(prometheusQueriesRunning)
 	reg.MustRegister(prometheusQueriesTotal)
 	reg.MustRegister(prometheusQueryErrorsTotal)
+	reg.MustRegister(prometheusBatchedQueriesTotal)
 }
 
 func errReason(err error) string {
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,8 +25,35 @@ type PrometheusContextKey string
 
 const (
 	AllPrometheusServers = PrometheusContextKey("allServers")
+	tenantsContextKey    = PrometheusContextKey("tenants")
+
+	// TenantHeader is the HTTP header used by Mimir and Cortex to select the tenant.
+	TenantHeader = "X-Scope-OrgID"
 )
 
+// WithTenants returns a context that will make all queries send the
+// TenantHeader with the list of given tenants.
+// Mimir and Cortex will query all tenants at once if tenant federation is enabled.
+// Tenants are sorted, so the same list in a different order uses the same cache keys.
+func WithTenants(ctx context.Context, tenants []string) context.Context {
+	if len(tenants) == 0 {
+		return ctx
+	}
+	tenants = slices.Clone(tenants)
+	slices.Sort(tenants)
+	return context.WithValue(ctx, tenantsContextKey, strings.Join(tenants, "|"))
+}
+
+func tenantsFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if val, ok := ctx.Value(tenantsContextKey).(string); ok {
+		return val
+	}
+	return ""
+}
+
 type QueryError struct {
 	err error
 	msg string
@@ -91,6 +119,7 @@ type unsupporedAPIs struct {
 	noConfig   bool
 	noFlags    bool
 	noMetadata bool
+	noTSDB     bool
 }
 
 func (ua *unsupporedAPIs) isSupported(s string) bool {
@@ -103,6 +132,8 @@ func (ua *unsupporedAPIs) isSupported(s string) bool {
 		return !ua.noFlags
 	case APIPathMetadata:
 		return !ua.noMetadata
+	case APIPathTSDB:
+		return !ua.noTSDB
 	default:
 		return true
 	}
@@ -119,6 +150,8 @@ func (ua *unsupporedAPIs) disable(s string) {
 		ua.noFlags = true
 	case APIPathMetadata:
 		ua.noMetadata = true
+	case APIPathTSDB:
+		ua.noTSDB = true
 	}
 }
 
@@ -127,6 +160,7 @@ type Prometheus struct {
 	headers          map[string]string
 	cache            *queryCache
 	locker           *partitionLocker
+	batcher          *queryBatcher
 	apis             *unsupporedAPIs
 	concurrencyLimit chan struct{}
 	client           http.Client
@@ -136,9 +170,10 @@ type Prometheus struct {
 	publicURI        string // either set explicitly by user in the config or same as safeURI, this ends up as URI in query responses
 	timeout          time.Duration
 	concurrency      int
+	batchSize        int
 }
 
-func NewPrometheus(name, uri, publicURI string, headers map[string]string, timeout time.Duration, concurrency, rl int, tlsConf *tls.Config) *Prometheus {
+func NewPrometheus(name, uri, publicURI string, headers map[string]string, timeout time.Duration, concurrency, rl, batchSize int, tlsConf *tls.Config) *Prometheus {
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	if tlsConf != nil {
 		transport.TLSClientConfig = tlsConf
@@ -162,6 +197,8 @@ func NewPrometheus(name, uri, publicURI string, headers map[string]string, timeo
 		locker:      newPartitionLocker((&sync.Mutex{})),
 		rateLimiter: ratelimit.New(rl),
 		concurrency: concurrency,
+		batchSize:   batchSize,
+		batcher:     &queryBatcher{},   // nolint: exhaustruct
 		apis:        &unsupporedAPIs{}, // nolint: exhaustruct
 	}
 
@@ -223,6 +260,9 @@ func (prom *Prometheus) doRequest(ctx context.Context, method, path string, args
 	for k, v := range prom.headers {
 		req.Header.Set(k, v)
 	}
+	if tenants := tenantsFromContext(ctx); tenants != "" {
+		req.Header.Set(TenantHeader, tenants)
+	}
 
 	return prom.client.Do(req)
 }
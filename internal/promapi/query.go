@@ -163,7 +163,7 @@ func (q instantQuery) String() string {
 }
 
 func (q instantQuery) CacheKey() uint64 {
-	return hash(q.prom.unsafeURI, q.Endpoint(), q.expr)
+	return hash(q.prom.unsafeURI, q.Endpoint(), q.expr, tenantsFromContext(q.ctx))
 }
 
 func (q instantQuery) CacheTTL() time.Duration {
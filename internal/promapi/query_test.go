@@ -330,6 +330,24 @@ func TestQuery(t *testing.T) {
 					UnlimitedTimes()
 			}),
 		},
+		{
+			name:    "source tenants",
+			timeout: time.Second,
+			series:  []promapi.Sample{},
+			ctx: func(t *testing.T) context.Context {
+				return promapi.WithTenants(t.Context(), []string{"team-a", "team-b"})
+			},
+			assertErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			mock: httpmock.New(func(s *httpmock.Server) {
+				s.ExpectPost(promapi.APIPathQuery).
+					WithHeader(promapi.TenantHeader, "team-a|team-b").
+					ReturnHeader("Content-Type", "application/json").
+					Return(`{"status":"success","data":{"resultType":"vector","result":[]}}`).
+					UnlimitedTimes()
+			}),
+		},
 		{
 			name:    "context cancelled",
 			timeout: time.Second,
@@ -350,7 +368,7 @@ func TestQuery(t *testing.T) {
 			srv := tc.mock(t)
 
 			fg := promapi.NewFailoverGroup("test", srv.URL(), []*promapi.Prometheus{
-				promapi.NewPrometheus("test", srv.URL(), srv.URL(), nil, tc.timeout, 1, 100, nil),
+				promapi.NewPrometheus("test", srv.URL(), srv.URL(), nil, tc.timeout, 1, 100, 0, nil),
 			}, true, "up", nil, nil, nil)
 			reg := prometheus.NewRegistry()
 			fg.StartWorkers(reg)
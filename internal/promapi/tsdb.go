@@ -0,0 +1,142 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+const (
+	APIPathTSDB = "/api/v1/status/tsdb"
+
+	// Prometheus only returns top 10 entries for each TSDB stats list by default.
+	tsdbStatsLimit = 1000
+)
+
+type PrometheusTSDBResponse struct {
+	Data v1.TSDBResult `json:"data"`
+	PrometheusResponse
+}
+
+type TSDBResult struct {
+	URI   string
+	Stats v1.TSDBResult
+}
+
+// LabelValueCount returns the number of unique values for given label name
+// and true if this label was present in the response.
+func (tr TSDBResult) LabelValueCount(name string) (int, bool) {
+	for _, s := range tr.Stats.LabelValueCountByLabelName {
+		if s.Name == name {
+			return int(s.Value), true
+		}
+	}
+	return 0, false
+}
+
+type tsdbQuery struct {
+	prom      *Prometheus
+	ctx       context.Context
+	timestamp time.Time
+}
+
+func (q tsdbQuery) Run() queryResult {
+	slog.LogAttrs(q.ctx, slog.LevelDebug, "Getting prometheus TSDB stats", slog.String("uri", q.prom.safeURI))
+
+	ctx, cancel := q.prom.requestContext(q.ctx)
+	defer cancel()
+
+	var qr queryResult
+
+	args := url.Values{}
+	args.Set("limit", strconv.Itoa(tsdbStatsLimit))
+	resp, err := q.prom.doRequest(ctx, http.MethodGet, q.Endpoint(), args)
+	if err != nil {
+		qr.err = fmt.Errorf("failed to query Prometheus TSDB stats: %w", err)
+		return qr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		qr.err = tryDecodingAPIError(resp)
+		return qr
+	}
+
+	stats, err := parseTSDB(resp.Body)
+	qr.value, qr.err = stats, err
+	return qr
+}
+
+func (q tsdbQuery) Endpoint() string {
+	return APIPathTSDB
+}
+
+func (q tsdbQuery) String() string {
+	return APIPathTSDB
+}
+
+func (q tsdbQuery) CacheKey() uint64 {
+	return hash(q.prom.unsafeURI, q.Endpoint(), tenantsFromContext(q.ctx))
+}
+
+func (q tsdbQuery) CacheTTL() time.Duration {
+	return time.Minute * 10
+}
+
+func (prom *Prometheus) TSDB(ctx context.Context) (*TSDBResult, error) {
+	slog.LogAttrs(ctx, slog.LevelDebug, "Scheduling Prometheus TSDB stats query", slog.String("uri", prom.safeURI))
+
+	prom.locker.lock(APIPathTSDB)
+	defer prom.locker.unlock(APIPathTSDB)
+
+	result, err := prom.runQuery(ctx, tsdbQuery{
+		prom:      prom,
+		ctx:       ctx,
+		timestamp: time.Now(),
+	})
+	if err != nil {
+		return nil, QueryError{err: err, msg: decodeError(err)}
+	}
+
+	r := TSDBResult{
+		URI:   prom.publicURI,
+		Stats: result.value.(v1.TSDBResult),
+	}
+
+	return &r, nil
+}
+
+func parseTSDB(r io.Reader) (_ v1.TSDBResult, err error) {
+	defer dummyReadAll(r)
+
+	var data PrometheusTSDBResponse
+	if err = json.NewDecoder(r).Decode(&data); err != nil {
+		return data.Data, APIError{
+			Status:    data.Status,
+			ErrorType: v1.ErrBadResponse,
+			Err:       fmt.Errorf("JSON parse error: %w", err),
+		}
+	}
+
+	if data.Status != "success" {
+		if data.Error == "" {
+			data.Error = "empty response object"
+		}
+		return data.Data, APIError{
+			Status:    data.Status,
+			ErrorType: decodeErrorType(data.ErrorType),
+			Err:       errors.New(data.Error),
+		}
+	}
+
+	return data.Data, nil
+}